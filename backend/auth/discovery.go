@@ -0,0 +1,259 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/go-jose/go-jose/v4"
+)
+
+// ErrVerifierNotReady is returned while the identity provider has not been reached yet.
+var ErrVerifierNotReady = errors.New("token verification is not available yet")
+
+const (
+	defaultJWKSRefresh      = 15 * time.Minute
+	defaultMinJWKSRefetch   = 10 * time.Second
+	defaultProviderTimeout  = 10 * time.Second
+	initialDiscoveryBackoff = time.Second
+	maxDiscoveryBackoff     = 30 * time.Second
+)
+
+// signingAlgs are the asymmetric algorithms accepted from a provider's JWKS.
+var signingAlgs = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+}
+
+// LazyVerifier discovers the OIDC provider in the background, so the server can start
+// before Keycloak is up. Until Run has reached the provider, Verify returns
+// ErrVerifierNotReady. Signing keys are refreshed periodically and refetched when a
+// token names an unknown key id, at most once per MinRefetchInterval.
+type LazyVerifier struct {
+	Issuer string
+	// DiscoveryURL is where the discovery document is fetched from, when it differs
+	// from Issuer (e.g. inside the devcontainer network). Optional.
+	DiscoveryURL string
+	Policy       TokenPolicy
+	Client       *http.Client
+
+	// RefreshInterval is how often signing keys are refetched. Defaults to 15m.
+	RefreshInterval time.Duration
+	// MinRefetchInterval limits refetches caused by unknown key ids. Defaults to 10s.
+	MinRefetchInterval time.Duration
+
+	verifier atomic.Pointer[OIDCVerifier]
+}
+
+// Ready reports whether the provider has been discovered and tokens can be verified.
+func (v *LazyVerifier) Ready() bool {
+	return v.verifier.Load() != nil
+}
+
+func (v *LazyVerifier) Verify(ctx context.Context, rawToken string) (*Principal, error) {
+	inner := v.verifier.Load()
+	if inner == nil {
+		return nil, ErrVerifierNotReady
+	}
+	return inner.Verify(ctx, rawToken)
+}
+
+// Run discovers the provider, retrying with backoff until it succeeds, and then
+// keeps its signing keys fresh until ctx is cancelled.
+func (v *LazyVerifier) Run(ctx context.Context) {
+	backoff := initialDiscoveryBackoff
+	var keys *jwksKeySet
+	for attempt := 1; ; attempt++ {
+		var err error
+		keys, err = v.discover(ctx)
+		if err == nil {
+			break
+		}
+		slog.Warn("OIDC provider not ready, retrying", "issuer", v.Issuer, "attempt", attempt, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxDiscoveryBackoff)
+	}
+	slog.Info("OIDC provider discovered", "issuer", v.Issuer, "jwks_uri", keys.url)
+
+	ticker := time.NewTicker(v.refreshInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := keys.fetch(ctx); err != nil && ctx.Err() == nil {
+				slog.Error("failed to refresh JWKS", "error", err)
+			}
+		}
+	}
+}
+
+// discover fetches the discovery document and an initial key set, and installs the
+// verifier once both have succeeded.
+func (v *LazyVerifier) discover(ctx context.Context) (*jwksKeySet, error) {
+	client := v.client()
+	discoveryCtx := oidc.ClientContext(ctx, client)
+	if v.DiscoveryURL != "" && v.DiscoveryURL != v.Issuer {
+		discoveryCtx = oidc.InsecureIssuerURLContext(discoveryCtx, v.DiscoveryURL)
+	}
+
+	provider, err := oidc.NewProvider(discoveryCtx, v.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	var meta struct {
+		JWKSURL string   `json:"jwks_uri"`
+		Algs    []string `json:"id_token_signing_alg_values_supported"`
+	}
+	if err := provider.Claims(&meta); err != nil {
+		return nil, err
+	}
+	if meta.JWKSURL == "" {
+		return nil, errors.New("discovery document has no jwks_uri")
+	}
+
+	keys := &jwksKeySet{url: meta.JWKSURL, client: client, minRefetch: v.minRefetchInterval()}
+	if err := keys.fetch(ctx); err != nil {
+		return nil, err
+	}
+
+	config := OIDCConfig()
+	config.SupportedSigningAlgs = meta.Algs
+	v.verifier.Store(NewOIDCVerifier(oidc.NewVerifier(v.Issuer, keys, config), v.Policy))
+	return keys, nil
+}
+
+func (v *LazyVerifier) client() *http.Client {
+	if v.Client != nil {
+		return v.Client
+	}
+	return &http.Client{Timeout: defaultProviderTimeout}
+}
+
+func (v *LazyVerifier) refreshInterval() time.Duration {
+	if v.RefreshInterval > 0 {
+		return v.RefreshInterval
+	}
+	return defaultJWKSRefresh
+}
+
+func (v *LazyVerifier) minRefetchInterval() time.Duration {
+	if v.MinRefetchInterval > 0 {
+		return v.MinRefetchInterval
+	}
+	return defaultMinJWKSRefetch
+}
+
+// jwksKeySet is an oidc.KeySet backed by a provider's JWKS endpoint.
+type jwksKeySet struct {
+	url        string
+	client     *http.Client
+	minRefetch time.Duration
+
+	mu   sync.RWMutex
+	keys []jose.JSONWebKey
+
+	// fetchMu serialises fetches; lastFetch is guarded by it.
+	fetchMu   sync.Mutex
+	lastFetch time.Time
+}
+
+// VerifySignature implements oidc.KeySet. A token signed with an unknown key id
+// triggers a refetch, unless one happened within minRefetch.
+func (s *jwksKeySet) VerifySignature(ctx context.Context, jwt string) ([]byte, error) {
+	jws, err := jose.ParseSigned(jwt, signingAlgs)
+	if err != nil {
+		return nil, fmt.Errorf("malformed jwt: %w", err)
+	}
+	kid := jws.Signatures[0].Header.KeyID
+
+	if payload, ok := s.verify(jws, kid); ok {
+		return payload, nil
+	}
+	if err := s.refetch(ctx); err != nil {
+		return nil, err
+	}
+	if payload, ok := s.verify(jws, kid); ok {
+		return payload, nil
+	}
+	return nil, errors.New("failed to verify signature: no matching key")
+}
+
+func (s *jwksKeySet) verify(jws *jose.JSONWebSignature, kid string) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, k := range s.keys {
+		if kid != "" && k.KeyID != kid {
+			continue
+		}
+		if payload, err := jws.Verify(&k); err == nil {
+			return payload, true
+		}
+	}
+	return nil, false
+}
+
+// refetch fetches the key set unless that was done within minRefetch. Concurrent
+// callers wait for the fetch in progress and then find it recent.
+func (s *jwksKeySet) refetch(ctx context.Context) error {
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+	if time.Since(s.lastFetch) < s.minRefetch {
+		return nil
+	}
+	return s.fetchLocked(ctx)
+}
+
+// fetch replaces the key set with the provider's current keys. On failure the
+// previous keys are kept.
+func (s *jwksKeySet) fetch(ctx context.Context) error {
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+	return s.fetchLocked(ctx)
+}
+
+func (s *jwksKeySet) fetchLocked(ctx context.Context) error {
+	s.lastFetch = time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("decode jwks: %w", err)
+	}
+	var keys []jose.JSONWebKey
+	for _, k := range set.Keys {
+		if pub := k.Public(); (k.Use == "" || k.Use == "sig") && pub.Key != nil {
+			keys = append(keys, pub)
+		}
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	return nil
+}
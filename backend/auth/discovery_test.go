@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+// fakeProvider serves a discovery document and a JWKS that can be rotated.
+type fakeProvider struct {
+	srv        *httptest.Server
+	up         atomic.Bool
+	jwksHits   atomic.Int32
+	mu         sync.Mutex
+	publicKeys []jose.JSONWebKey
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	p := &fakeProvider{}
+	p.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !p.up.Load() {
+			http.Error(w, "starting", http.StatusServiceUnavailable)
+			return
+		}
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(map[string]any{
+				"issuer":                                p.srv.URL,
+				"jwks_uri":                              p.srv.URL + "/certs",
+				"id_token_signing_alg_values_supported": []string{"ES256"},
+			})
+		case "/certs":
+			p.jwksHits.Add(1)
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: p.publicKeys})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(p.srv.Close)
+	return p
+}
+
+// rotate publishes a new signing key and returns it.
+func (p *fakeProvider) rotate(kid string) *ecdsa.PrivateKey {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p.mu.Lock()
+	p.publicKeys = append(p.publicKeys, jose.JSONWebKey{Key: key.Public(), KeyID: kid, Algorithm: "ES256", Use: "sig"})
+	p.mu.Unlock()
+	return key
+}
+
+func (p *fakeProvider) token(t *testing.T, key *ecdsa.PrivateKey, kid string) string {
+	t.Helper()
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: jose.JSONWebKey{Key: key, KeyID: kid}}, nil)
+	if err != nil {
+		t.Fatalf("new signer: %v", err)
+	}
+	claims := validClaims()
+	claims["iss"] = p.srv.URL
+	payload, _ := json.Marshal(claims)
+	obj, _ := signer.Sign(payload)
+	raw, _ := obj.CompactSerialize()
+	return raw
+}
+
+func waitReady(t *testing.T, v *LazyVerifier) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !v.Ready() {
+		if time.Now().After(deadline) {
+			t.Fatal("verifier never became ready")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLazyVerifier_NotReadyUntilProviderIsUp(t *testing.T) {
+	p := newFakeProvider(t)
+	key := p.rotate("k1")
+	v := &LazyVerifier{Issuer: p.srv.URL}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go v.Run(ctx)
+
+	if _, err := v.Verify(ctx, p.token(t, key, "k1")); !errors.Is(err, ErrVerifierNotReady) {
+		t.Fatalf("got %v, want %v", err, ErrVerifierNotReady)
+	}
+
+	p.up.Store(true)
+	waitReady(t, v)
+
+	if _, err := v.Verify(ctx, p.token(t, key, "k1")); err != nil {
+		t.Errorf("unexpected error once ready: %v", err)
+	}
+}
+
+func TestLazyVerifier_RefetchesOnUnknownKeyID(t *testing.T) {
+	p := newFakeProvider(t)
+	p.up.Store(true)
+	p.rotate("k1")
+	v := &LazyVerifier{Issuer: p.srv.URL, MinRefetchInterval: 200 * time.Millisecond}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go v.Run(ctx)
+	waitReady(t, v)
+	time.Sleep(250 * time.Millisecond) // let the initial fetch age past the limit
+	before := p.jwksHits.Load()
+
+	rotated := p.rotate("k2")
+	if _, err := v.Verify(ctx, p.token(t, rotated, "k2")); err != nil {
+		t.Fatalf("expected rotated key to be picked up, got %v", err)
+	}
+	if hits := p.jwksHits.Load() - before; hits != 1 {
+		t.Errorf("jwks fetches after rotation: got %d, want 1", hits)
+	}
+
+	// Within MinRefetchInterval, further unknown kids must not hit the provider.
+	stranger, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	for range 5 {
+		if _, err := v.Verify(ctx, p.token(t, stranger, "k3")); err == nil {
+			t.Fatal("expected token with unknown key to be rejected")
+		}
+	}
+	if hits := p.jwksHits.Load() - before; hits != 1 {
+		t.Errorf("jwks fetches after unknown kids: got %d, want 1", hits)
+	}
+}
+
+func TestLazyVerifier_PeriodicRefresh(t *testing.T) {
+	p := newFakeProvider(t)
+	p.up.Store(true)
+	p.rotate("k1")
+	v := &LazyVerifier{Issuer: p.srv.URL, RefreshInterval: 20 * time.Millisecond}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go v.Run(ctx)
+	waitReady(t, v)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for p.jwksHits.Load() < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected periodic refreshes, got %d fetches", p.jwksHits.Load())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestAuthMiddleware_NotReady(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer t")
+	applyMiddleware(&LazyVerifier{}, okHandler).ServeHTTP(w, r)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header")
+	}
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/dcrespo1/book-list-app/problem"
+)
+
+// AuthInterceptor is AuthMiddleware for Connect services: every RPC needs a valid
+// Bearer token, and the caller is injected into its context. Failures carry the
+// problem code AuthMiddleware answers with; see problem.ConnectError.
+func AuthInterceptor(v TokenVerifier) connect.Interceptor {
+	return checkInterceptor(func(ctx context.Context, _ connect.Spec, header http.Header) (context.Context, error) {
+		scheme, token, _ := strings.Cut(header.Get("Authorization"), " ")
+		if !strings.EqualFold(scheme, "Bearer") {
+			return nil, problem.ConnectError(ctx, problem.Unauthenticated, "authorization header required")
+		}
+		if token = strings.TrimSpace(token); token == "" {
+			return nil, problem.ConnectError(ctx, problem.MalformedToken, "bearer token is empty")
+		}
+
+		principal, err := v.Verify(ctx, token)
+		if errors.Is(err, ErrVerifierNotReady) {
+			cerr := problem.ConnectError(ctx, problem.Unavailable, "authentication is starting up, try again shortly")
+			cerr.Meta().Set("Retry-After", "5")
+			return nil, cerr
+		}
+		if err != nil {
+			return nil, problem.ConnectError(ctx, problem.InvalidToken, describeTokenError(err))
+		}
+		return PrincipalToContext(ctx, principal), nil
+	})
+}
+
+// PersonalTokenScopesInterceptor is RequirePersonalTokenScopes for Connect services.
+// RPCs declared free of side effects need the read or write scope, anything else
+// needs write. It must come after AuthInterceptor.
+func PersonalTokenScopesInterceptor(read, write string) connect.Interceptor {
+	return checkInterceptor(func(ctx context.Context, spec connect.Spec, _ http.Header) (context.Context, error) {
+		p, ok := PrincipalFromContext(ctx)
+		if !ok || !p.PersonalToken {
+			return ctx, nil
+		}
+		allowed := p.HasScope(write)
+		if spec.IdempotencyLevel == connect.IdempotencyNoSideEffects {
+			allowed = allowed || p.HasScope(read)
+		}
+		if !allowed {
+			return nil, problem.ConnectError(ctx, problem.InsufficientScope, "insufficient scope")
+		}
+		return ctx, nil
+	})
+}
+
+// checkInterceptor runs a check before every RPC a handler serves, streaming ones
+// included, and calls the RPC with the context it returns. Clients are unaffected.
+type checkInterceptor func(ctx context.Context, spec connect.Spec, header http.Header) (context.Context, error)
+
+func (c checkInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		ctx, err := c(ctx, req.Spec(), req.Header())
+		if err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (c checkInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (c checkInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, err := c(ctx, conn.Spec(), conn.RequestHeader())
+		if err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func callIntercepted(v TokenVerifier, authorization string) (sub string, err error) {
+	next := func(ctx context.Context, _ connect.AnyRequest) (connect.AnyResponse, error) {
+		sub, _ = SubFromContext(ctx)
+		return connect.NewResponse(&emptypb.Empty{}), nil
+	}
+	req := connect.NewRequest(&emptypb.Empty{})
+	if authorization != "" {
+		req.Header().Set("Authorization", authorization)
+	}
+	_, err = AuthInterceptor(v).WrapUnary(next)(context.Background(), req)
+	return sub, err
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	cases := []struct {
+		name          string
+		verifier      *mockVerifier
+		authorization string
+		want          connect.Code
+	}{
+		{"missing header", &mockVerifier{sub: "u"}, "", connect.CodeUnauthenticated},
+		{"non-bearer scheme", &mockVerifier{sub: "u"}, "Basic dXNlcjpwYXNz", connect.CodeUnauthenticated},
+		{"empty token", &mockVerifier{sub: "u"}, "Bearer ", connect.CodeInvalidArgument},
+		{"invalid token", &mockVerifier{err: errors.New("bad signature")}, "Bearer bad", connect.CodeUnauthenticated},
+		{"verifier not ready", &mockVerifier{err: ErrVerifierNotReady}, "Bearer t", connect.CodeUnavailable},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := callIntercepted(tc.verifier, tc.authorization)
+			if got := connect.CodeOf(err); got != tc.want {
+				t.Errorf("code: got %v, want %v (%v)", got, tc.want, err)
+			}
+		})
+	}
+}
+
+func TestAuthInterceptor_ValidToken_PassesThrough(t *testing.T) {
+	sub, err := callIntercepted(&mockVerifier{sub: "user-uuid-abc123"}, "Bearer valid-token")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sub != "user-uuid-abc123" {
+		t.Errorf("sub in context: got %q, want %q", sub, "user-uuid-abc123")
+	}
+}
@@ -2,58 +2,108 @@ package auth
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/dcrespo1/book-list-app/problem"
 )
 
 type contextKey string
 
-const contextKeySub contextKey = "sub"
-
-// TokenVerifier validates a raw Bearer token and returns the subject claim.
+// TokenVerifier validates a raw Bearer token and returns the caller it identifies.
 // *OIDCVerifier implements this; tests use a mock.
 type TokenVerifier interface {
-	Verify(ctx context.Context, rawToken string) (sub string, err error)
+	Verify(ctx context.Context, rawToken string) (*Principal, error)
 }
 
-// OIDCVerifier wraps *oidc.IDTokenVerifier to implement TokenVerifier.
+// OIDCVerifier wraps *oidc.IDTokenVerifier to implement TokenVerifier for access tokens.
 type OIDCVerifier struct {
-	inner *oidc.IDTokenVerifier
+	inner  *oidc.IDTokenVerifier
+	policy TokenPolicy
 }
 
-func NewOIDCVerifier(inner *oidc.IDTokenVerifier) *OIDCVerifier {
-	return &OIDCVerifier{inner: inner}
+// NewOIDCVerifier checks signatures and issuers with inner, which should be built
+// from OIDCConfig, and everything else with policy.
+func NewOIDCVerifier(inner *oidc.IDTokenVerifier, policy TokenPolicy) *OIDCVerifier {
+	return &OIDCVerifier{inner: inner, policy: policy}
 }
 
-func (v *OIDCVerifier) Verify(ctx context.Context, rawToken string) (string, error) {
+func (v *OIDCVerifier) Verify(ctx context.Context, rawToken string) (*Principal, error) {
 	token, err := v.inner.Verify(ctx, rawToken)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	var registered policyClaims
+	if err := token.Claims(&registered); err != nil {
+		return nil, err
+	}
+	if err := v.policy.check(registered); err != nil {
+		return nil, err
+	}
+	var claims tokenClaims
+	if err := token.Claims(&claims); err != nil {
+		return nil, err
 	}
-	return token.Subject, nil
+	return claims.principal(token.Subject), nil
 }
 
 // AuthMiddleware is a chi-compatible middleware that requires a valid Keycloak Bearer token.
-// On success it injects the token's subject claim into the request context via SubFromContext.
+// On success it injects the caller into the request context; see PrincipalFromContext.
+// Failures carry an RFC 6750 WWW-Authenticate challenge.
 func AuthMiddleware(v TokenVerifier) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			header := r.Header.Get("Authorization")
-			if !strings.HasPrefix(header, "Bearer ") {
-				writeError(w, http.StatusUnauthorized, "authorization header required")
+			scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+			if !strings.EqualFold(scheme, "Bearer") {
+				challenge(w, "", "")
+				problem.Write(w, r, problem.Unauthenticated, "authorization header required")
+				return
+			}
+			if token = strings.TrimSpace(token); token == "" {
+				challenge(w, "invalid_request", "bearer token is empty")
+				problem.Write(w, r, problem.MalformedToken, "bearer token is empty")
 				return
 			}
 
-			sub, err := v.Verify(r.Context(), strings.TrimPrefix(header, "Bearer "))
+			principal, err := v.Verify(r.Context(), token)
+			if errors.Is(err, ErrVerifierNotReady) {
+				w.Header().Set("Retry-After", "5")
+				problem.Write(w, r, problem.Unavailable, "authentication is starting up, try again shortly")
+				return
+			}
 			if err != nil {
-				writeError(w, http.StatusUnauthorized, "invalid or expired token")
+				desc := describeTokenError(err)
+				challenge(w, "invalid_token", desc)
+				problem.Write(w, r, problem.InvalidToken, desc)
 				return
 			}
 
-			next.ServeHTTP(w, r.WithContext(SubToContext(r.Context(), sub)))
+			next.ServeHTTP(w, r.WithContext(PrincipalToContext(r.Context(), principal)))
+		})
+	}
+}
+
+// OptionalAuthMiddleware identifies the caller when it can and otherwise treats the
+// request as anonymous. The routes it guards work without a token, so a stale or
+// invalid token, or a verifier that is still starting, does not fail the request:
+// the caller just gets the anonymous response.
+func OptionalAuthMiddleware(v TokenVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+			if token = strings.TrimSpace(token); !strings.EqualFold(scheme, "Bearer") || token == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			principal, err := v.Verify(r.Context(), token)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(PrincipalToContext(r.Context(), principal)))
 		})
 	}
 }
@@ -61,18 +111,48 @@ func AuthMiddleware(v TokenVerifier) func(http.Handler) http.Handler {
 // SubFromContext retrieves the authenticated user's Keycloak subject (UUID) from the request context.
 // Returns ("", false) if no authenticated user is present.
 func SubFromContext(ctx context.Context) (string, bool) {
-	sub, ok := ctx.Value(contextKeySub).(string)
-	return sub, ok
+	p, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	return p.Subject, true
 }
 
-// SubToContext injects a Keycloak subject claim into a context.
-// Used by AuthMiddleware and in tests to simulate an authenticated user.
+// SubToContext injects a Principal carrying only a subject into a context.
+// Used in tests to simulate an authenticated user.
 func SubToContext(ctx context.Context, sub string) context.Context {
-	return context.WithValue(ctx, contextKeySub, sub)
+	return PrincipalToContext(ctx, &Principal{Subject: sub})
+}
+
+// realm is the protection space named in WWW-Authenticate challenges.
+const realm = "booklist"
+
+// challenge sets an RFC 6750 WWW-Authenticate header. code is empty when the request
+// carried no credentials at all.
+func challenge(w http.ResponseWriter, code, description string, params ...string) {
+	v := `Bearer realm="` + realm + `"`
+	if code != "" {
+		v += `, error="` + code + `"`
+	}
+	if description != "" {
+		v += ", error_description=" + strconv.Quote(description)
+	}
+	for i := 0; i+1 < len(params); i += 2 {
+		v += ", " + params[i] + "=" + strconv.Quote(params[i+1])
+	}
+	w.Header().Set("WWW-Authenticate", v)
 }
 
-func writeError(w http.ResponseWriter, status int, msg string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+// describeTokenError turns a verification failure into a client-safe description.
+func describeTokenError(err error) string {
+	var expired *oidc.TokenExpiredError
+	if errors.As(err, &expired) {
+		return ErrTokenExpired.Error()
+	}
+	for _, known := range []error{ErrTokenExpired, ErrTokenNotYetValid, ErrTokenType, ErrTokenAudience, ErrTokenUnauthorized, ErrPersonalTokenInvalid} {
+		if errors.Is(err, known) {
+			return known.Error()
+		}
+	}
+	return "token could not be verified"
 }
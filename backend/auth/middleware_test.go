@@ -13,8 +13,11 @@ type mockVerifier struct {
 	err error
 }
 
-func (m *mockVerifier) Verify(_ context.Context, _ string) (string, error) {
-	return m.sub, m.err
+func (m *mockVerifier) Verify(_ context.Context, _ string) (*Principal, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &Principal{Subject: m.sub}, nil
 }
 
 func applyMiddleware(v TokenVerifier, next http.Handler) http.Handler {
@@ -77,9 +80,83 @@ func TestAuthMiddleware_ValidToken_PassesThrough(t *testing.T) {
 	}
 }
 
+func TestOptionalAuthMiddleware(t *testing.T) {
+	var gotSub string
+	var gotOK bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSub, gotOK = SubFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+	cases := []struct {
+		name     string
+		header   string
+		verifier TokenVerifier
+		wantSub  string
+	}{
+		{"anonymous", "", &mockVerifier{sub: "user-uuid-abc123"}, ""},
+		{"valid token", "Bearer good-token", &mockVerifier{sub: "user-uuid-abc123"}, "user-uuid-abc123"},
+		{"invalid token", "Bearer bad-token", &mockVerifier{err: errors.New("bad signature")}, ""},
+		{"expired token", "Bearer old-token", &mockVerifier{err: ErrTokenExpired}, ""},
+		{"verifier not ready", "Bearer good-token", &mockVerifier{err: ErrVerifierNotReady}, ""},
+		{"other scheme", "Basic dXNlcjpwdw==", &mockVerifier{sub: "user-uuid-abc123"}, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gotSub, gotOK = "", false
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				r.Header.Set("Authorization", tc.header)
+			}
+			OptionalAuthMiddleware(tc.verifier)(next).ServeHTTP(w, r)
+			if w.Code != http.StatusOK {
+				t.Errorf("status: got %d, want %d", w.Code, http.StatusOK)
+			}
+			if gotSub != tc.wantSub || gotOK != (tc.wantSub != "") {
+				t.Errorf("sub in context: got %q (%v), want %q", gotSub, gotOK, tc.wantSub)
+			}
+		})
+	}
+}
+
 func TestSubFromContext_Missing(t *testing.T) {
 	_, ok := SubFromContext(context.Background())
 	if ok {
 		t.Error("expected ok=false for context with no authenticated user")
 	}
 }
+
+func TestAuthMiddleware_Challenges(t *testing.T) {
+	cases := []struct {
+		name       string
+		header     string
+		verifyErr  error
+		wantStatus int
+		wantHeader string
+	}{
+		{"missing header", "", nil, http.StatusUnauthorized, `Bearer realm="booklist"`},
+		{"empty token", "Bearer ", nil, http.StatusBadRequest,
+			`Bearer realm="booklist", error="invalid_request", error_description="bearer token is empty"`},
+		{"wrong audience", "Bearer t", ErrTokenAudience, http.StatusUnauthorized,
+			`Bearer realm="booklist", error="invalid_token", error_description="token audience is not accepted"`},
+		{"internal error hidden", "Bearer t", errors.New("square/go-jose: error in cryptographic primitive"), http.StatusUnauthorized,
+			`Bearer realm="booklist", error="invalid_token", error_description="token could not be verified"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				r.Header.Set("Authorization", tc.header)
+			}
+			applyMiddleware(&mockVerifier{sub: "user-123", err: tc.verifyErr}, okHandler).ServeHTTP(w, r)
+
+			if w.Code != tc.wantStatus {
+				t.Errorf("status: got %d, want %d", w.Code, tc.wantStatus)
+			}
+			if got := w.Header().Get("WWW-Authenticate"); got != tc.wantHeader {
+				t.Errorf("WWW-Authenticate: got %s, want %s", got, tc.wantHeader)
+			}
+		})
+	}
+}
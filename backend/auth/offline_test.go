@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+const testIssuer = "http://localhost:8180/realms/booklist"
+
+func signTestToken(t *testing.T, key *ecdsa.PrivateKey, claims map[string]any) string {
+	t.Helper()
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: key}, nil)
+	if err != nil {
+		t.Fatalf("new signer: %v", err)
+	}
+	payload, _ := json.Marshal(claims)
+	obj, err := signer.Sign(payload)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	token, _ := obj.CompactSerialize()
+	return token
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"iss":                testIssuer,
+		"sub":                "dev-user",
+		"typ":                "Bearer",
+		"azp":                "booklist-api",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"preferred_username": "dev",
+		"realm_access":       map[string]any{"roles": []string{"admin"}},
+	}
+}
+
+func TestOfflineVerifier(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	v := NewOfflineVerifier(testIssuer, []crypto.PublicKey{key.Public()}, TokenPolicy{})
+
+	t.Run("valid", func(t *testing.T) {
+		p, err := v.Verify(context.Background(), signTestToken(t, key, validClaims()))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if p.Subject != "dev-user" || p.Username != "dev" || !p.HasRole("admin") {
+			t.Errorf("unexpected principal: %+v", p)
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		if _, err := v.Verify(context.Background(), signTestToken(t, other, validClaims())); err == nil {
+			t.Error("expected token signed by an unknown key to be rejected")
+		}
+	})
+
+	t.Run("wrong issuer", func(t *testing.T) {
+		claims := validClaims()
+		claims["iss"] = "http://evil.example.com"
+		if _, err := v.Verify(context.Background(), signTestToken(t, key, claims)); err == nil {
+			t.Error("expected token from another issuer to be rejected")
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		claims := validClaims()
+		claims["exp"] = time.Now().Add(-time.Minute).Unix()
+		if _, err := v.Verify(context.Background(), signTestToken(t, key, claims)); !errors.Is(err, ErrTokenExpired) {
+			t.Errorf("got %v, want %v", err, ErrTokenExpired)
+		}
+	})
+}
+
+func TestLoadPublicKeys(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	dir := t.TempDir()
+
+	jwks, _ := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{Key: key.Public(), Algorithm: "ES256", Use: "sig"},
+		{Key: []byte("shared-secret"), Algorithm: "HS256", Use: "sig"},
+	}})
+	jwksPath := filepath.Join(dir, "jwks.json")
+	os.WriteFile(jwksPath, jwks, 0o600)
+
+	der, _ := x509.MarshalPKIXPublicKey(key.Public())
+	pemPath := filepath.Join(dir, "key.pem")
+	os.WriteFile(pemPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600)
+
+	for _, path := range []string{jwksPath, pemPath} {
+		keys, err := LoadPublicKeys(path)
+		if err != nil {
+			t.Fatalf("%s: %v", filepath.Base(path), err)
+		}
+		if len(keys) != 1 || !key.PublicKey.Equal(keys[0]) {
+			t.Errorf("%s: got %d keys, want the one ES256 key", filepath.Base(path), len(keys))
+		}
+	}
+}
+
+func TestParsePEMKeys_Empty(t *testing.T) {
+	if _, err := ParsePEMKeys([]byte("not a key")); err == nil {
+		t.Error("expected error for input without PEM blocks")
+	}
+}
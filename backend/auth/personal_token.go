@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/problem"
+)
+
+// Scopes a personal access token can be granted.
+const (
+	ScopeReadlistRead  = "readlist:read"
+	ScopeReadlistWrite = "readlist:write"
+)
+
+// PersonalTokenPrefix starts every personal access token, so they can be told apart
+// from JWTs (and spotted by secret scanners).
+const PersonalTokenPrefix = "blpat_"
+
+// ErrPersonalTokenInvalid is returned for an unknown, revoked or expired personal token.
+var ErrPersonalTokenInvalid = errors.New("personal access token is invalid, revoked or expired")
+
+// NewPersonalToken generates a personal access token, returning the token to show
+// the user once and the hash to store.
+func NewPersonalToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = PersonalTokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return token, HashPersonalToken(token), nil
+}
+
+// HashPersonalToken is the form in which a personal access token is stored.
+func HashPersonalToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// PersonalTokenStore looks up personal access tokens. *database.Queries satisfies it.
+type PersonalTokenStore interface {
+	UsePersonalAccessToken(ctx context.Context, tokenHash string) (database.PersonalAccessToken, error)
+}
+
+// PersonalTokenVerifier accepts personal access tokens and hands any other token to JWT.
+type PersonalTokenVerifier struct {
+	Store PersonalTokenStore
+	JWT   TokenVerifier
+}
+
+func (v *PersonalTokenVerifier) Verify(ctx context.Context, rawToken string) (*Principal, error) {
+	if !strings.HasPrefix(rawToken, PersonalTokenPrefix) {
+		return v.JWT.Verify(ctx, rawToken)
+	}
+	pat, err := v.Store.UsePersonalAccessToken(ctx, HashPersonalToken(rawToken))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPersonalTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Principal{Subject: pat.UserID, Scopes: pat.Scopes, PersonalToken: true}, nil
+}
+
+// RequirePersonalTokenScopes limits requests authenticated with a personal access
+// token: safe methods need the read or write scope, anything else needs write.
+// Requests authenticated otherwise pass through. It must run after AuthMiddleware.
+func RequirePersonalTokenScopes(read, write string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := PrincipalFromContext(r.Context())
+			if !ok || !p.PersonalToken {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			needed := write
+			allowed := p.HasScope(write)
+			if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				needed = read
+				allowed = allowed || p.HasScope(read)
+			}
+			if !allowed {
+				challenge(w, "insufficient_scope", "insufficient scope", "scope", needed)
+				problem.Write(w, r, problem.InsufficientScope, "insufficient scope")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dcrespo1/book-list-app/pkg/database"
+)
+
+type fakeTokenStore map[string]database.PersonalAccessToken
+
+func (f fakeTokenStore) UsePersonalAccessToken(_ context.Context, hash string) (database.PersonalAccessToken, error) {
+	pat, ok := f[hash]
+	if !ok {
+		return database.PersonalAccessToken{}, sql.ErrNoRows
+	}
+	return pat, nil
+}
+
+func TestNewPersonalToken(t *testing.T) {
+	token, hash, err := NewPersonalToken()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(token, PersonalTokenPrefix) {
+		t.Errorf("token %q lacks prefix %q", token, PersonalTokenPrefix)
+	}
+	if hash != HashPersonalToken(token) || strings.Contains(hash, token) {
+		t.Errorf("hash %q does not match token", hash)
+	}
+	if other, _, _ := NewPersonalToken(); other == token {
+		t.Error("expected distinct tokens")
+	}
+}
+
+func TestPersonalTokenVerifier(t *testing.T) {
+	token, hash, _ := NewPersonalToken()
+	v := &PersonalTokenVerifier{
+		Store: fakeTokenStore{hash: {UserID: "user-123", Scopes: []string{ScopeReadlistRead}}},
+		JWT:   &mockVerifier{sub: "jwt-user"},
+	}
+
+	p, err := v.Verify(context.Background(), token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Subject != "user-123" || !p.PersonalToken || !p.HasScope(ScopeReadlistRead) {
+		t.Errorf("unexpected principal: %+v", p)
+	}
+
+	if _, err := v.Verify(context.Background(), PersonalTokenPrefix+"unknown"); !errors.Is(err, ErrPersonalTokenInvalid) {
+		t.Errorf("got %v, want %v", err, ErrPersonalTokenInvalid)
+	}
+
+	p, err = v.Verify(context.Background(), "eyJ.jwt.token")
+	if err != nil || p.Subject != "jwt-user" || p.PersonalToken {
+		t.Errorf("expected JWT to be delegated, got %+v, %v", p, err)
+	}
+}
+
+func TestRequirePersonalTokenScopes(t *testing.T) {
+	read := &Principal{Subject: "u", Scopes: []string{ScopeReadlistRead}, PersonalToken: true}
+	write := &Principal{Subject: "u", Scopes: []string{ScopeReadlistWrite}, PersonalToken: true}
+	jwt := &Principal{Subject: "u"}
+
+	cases := []struct {
+		name      string
+		principal *Principal
+		method    string
+		want      int
+	}{
+		{"read token reads", read, http.MethodGet, http.StatusOK},
+		{"read token writes", read, http.MethodPost, http.StatusForbidden},
+		{"write token reads", write, http.MethodGet, http.StatusOK},
+		{"write token deletes", write, http.MethodDelete, http.StatusOK},
+		{"jwt without scopes", jwt, http.MethodPatch, http.StatusOK},
+	}
+	mw := RequirePersonalTokenScopes(ScopeReadlistRead, ScopeReadlistWrite)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tc.method, "/", nil)
+			r = r.WithContext(PrincipalToContext(r.Context(), tc.principal))
+			mw(okHandler).ServeHTTP(w, r)
+			if w.Code != tc.want {
+				t.Errorf("status: got %d, want %d", w.Code, tc.want)
+			}
+		})
+	}
+}
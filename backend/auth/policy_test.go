@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+var policyNow = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func claimsJSON(t *testing.T, raw string) policyClaims {
+	t.Helper()
+	var c policyClaims
+	if err := json.Unmarshal([]byte(raw), &c); err != nil {
+		t.Fatalf("unmarshal claims: %v", err)
+	}
+	return c
+}
+
+func TestTokenPolicy_Check(t *testing.T) {
+	exp := policyNow.Add(time.Minute).Unix()
+	expired := policyNow.Add(-10 * time.Second).Unix()
+	future := policyNow.Add(10 * time.Second).Unix()
+	policy := TokenPolicy{
+		Audiences:         []string{"booklist-api"},
+		AuthorizedParties: []string{"booklist-api", "booklist-frontend"},
+		now:               func() time.Time { return policyNow },
+	}
+
+	cases := []struct {
+		name   string
+		policy TokenPolicy
+		claims string
+		want   error
+	}{
+		{"valid", policy, `{"typ":"Bearer","aud":["account","booklist-api"],"azp":"booklist-frontend","exp":%d}`, nil},
+		{"single string aud", policy, `{"typ":"Bearer","aud":"booklist-api","azp":"booklist-api","exp":%d}`, nil},
+		{"id token", policy, `{"typ":"ID","aud":"booklist-api","azp":"booklist-api","exp":%d}`, ErrTokenType},
+		{"wrong audience", policy, `{"typ":"Bearer","aud":"account","azp":"booklist-api","exp":%d}`, ErrTokenAudience},
+		{"wrong azp", policy, `{"typ":"Bearer","aud":"booklist-api","azp":"admin-cli","exp":%d}`, ErrTokenUnauthorized},
+		{"no lists configured", TokenPolicy{now: policy.now}, `{"typ":"Bearer","aud":"account","azp":"admin-cli","exp":%d}`, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.policy.check(claimsJSON(t, fmt.Sprintf(tc.claims, exp))); !errors.Is(err, tc.want) {
+				t.Errorf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+
+	t.Run("expired", func(t *testing.T) {
+		c := claimsJSON(t, fmt.Sprintf(`{"typ":"Bearer","exp":%d}`, expired))
+		if err := (TokenPolicy{now: policy.now}).check(c); !errors.Is(err, ErrTokenExpired) {
+			t.Errorf("got %v, want %v", err, ErrTokenExpired)
+		}
+		if err := (TokenPolicy{ClockSkew: 30 * time.Second, now: policy.now}).check(c); err != nil {
+			t.Errorf("expected clock skew to accept token, got %v", err)
+		}
+	})
+
+	t.Run("not yet valid", func(t *testing.T) {
+		c := claimsJSON(t, fmt.Sprintf(`{"typ":"Bearer","exp":%d,"nbf":%d}`, exp, future))
+		if err := (TokenPolicy{now: policy.now}).check(c); !errors.Is(err, ErrTokenNotYetValid) {
+			t.Errorf("got %v, want %v", err, ErrTokenNotYetValid)
+		}
+		if err := (TokenPolicy{ClockSkew: 30 * time.Second, now: policy.now}).check(c); err != nil {
+			t.Errorf("expected clock skew to accept token, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/dcrespo1/book-list-app/problem"
+)
+
+const contextKeyPrincipal contextKey = "principal"
+
+// Principal is the authenticated caller, built from the claims of a verified token.
+type Principal struct {
+	Subject  string
+	Username string
+	Email    string
+	// Name is the display name (the name claim).
+	Name string
+	// RealmRoles are the Keycloak realm roles (realm_access.roles).
+	RealmRoles []string
+	// ClientRoles are the Keycloak client roles keyed by client id (resource_access).
+	ClientRoles map[string][]string
+	// Scopes are the OAuth scopes granted to the token (the space-separated scope claim),
+	// or the scopes of a personal access token.
+	Scopes []string
+	// PersonalToken is set when the caller authenticated with a personal access token
+	// rather than a Keycloak JWT.
+	PersonalToken bool
+}
+
+// HasRole reports whether the principal holds role. A role of the form
+// "client:role" names a client role; anything else names a realm role.
+func (p *Principal) HasRole(role string) bool {
+	if client, name, ok := strings.Cut(role, ":"); ok {
+		return slices.Contains(p.ClientRoles[client], name)
+	}
+	return slices.Contains(p.RealmRoles, role)
+}
+
+// HasScope reports whether the token was granted scope.
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+// tokenClaims are the Keycloak claims a Principal is built from.
+type tokenClaims struct {
+	PreferredUsername string `json:"preferred_username"`
+	Email             string `json:"email"`
+	Name              string `json:"name"`
+	Scope             string `json:"scope"`
+	RealmAccess       struct {
+		Roles []string `json:"roles"`
+	} `json:"realm_access"`
+	ResourceAccess map[string]struct {
+		Roles []string `json:"roles"`
+	} `json:"resource_access"`
+}
+
+func (c tokenClaims) principal(sub string) *Principal {
+	p := &Principal{
+		Subject:    sub,
+		Username:   c.PreferredUsername,
+		Email:      c.Email,
+		Name:       c.Name,
+		RealmRoles: c.RealmAccess.Roles,
+		Scopes:     strings.Fields(c.Scope),
+	}
+	if len(c.ResourceAccess) > 0 {
+		p.ClientRoles = make(map[string][]string, len(c.ResourceAccess))
+		for client, access := range c.ResourceAccess {
+			p.ClientRoles[client] = access.Roles
+		}
+	}
+	return p
+}
+
+// PrincipalFromContext retrieves the authenticated caller from the request context.
+// Returns (nil, false) if no authenticated user is present.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(contextKeyPrincipal).(*Principal)
+	return p, ok && p != nil
+}
+
+// PrincipalToContext injects the authenticated caller into a context.
+func PrincipalToContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKeyPrincipal, p)
+}
+
+// RequireRole allows the request through only if the caller holds at least one of
+// roles (see Principal.HasRole). It must run after AuthMiddleware.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return require("insufficient role", func(p *Principal) bool {
+		return slices.ContainsFunc(roles, p.HasRole)
+	})
+}
+
+// RequireScope allows the request through only if the caller's token was granted
+// every one of scopes. It must run after AuthMiddleware.
+func RequireScope(scopes ...string) func(http.Handler) http.Handler {
+	return require("insufficient scope", func(p *Principal) bool {
+		for _, s := range scopes {
+			if !p.HasScope(s) {
+				return false
+			}
+		}
+		return true
+	}, "insufficient_scope", "scope", strings.Join(scopes, " "))
+}
+
+// require gates a handler on allowed. A non-empty code is reported in an RFC 6750
+// challenge on refusal, with the given extra parameters.
+func require(msg string, allowed func(*Principal) bool, challengeParams ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := PrincipalFromContext(r.Context())
+			if !ok {
+				challenge(w, "", "")
+				problem.Write(w, r, problem.Unauthenticated, "authentication required")
+				return
+			}
+			if !allowed(p) {
+				t := problem.Forbidden
+				if len(challengeParams) > 0 {
+					challenge(w, challengeParams[0], msg, challengeParams[1:]...)
+					if challengeParams[0] == "insufficient_scope" {
+						t = problem.InsufficientScope
+					}
+				}
+				problem.Write(w, r, t, msg)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
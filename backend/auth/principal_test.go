@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dcrespo1/book-list-app/problem"
+)
+
+const keycloakClaims = `{
+	"preferred_username": "alice",
+	"email": "alice@example.com",
+	"name": "Alice Liddell",
+	"scope": "openid profile readlist:write",
+	"realm_access": {"roles": ["admin", "offline_access"]},
+	"resource_access": {"booklist-app": {"roles": ["librarian"]}}
+}`
+
+func TestTokenClaims_Principal(t *testing.T) {
+	var claims tokenClaims
+	if err := json.Unmarshal([]byte(keycloakClaims), &claims); err != nil {
+		t.Fatalf("unmarshal claims: %v", err)
+	}
+	p := claims.principal("user-123")
+
+	if p.Subject != "user-123" || p.Username != "alice" || p.Email != "alice@example.com" || p.Name != "Alice Liddell" {
+		t.Errorf("unexpected identity: %+v", p)
+	}
+	if !p.HasRole("admin") || p.HasRole("librarian") {
+		t.Errorf("realm roles: got %v", p.RealmRoles)
+	}
+	if !p.HasRole("booklist-app:librarian") || p.HasRole("booklist-app:admin") {
+		t.Errorf("client roles: got %v", p.ClientRoles)
+	}
+	if !p.HasScope("readlist:write") || p.HasScope("readlist:read") {
+		t.Errorf("scopes: got %v", p.Scopes)
+	}
+}
+
+func serveAs(p *Principal, mw func(http.Handler) http.Handler) int {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if p != nil {
+		r = r.WithContext(PrincipalToContext(r.Context(), p))
+	}
+	mw(okHandler).ServeHTTP(w, r)
+	return w.Code
+}
+
+func TestRequireRole(t *testing.T) {
+	cases := []struct {
+		name      string
+		principal *Principal
+		want      int
+	}{
+		{"unauthenticated", nil, http.StatusUnauthorized},
+		{"missing role", &Principal{Subject: "u", RealmRoles: []string{"user"}}, http.StatusForbidden},
+		{"realm role", &Principal{Subject: "u", RealmRoles: []string{"admin"}}, http.StatusOK},
+		{"client role", &Principal{Subject: "u", ClientRoles: map[string][]string{"booklist-app": {"admin"}}}, http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := serveAs(tc.principal, RequireRole("admin", "booklist-app:admin")); got != tc.want {
+				t.Errorf("status: got %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRequireScope_NeedsEveryScope(t *testing.T) {
+	p := &Principal{Subject: "u", Scopes: []string{"readlist:read"}}
+
+	if got := serveAs(p, RequireScope("readlist:read")); got != http.StatusOK {
+		t.Errorf("status: got %d, want %d", got, http.StatusOK)
+	}
+	if got := serveAs(p, RequireScope("readlist:read", "readlist:write")); got != http.StatusForbidden {
+		t.Errorf("status: got %d, want %d", got, http.StatusForbidden)
+	}
+}
+
+func TestAuthMiddleware_InjectsPrincipal(t *testing.T) {
+	var got *Principal
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = PrincipalFromContext(r.Context())
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer valid-token")
+	applyMiddleware(&mockVerifier{sub: "user-uuid-abc123"}, next).ServeHTTP(w, r)
+
+	if got == nil || got.Subject != "user-uuid-abc123" {
+		t.Errorf("principal in context: got %+v", got)
+	}
+}
+
+func TestRequireScope_Challenge(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r = r.WithContext(PrincipalToContext(r.Context(), &Principal{Subject: "u"}))
+	RequireScope("readlist:write")(okHandler).ServeHTTP(w, r)
+
+	want := `Bearer realm="booklist", error="insufficient_scope", error_description="insufficient scope", scope="readlist:write"`
+	if got := w.Header().Get("WWW-Authenticate"); got != want {
+		t.Errorf("WWW-Authenticate: got %s, want %s", got, want)
+	}
+	var p problem.Problem
+	json.NewDecoder(w.Body).Decode(&p)
+	if p.Code != problem.InsufficientScope.Code {
+		t.Errorf("code: got %q, want %q", p.Code, problem.InsufficientScope.Code)
+	}
+}
@@ -0,0 +1,196 @@
+// Command devtoken mints access tokens signed with a local key, for running the API
+// without Keycloak. The API accepts them when JWT_KEYS_FILE points at the JWKS
+// written by -init.
+//
+//	go run ./cmd/devtoken -init
+//	export JWT_KEYS_FILE=dev-jwks.json
+//	go run ./cmd/devtoken -sub alice -roles admin
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+func main() {
+	var (
+		keyPath  = flag.String("key", "dev-key.pem", "PEM private key used to sign tokens")
+		jwksPath = flag.String("jwks", "dev-jwks.json", "where -init writes the public JWKS")
+		initKey  = flag.Bool("init", false, "generate a new signing key and JWKS, then exit")
+		alg      = flag.String("alg", "ES256", "key algorithm for -init: ES256 or RS256")
+		issuer   = flag.String("issuer", envOr("KEYCLOAK_ISSUER", "http://localhost:8180/realms/booklist"), "iss claim")
+		sub      = flag.String("sub", "dev-user", "sub claim")
+		username = flag.String("username", "", "preferred_username claim (defaults to -sub)")
+		email    = flag.String("email", "", "email claim")
+		roles    = flag.String("roles", "", "comma-separated realm roles")
+		scope    = flag.String("scope", "openid profile email", "space-separated scopes")
+		aud      = flag.String("aud", envOr("OIDC_CLIENT_ID", "booklist-api"), "comma-separated aud claim")
+		azp      = flag.String("azp", envOr("OIDC_CLIENT_ID", "booklist-api"), "azp claim")
+		ttl      = flag.Duration("ttl", time.Hour, "token lifetime")
+	)
+	flag.Parse()
+
+	if *initKey {
+		if err := writeKeys(*keyPath, *jwksPath, *alg); err != nil {
+			fail(err)
+		}
+		fmt.Fprintf(os.Stderr, "wrote %s and %s\n", *keyPath, *jwksPath)
+		return
+	}
+
+	key, err := readPrivateKey(*keyPath)
+	if err != nil {
+		fail(fmt.Errorf("%w (run with -init to create one)", err))
+	}
+
+	if *username == "" {
+		*username = *sub
+	}
+	now := time.Now()
+	claims := map[string]any{
+		"iss":                *issuer,
+		"sub":                *sub,
+		"aud":                split(*aud, ","),
+		"azp":                *azp,
+		"typ":                "Bearer",
+		"iat":                now.Unix(),
+		"nbf":                now.Unix(),
+		"exp":                now.Add(*ttl).Unix(),
+		"preferred_username": *username,
+		"scope":              *scope,
+		"realm_access":       map[string]any{"roles": split(*roles, ",")},
+	}
+	if *email != "" {
+		claims["email"] = *email
+	}
+
+	token, err := sign(key, claims)
+	if err != nil {
+		fail(err)
+	}
+	fmt.Println(token)
+}
+
+func sign(key crypto.Signer, claims map[string]any) (string, error) {
+	jwk := jose.JSONWebKey{Key: key.Public()}
+	alg := jose.ES256
+	if _, ok := key.(*rsa.PrivateKey); ok {
+		alg = jose.RS256
+	}
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: alg, Key: jose.JSONWebKey{Key: key, KeyID: keyID(jwk)}},
+		(&jose.SignerOptions{}).WithType("JWT"),
+	)
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	obj, err := signer.Sign(payload)
+	if err != nil {
+		return "", err
+	}
+	return obj.CompactSerialize()
+}
+
+func writeKeys(keyPath, jwksPath, alg string) error {
+	var (
+		key crypto.Signer
+		err error
+	)
+	switch alg {
+	case "ES256":
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "RS256":
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	default:
+		return fmt.Errorf("unsupported -alg %q", alg)
+	}
+	if err != nil {
+		return err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return err
+	}
+
+	jwk := jose.JSONWebKey{Key: key.Public(), Algorithm: alg, Use: "sig"}
+	jwk.KeyID = keyID(jwk)
+	jwks, err := json.MarshalIndent(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{jwk}}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(jwksPath, append(jwks, '\n'), 0o644)
+}
+
+func readPrivateKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New(path + ": no PEM block found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New(path + ": unsupported key type")
+	}
+	return signer, nil
+}
+
+// keyID is the RFC 7638 thumbprint of the public key.
+func keyID(jwk jose.JSONWebKey) string {
+	tp, err := jwk.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(tp)
+}
+
+func split(s, sep string) []string {
+	out := []string{}
+	for _, v := range strings.Split(s, sep) {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "devtoken:", err)
+	os.Exit(1)
+}
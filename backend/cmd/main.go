@@ -2,22 +2,37 @@ package main
 
 import (
 	"context"
+	"crypto"
 	"database/sql"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata" // validate timezone preferences without relying on host zoneinfo
 
+	"connectrpc.com/connect"
 	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/deprecation"
+	"github.com/dcrespo1/book-list-app/events"
 	"github.com/dcrespo1/book-list-app/handlers"
+	"github.com/dcrespo1/book-list-app/idempotency"
+	"github.com/dcrespo1/book-list-app/jobs"
+	"github.com/dcrespo1/book-list-app/openapi"
 	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/pkg/proto/booklist/v1/booklistv1connect"
+	"github.com/dcrespo1/book-list-app/problem"
+	"github.com/dcrespo1/book-list-app/ratelimit"
+	"github.com/dcrespo1/book-list-app/stream"
+	"github.com/dcrespo1/book-list-app/users"
+	"github.com/dcrespo1/book-list-app/webhooks"
 	"github.com/go-chi/chi/v5"
 	chimw "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
-	"github.com/coreos/go-oidc/v3/oidc"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type config struct {
@@ -25,10 +40,27 @@ type config struct {
 	port                 string
 	keycloakIssuer       string // iss claim in tokens; what clients (Bruno/browser) use
 	keycloakDiscoveryURL string // where the server fetches OIDC config (differs inside devcontainer)
+	tokenPolicy          appauth.TokenPolicy
+	jwtKeysFile          string // JWKS or PEM file; enables offline verification
+	jwtPublicKeyPEM      string // inline PEM public keys; enables offline verification
+	trashRetention       time.Duration
+	idempotencyTTL       time.Duration
+	accountDeletionGrace time.Duration
+	rateLimitStore       string    // memory or postgres
+	proxyRateLimit       string    // <requests>/<duration> per caller on the Open Library proxies
+	apiRateLimit         string    // <requests>/<duration> per caller on signed-in routes
+	trustedProxies       []string  // IPs or CIDRs whose X-Forwarded-For is believed
+	openAPIValidate      bool      // check traffic against openapi/openapi.json
+	rootRoutes           bool      // serve the deprecated unversioned aliases of /v1
+	rootRoutesSunset     time.Time // advertised in the aliases' Sunset header
 }
 
+// rootRoutesDeprecated is when the unversioned routes were superseded by /v1.
+var rootRoutesDeprecated = time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
 func loadConfig() config {
 	issuer := getEnv("KEYCLOAK_ISSUER", "http://localhost:8180/realms/booklist")
+	clientID := getEnv("OIDC_CLIENT_ID", "booklist-api")
 	return config{
 		dbURL: "postgres://" +
 			getEnv("POSTGRES_USER", "app") + ":" +
@@ -39,6 +71,24 @@ func loadConfig() config {
 		port:                 getEnv("PORT", "8080"),
 		keycloakIssuer:       issuer,
 		keycloakDiscoveryURL: getEnv("KEYCLOAK_DISCOVERY_URL", issuer),
+		trashRetention:       getDuration("TRASH_RETENTION", "720h"),
+		idempotencyTTL:       getDuration("IDEMPOTENCY_TTL", "24h"),
+		accountDeletionGrace: getDuration("ACCOUNT_DELETION_GRACE", "720h"),
+		rateLimitStore:       getEnv("RATE_LIMIT_STORE", "memory"),
+		proxyRateLimit:       getEnv("RATE_LIMIT_PROXY", "60/1m"),
+		apiRateLimit:         getEnv("RATE_LIMIT_API", "300/1m"),
+		trustedProxies:       getList("TRUSTED_PROXIES", ""),
+		openAPIValidate:      getBool("OPENAPI_VALIDATE", false),
+		rootRoutes:           getBool("ROOT_ROUTES", true),
+		rootRoutesSunset:     getDate("ROOT_ROUTES_SUNSET", "2027-04-30"),
+		jwtKeysFile:          os.Getenv("JWT_KEYS_FILE"),
+		jwtPublicKeyPEM:      os.Getenv("JWT_PUBLIC_KEY_PEM"),
+		tokenPolicy: appauth.TokenPolicy{
+			// Unset allow-lists default to the app's own client, never to no check.
+			Audiences:         getList("OIDC_AUDIENCES", clientID),
+			AuthorizedParties: getList("OIDC_AUTHORIZED_PARTIES", clientID),
+			ClockSkew:         getDuration("OIDC_CLOCK_SKEW", ""),
+		},
 	}
 }
 
@@ -49,6 +99,52 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// getDuration reads a positive Go duration from the environment, exiting if it is malformed.
+// With an empty fallback the setting is optional and may be zero, which it is when unset.
+func getDuration(key, fallback string) time.Duration {
+	v := getEnv(key, fallback)
+	if v == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 || d == 0 && fallback != "" {
+		slog.Error("invalid duration", "key", key, "value", os.Getenv(key))
+		os.Exit(1)
+	}
+	return d
+}
+
+// getList reads a comma-separated list from the environment, dropping empty entries.
+func getList(key, fallback string) []string {
+	var out []string
+	for _, v := range strings.Split(getEnv(key, fallback), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// getBool reads a boolean from the environment, exiting if it is malformed.
+func getBool(key string, fallback bool) bool {
+	b, err := strconv.ParseBool(getEnv(key, strconv.FormatBool(fallback)))
+	if err != nil {
+		slog.Error("invalid boolean", "key", key, "value", os.Getenv(key))
+		os.Exit(1)
+	}
+	return b
+}
+
+// getDate reads a YYYY-MM-DD date (UTC) from the environment, exiting if it is malformed.
+func getDate(key, fallback string) time.Time {
+	d, err := time.Parse(time.DateOnly, getEnv(key, fallback))
+	if err != nil {
+		slog.Error("invalid date", "key", key, "value", os.Getenv(key))
+		os.Exit(1)
+	}
+	return d
+}
+
 func main() {
 	cfg := loadConfig()
 
@@ -65,75 +161,274 @@ func main() {
 		os.Exit(1)
 	}
 
-	// --- OIDC / Keycloak ---
-	// KEYCLOAK_DISCOVERY_URL may differ from KEYCLOAK_ISSUER when the server runs inside
-	// the devcontainer network and needs to reach Keycloak by service name (keycloak:8080)
-	// while tokens carry the public-facing issuer (localhost:8180).
-	oidcCtx := context.Background()
-	if cfg.keycloakDiscoveryURL != cfg.keycloakIssuer {
-		oidcCtx = oidc.InsecureIssuerURLContext(oidcCtx, cfg.keycloakDiscoveryURL)
-	}
+	// Background workers share this context and stop on shutdown.
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
 
-	var provider *oidc.Provider
-	for i := range 10 {
-		provider, err = oidc.NewProvider(oidcCtx, cfg.keycloakIssuer)
-		if err == nil {
-			break
+	// --- Auth ---
+	// With JWT_KEYS_FILE or JWT_PUBLIC_KEY_PEM set, tokens are verified against local
+	// keys (see cmd/devtoken) and Keycloak is never contacted. Otherwise Keycloak is
+	// discovered in the background; protected routes answer 503 until it is reached.
+	var verifier appauth.TokenVerifier
+	authReady := func() bool { return true }
+	if cfg.jwtKeysFile != "" || cfg.jwtPublicKeyPEM != "" {
+		keys, err := loadOfflineKeys(cfg)
+		if err != nil {
+			slog.Error("failed to load JWT verification keys", "error", err)
+			os.Exit(1)
+		}
+		slog.Warn("verifying tokens against local keys; Keycloak is not used", "issuer", cfg.keycloakIssuer, "keys", len(keys))
+		verifier = appauth.NewOfflineVerifier(cfg.keycloakIssuer, keys, cfg.tokenPolicy)
+	} else {
+		// KEYCLOAK_DISCOVERY_URL may differ from KEYCLOAK_ISSUER when the server runs inside
+		// the devcontainer network and needs to reach Keycloak by service name (keycloak:8080)
+		// while tokens carry the public-facing issuer (localhost:8180).
+		lazy := &appauth.LazyVerifier{
+			Issuer:       cfg.keycloakIssuer,
+			DiscoveryURL: cfg.keycloakDiscoveryURL,
+			Policy:       cfg.tokenPolicy,
 		}
-		slog.Warn("Keycloak not ready, retrying...", "attempt", i+1, "error", err)
-		time.Sleep(3 * time.Second)
+		go lazy.Run(workerCtx)
+		verifier = lazy
+		authReady = lazy.Ready
 	}
-	if err != nil {
-		slog.Error("failed to connect to Keycloak", "issuer", cfg.keycloakIssuer, "error", err)
+
+	// --- Live events ---
+	// A dedicated LISTEN connection per instance; pq reconnects it on its own.
+	listener := pq.NewListener(cfg.dbURL, 10*time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.Warn("readlist event listener", "event", ev, "error", err)
+		}
+	})
+	if err := listener.Listen(stream.Channel); err != nil {
+		slog.Error("failed to listen for readlist events", "error", err)
 		os.Exit(1)
 	}
-
-	verifier := appauth.NewOIDCVerifier(provider.Verifier(&oidc.Config{
-		SkipClientIDCheck: true,
-	}))
+	defer listener.Close()
+	broker := stream.NewBroker()
 
 	// --- Handlers ---
 	queries := database.New(db)
+	healthHandler := &handlers.HealthHandler{Ping: db.PingContext, AuthReady: authReady}
 	bookHandler := &handlers.BookHandler{}
-	readlistHandler := &handlers.ReadlistHandler{Queries: queries}
+	readlistHandler := &handlers.ReadlistHandler{
+		Queries: &handlers.DBBookStore{Queries: queries, DB: db},
+		Events:  func(tx handlers.BookStore) events.Publisher { return outbox(tx) },
+	}
+	eventStreamHandler := &handlers.EventStreamHandler{Queries: queries, Notifier: broker}
+	commentHandler := &handlers.CommentHandler{Queries: queries}
+	webhookHandler := &handlers.WebhookHandler{Queries: queries}
+	tokenHandler := &handlers.TokenHandler{Queries: queries}
+	loanHandler := &handlers.LoanHandler{Queries: queries}
+	householdHandler := &handlers.HouseholdHandler{Queries: &handlers.DBHouseholdStore{Queries: queries, DB: db}}
+	meHandler := &handlers.MeHandler{Queries: queries, DeletionGrace: cfg.accountDeletionGrace}
+	readlistServer := &handlers.ReadlistServer{Readlist: readlistHandler}
+	bookServer := &handlers.BookServer{Books: bookHandler}
+	accounts := &users.DBStore{Queries: queries, DB: db}
+
+	// --- Background workers ---
+	go broker.Run(workerCtx, listener)
+	go jobs.Every(workerCtx, "deliver-webhooks", 5*time.Second, (&webhooks.Dispatcher{Store: queries}).Deliver)
+	go jobs.Every(workerCtx, "prune-readlist-events", time.Hour, stream.Prune(queries, 24*time.Hour))
+	go jobs.Every(workerCtx, "purge-trash", time.Hour, jobs.PurgeTrash(queries, cfg.trashRetention))
+	go jobs.Every(workerCtx, "expire-idempotency-keys", time.Hour, jobs.ExpireIdempotencyKeys(queries))
+	go jobs.Every(workerCtx, "purge-deleted-accounts", time.Hour, jobs.PurgeDeletedAccounts(accounts))
+	go jobs.Every(workerCtx, "flag-overdue-loans", 24*time.Hour, jobs.FlagOverdueLoans(
+		&jobs.DBOverdueLoanFlagger{Queries: queries, DB: db},
+		func(tx jobs.LoanTx) events.Publisher { return outbox(tx) },
+	))
+
+	// Every authenticated request carries the caller's user row and preferences.
+	loadUser := users.Middleware(queries)
+
+	// Retried POSTs carrying an Idempotency-Key get the original response.
+	idempotent := idempotency.Middleware(queries, cfg.idempotencyTTL)
+
+	// Readlist and trash routes also accept personal access tokens, limited to their
+	// scopes. Everything else, including managing tokens, needs a Keycloak token.
+	patVerifier := &appauth.PersonalTokenVerifier{Store: queries, JWT: verifier}
+	patScopes := appauth.RequirePersonalTokenScopes(appauth.ScopeReadlistRead, appauth.ScopeReadlistWrite)
+
+	// --- Rate limits ---
+	// Each caller, by token sub or client IP, gets separate budgets for the Open Library
+	// proxies (protecting our standing with Open Library) and for signed-in routes.
+	proxyLimit, err := ratelimit.ParseLimit("proxy", cfg.proxyRateLimit)
+	if err != nil {
+		slog.Error("invalid RATE_LIMIT_PROXY", "error", err)
+		os.Exit(1)
+	}
+	apiLimit, err := ratelimit.ParseLimit("api", cfg.apiRateLimit)
+	if err != nil {
+		slog.Error("invalid RATE_LIMIT_API", "error", err)
+		os.Exit(1)
+	}
+	clientIP, err := ratelimit.NewClientIP(cfg.trustedProxies)
+	if err != nil {
+		slog.Error("invalid TRUSTED_PROXIES", "error", err)
+		os.Exit(1)
+	}
+	var limiterStore ratelimit.Store
+	switch cfg.rateLimitStore {
+	case "memory":
+		limiterStore = &ratelimit.MemoryStore{}
+	case "postgres":
+		limiterStore = &ratelimit.PostgresStore{Queries: queries}
+		go jobs.Every(workerCtx, "prune-rate-limit-buckets", time.Hour,
+			jobs.PruneRateLimitBuckets(queries, max(proxyLimit.Per, apiLimit.Per)))
+	default:
+		slog.Error("invalid RATE_LIMIT_STORE; want memory or postgres", "value", cfg.rateLimitStore)
+		os.Exit(1)
+	}
+	proxyRateLimit := ratelimit.Middleware(limiterStore, proxyLimit, clientIP)
+	apiRateLimit := ratelimit.Middleware(limiterStore, apiLimit, clientIP)
+
+	// A GraphQL request is charged to the API budget, and every Open Library call it
+	// fans out into to the proxy budget.
+	graphQLHandler := &handlers.GraphQLHandler{
+		Readlist: readlistHandler,
+		Books:    bookHandler,
+		AllowUpstream: func(r *http.Request) bool {
+			return ratelimit.Allow(r, limiterStore, proxyLimit, clientIP)
+		},
+	}
+
+	// With OPENAPI_VALIDATE, requests that break the spec are rejected; responses that
+	// break it are sent anyway and logged. Validation runs inside each route group,
+	// after authentication.
+	var validate func(http.Handler) http.Handler
+	if cfg.openAPIValidate {
+		validator, err := openapi.Load()
+		if err != nil {
+			slog.Error("failed to load OpenAPI spec", "error", err)
+			os.Exit(1)
+		}
+		validate = validator.Middleware(func(r *http.Request, err error) {
+			slog.Warn("response does not match the OpenAPI spec", "method", r.Method, "path", r.URL.Path, "error", err)
+		})
+	}
 
 	// --- Router ---
 	r := chi.NewRouter()
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins: []string{"http://localhost:5173"},
-		AllowedMethods: []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders: []string{"Authorization", "Content-Type"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders: []string{
+			"Authorization", "Content-Type", "Last-Event-ID", "If-Match", "If-None-Match", "Idempotency-Key",
+			"Connect-Protocol-Version", "Connect-Timeout-Ms", "Grpc-Timeout", "X-Grpc-Web", "X-User-Agent",
+		},
+		ExposedHeaders: []string{
+			"ETag", "Idempotent-Replayed", "WWW-Authenticate",
+			"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After",
+			"Deprecation", "Sunset", "Link",
+			"Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin",
+		},
 	}))
 	r.Use(chimw.RequestID)
 	r.Use(chimw.Logger)
 	r.Use(chimw.Recoverer)
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		problem.Write(w, r, problem.NotFound, "no route matches "+r.URL.Path)
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		problem.Write(w, r, problem.MethodNotAllowed, r.Method+" is not supported on "+r.URL.Path)
+	})
 
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		if err := db.PingContext(r.Context()); err != nil {
-			handlers.WriteJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "unhealthy"})
-			return
+	r.Group(func(r chi.Router) {
+		if validate != nil {
+			r.Use(validate)
 		}
-		handlers.WriteJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+		r.Get("/health", healthHandler.Health)
 	})
+	// Problem type URIs resolve here.
+	r.Get("/problems/{code}", problem.Describe)
+	r.Get("/openapi.json", openapi.ServeSpec)
+	r.Get("/docs", openapi.ServeDocs)
 
-	// Public — read-only Open Library proxies, no auth needed
-	r.Get("/search", bookHandler.Search)
-	r.Get("/details", bookHandler.Details)
-
-	// Protected — all readlist routes require a valid Keycloak token
-	r.Route("/readlist", func(r chi.Router) {
-		r.Use(appauth.AuthMiddleware(verifier))
-		r.Get("/", readlistHandler.GetReadlist)
-		r.Post("/", readlistHandler.AddToReadlist)
-		r.Get("/{workID}", readlistHandler.GetByWorkID)
-		r.Patch("/{id}", readlistHandler.PatchReadlist)
-		r.Delete("/{id}", readlistHandler.DeleteFromReadlist)
+	// Protected — GraphQL over the readlist and its Open Library metadata. It sits
+	// outside the REST versions; the schema evolves by deprecating fields instead.
+	// Personal access token scopes are checked per field, as every request is a POST.
+	r.Route("/graphql", func(r chi.Router) {
+		r.Use(appauth.AuthMiddleware(patVerifier))
+		r.Use(apiRateLimit)
+		r.Use(loadUser)
+		r.Post("/", graphQLHandler.ServeHTTP)
 	})
 
+	// Protected — the readlist and Open Library proxies as Connect services for
+	// internal clients, callable with gRPC, gRPC-Web or Connect (JSON or protobuf
+	// over HTTP). The proto package carries the version (booklist.v1). Interceptors
+	// stand in for the REST middleware so failures come back as RPC errors.
+	// Messages share the REST cap on request bodies.
+	rpcAuth := appauth.AuthInterceptor(patVerifier)
+	rpcLoadUser := users.Interceptor(queries)
+	rpcReadMax := connect.WithReadMaxBytes(handlers.MaxBodyBytes)
+	r.Mount(booklistv1connect.NewReadlistServiceHandler(readlistServer, rpcReadMax, connect.WithInterceptors(
+		rpcAuth,
+		ratelimit.Interceptor(limiterStore, apiLimit, clientIP),
+		appauth.PersonalTokenScopesInterceptor(appauth.ScopeReadlistRead, appauth.ScopeReadlistWrite),
+		rpcLoadUser,
+	)))
+	r.Mount(booklistv1connect.NewBookServiceHandler(bookServer, rpcReadMax, connect.WithInterceptors(
+		rpcAuth,
+		ratelimit.Interceptor(limiterStore, proxyLimit, clientIP),
+		rpcLoadUser,
+	)))
+
+	// API routes, shared by every version. Each version mounts them with its own
+	// response mapper, so a /v2 can sit beside /v1 and reuse the handlers, changing
+	// only how responses are rendered.
+	apiRoutes := &handlers.Routes{
+		Books:      bookHandler,
+		Readlist:   readlistHandler,
+		Events:     eventStreamHandler,
+		Loans:      loanHandler,
+		Comments:   commentHandler,
+		Webhooks:   webhookHandler,
+		Me:         meHandler,
+		Tokens:     tokenHandler,
+		Households: householdHandler,
+		Middleware: handlers.RouteMiddleware{
+			OptionalAuth:   appauth.OptionalAuthMiddleware(patVerifier),
+			TokenAuth:      appauth.AuthMiddleware(patVerifier),
+			TokenScopes:    patScopes,
+			Auth:           appauth.AuthMiddleware(verifier),
+			ProxyRateLimit: proxyRateLimit,
+			APIRateLimit:   apiRateLimit,
+			Validate:       validate,
+			LoadUser:       loadUser,
+			Idempotent:     idempotent,
+		},
+	}
+
+	r.Route("/v1", func(r chi.Router) {
+		r.Use(handlers.WithMapper(handlers.V1))
+		apiRoutes.API(r)
+	})
+
+	// Unversioned aliases of /v1 for clients written before versioning. They carry
+	// Deprecation and Sunset headers and go away once ROOT_ROUTES is turned off.
+	if cfg.rootRoutes {
+		r.Group(func(r chi.Router) {
+			r.Use(deprecation.Middleware(deprecation.Policy{
+				Since:     rootRoutesDeprecated,
+				Sunset:    cfg.rootRoutesSunset,
+				Successor: "/v1",
+			}))
+			r.Use(handlers.WithMapper(handlers.V1))
+			apiRoutes.API(r)
+		})
+	}
+
 	// --- Server ---
+	// HTTP/2 without TLS (h2c) lets gRPC clients, which need HTTP/2, connect when TLS
+	// is terminated in front of the server.
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
 	srv := &http.Server{
 		Addr:         "0.0.0.0:" + cfg.port,
 		Handler:      r,
+		Protocols:    protocols,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -152,6 +447,7 @@ func main() {
 	<-quit
 
 	slog.Info("shutting down...")
+	stopWorkers()
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
@@ -159,3 +455,33 @@ func main() {
 	}
 	slog.Info("server stopped")
 }
+
+// loadOfflineKeys reads the keys configured by JWT_KEYS_FILE and JWT_PUBLIC_KEY_PEM.
+func loadOfflineKeys(cfg config) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	if cfg.jwtKeysFile != "" {
+		k, err := appauth.LoadPublicKeys(cfg.jwtKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k...)
+	}
+	if cfg.jwtPublicKeyPEM != "" {
+		k, err := appauth.ParsePEMKeys([]byte(cfg.jwtPublicKeyPEM))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k...)
+	}
+	return keys, nil
+}
+
+// outbox returns the publisher for events raised in a transaction, given queries
+// bound to it. Events are recorded as webhook deliveries and on the readlist
+// stream in that transaction, so they are committed together with the change.
+func outbox(tx interface {
+	webhooks.Enqueuer
+	stream.Appender
+}) events.Publisher {
+	return events.Multi{&webhooks.Publisher{Queries: tx}, &stream.Publisher{Queries: tx}}
+}
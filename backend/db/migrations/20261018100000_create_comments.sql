@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Public discussion threads keyed by Open Library work_id, so every reader of
+-- the same work shares one thread regardless of whose readlist it sits on.
+CREATE TABLE comments (
+    id         SERIAL PRIMARY KEY,
+    work_id    TEXT NOT NULL,
+    user_id    TEXT NOT NULL,                         -- Keycloak sub of the author
+    parent_id  INTEGER REFERENCES comments (id),      -- NULL for top-level comments
+    body       TEXT NOT NULL,
+    spoiler    BOOLEAN NOT NULL DEFAULT FALSE,
+    chapter    INTEGER CHECK (chapter >= 1),          -- Position the comment refers to
+    page       INTEGER CHECK (page >= 1),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ                            -- Set instead of deleting so replies keep their parent
+);
+
+CREATE INDEX comments_work_id_idx ON comments (work_id, id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE comments;
+
+-- +goose StatementEnd
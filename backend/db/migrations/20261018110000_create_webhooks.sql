@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE webhook_subscriptions (
+    id          SERIAL PRIMARY KEY,
+    user_id     TEXT NOT NULL,
+    url         TEXT NOT NULL,
+    secret      TEXT NOT NULL,              -- HMAC-SHA256 key for the signature header
+    event_types TEXT[] NOT NULL,            -- e.g. {book.added,book.deleted}
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX webhook_subscriptions_user_id_idx ON webhook_subscriptions (user_id);
+
+-- Durable delivery queue. Rows stay behind after delivery and double as the log.
+CREATE TABLE webhook_deliveries (
+    id               SERIAL PRIMARY KEY,
+    subscription_id  INTEGER NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_type       TEXT NOT NULL,
+    payload          JSONB NOT NULL,
+    status           TEXT NOT NULL DEFAULT 'pending', -- pending, succeeded, failed
+    attempts         INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_status_code INTEGER,
+    last_error       TEXT,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at     TIMESTAMPTZ
+);
+
+CREATE INDEX webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX webhook_deliveries_subscription_id_idx ON webhook_deliveries (subscription_id, id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE webhook_deliveries;
+DROP TABLE webhook_subscriptions;
+
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Short-lived log of readlist changes backing GET /readlist/events. Clients resume
+-- with Last-Event-ID, so ids must be monotonic; rows are pruned after a retention window.
+CREATE TABLE readlist_events (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    TEXT NOT NULL,
+    type       TEXT NOT NULL,
+    payload    JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX readlist_events_user_id_idx ON readlist_events (user_id, id);
+
+-- Wake every backend instance listening on readlist_events. The payload is
+-- "<id>:<user_id>"; listeners read the event itself from the table.
+CREATE FUNCTION notify_readlist_event() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('readlist_events', NEW.id || ':' || NEW.user_id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER readlist_events_notify
+    AFTER INSERT ON readlist_events
+    FOR EACH ROW EXECUTE FUNCTION notify_readlist_event();
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TRIGGER readlist_events_notify ON readlist_events;
+DROP FUNCTION notify_readlist_event();
+DROP TABLE readlist_events;
+
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- version backs the ETag on readlist entries; every update bumps it so
+-- concurrent edits can be detected with If-Match.
+ALTER TABLE books
+    ADD COLUMN version    INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE books
+    DROP COLUMN updated_at,
+    DROP COLUMN version;
+
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE books ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- Trashed rows must not block re-adding the same work, so uniqueness only
+-- applies to live entries.
+ALTER TABLE books DROP CONSTRAINT books_work_id_user_id_key;
+CREATE UNIQUE INDEX books_work_id_user_id_key ON books (work_id, user_id) WHERE deleted_at IS NULL;
+
+CREATE INDEX books_deleted_at_idx ON books (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DELETE FROM books WHERE deleted_at IS NOT NULL;
+DROP INDEX books_deleted_at_idx;
+DROP INDEX books_work_id_user_id_key;
+ALTER TABLE books ADD CONSTRAINT books_work_id_user_id_key UNIQUE (work_id, user_id);
+ALTER TABLE books DROP COLUMN deleted_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Responses to POST requests sent with an Idempotency-Key, replayed when a client
+-- retries with the same key. status_code is NULL while the first request is still
+-- being handled. Rows are deleted once expires_at has passed.
+CREATE TABLE idempotency_keys (
+    user_id          TEXT NOT NULL,
+    key              TEXT NOT NULL,
+    request_hash     TEXT NOT NULL,
+    status_code      INT,
+    response_headers JSONB NOT NULL DEFAULT '{}',
+    response_body    BYTEA NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at       TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE idempotency_keys;
+-- +goose StatementEnd
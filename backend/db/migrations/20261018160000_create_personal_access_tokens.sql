@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Long-lived tokens users create for scripts. Only a SHA-256 hash of the token is
+-- stored; prefix keeps enough of it to tell tokens apart in listings.
+CREATE TABLE personal_access_tokens (
+    id           SERIAL PRIMARY KEY,
+    user_id      TEXT NOT NULL,
+    name         TEXT NOT NULL,
+    token_hash   TEXT NOT NULL UNIQUE,
+    prefix       TEXT NOT NULL,
+    scopes       TEXT[] NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at   TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ
+);
+
+CREATE INDEX personal_access_tokens_user_id_idx ON personal_access_tokens (user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE personal_access_tokens;
+-- +goose StatementEnd
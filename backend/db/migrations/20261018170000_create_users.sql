@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- One row per Keycloak subject, created on the first authenticated request and kept
+-- in step with the token claims. Other tables keep referring to users by user_id.
+CREATE TABLE users (
+    user_id        TEXT PRIMARY KEY,
+    username       TEXT,
+    email          TEXT,
+    name           TEXT,
+    default_sort   TEXT NOT NULL DEFAULT 'added_desc',
+    privacy        TEXT NOT NULL DEFAULT 'private',      -- private, public
+    default_status TEXT NOT NULL DEFAULT 'want_to_read',
+    timezone       TEXT NOT NULL DEFAULT 'UTC',          -- IANA zone name
+    language       TEXT,                                 -- ISO 639-1 code for book metadata
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE users;
+-- +goose StatementEnd
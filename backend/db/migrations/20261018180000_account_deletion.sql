@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Account deletion is requested, confirmed with a short-lived token, and then carried
+-- out by a background job once the grace period has passed.
+ALTER TABLE users
+    ADD COLUMN deletion_token_hash       TEXT,
+    ADD COLUMN deletion_token_expires_at TIMESTAMPTZ,
+    ADD COLUMN deletion_scheduled_for    TIMESTAMPTZ;
+
+CREATE INDEX users_deletion_scheduled_for_idx ON users (deletion_scheduled_for)
+    WHERE deletion_scheduled_for IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX users_deletion_scheduled_for_idx;
+ALTER TABLE users
+    DROP COLUMN deletion_token_hash,
+    DROP COLUMN deletion_token_expires_at,
+    DROP COLUMN deletion_scheduled_for;
+-- +goose StatementEnd
@@ -0,0 +1,67 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- A household shares one physical library. Each member keeps their own readlist
+-- (status, rating, notes) in books; the copies the household owns live here.
+CREATE TABLE households (
+    id         SERIAL PRIMARY KEY,
+    name       TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE household_members (
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    user_id      TEXT NOT NULL,
+    role         TEXT NOT NULL DEFAULT 'member', -- owner, admin, member
+    joined_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (household_id, user_id)
+);
+
+CREATE INDEX household_members_user_id_idx ON household_members (user_id);
+
+-- Each household has exactly one owner.
+CREATE UNIQUE INDEX household_members_owner_idx ON household_members (household_id) WHERE role = 'owner';
+
+-- Single-use invites. Only a SHA-256 hash of the token is stored.
+CREATE TABLE household_invites (
+    id           SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    token_hash   TEXT NOT NULL UNIQUE,
+    role         TEXT NOT NULL,                 -- admin, member
+    created_by   TEXT NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at   TIMESTAMPTZ NOT NULL,
+    accepted_by  TEXT,
+    accepted_at  TIMESTAMPTZ
+);
+
+CREATE INDEX household_invites_household_id_idx ON household_invites (household_id);
+
+-- A physical (or digital) copy of a work owned by the household. A household may
+-- own several copies of the same work.
+CREATE TABLE household_copies (
+    id            SERIAL PRIMARY KEY,
+    household_id  INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    work_id       TEXT NOT NULL,
+    title         TEXT NOT NULL,
+    authors       TEXT NOT NULL,
+    cover_art_url TEXT,
+    format        TEXT NOT NULL,                -- hardcover, paperback, ebook, audiobook, other
+    condition     TEXT,                         -- new, like_new, good, fair, poor
+    location      TEXT,                         -- free text, e.g. "living room, top shelf"
+    added_by      TEXT NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX household_copies_household_id_idx ON household_copies (household_id, work_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE household_copies;
+DROP TABLE household_invites;
+DROP TABLE household_members;
+DROP TABLE households;
+-- +goose StatementEnd
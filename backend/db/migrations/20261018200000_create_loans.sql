@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Loans of a readlist entry's physical copy. The borrower is a free-text name, a
+-- linked user, or both. Dates are calendar dates in the lender's time zone.
+CREATE TABLE loans (
+    id                 SERIAL PRIMARY KEY,
+    book_id            INTEGER NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    user_id            TEXT NOT NULL,           -- the lender; owns the readlist entry
+    borrower_name      TEXT,
+    borrower_user_id   TEXT,
+    lent_on            DATE NOT NULL,
+    due_on             DATE,
+    returned_on        DATE,
+    notes              TEXT,
+    overdue_flagged_at TIMESTAMPTZ,             -- set once loan.overdue has been emitted
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX loans_user_id_idx ON loans (user_id, lent_on);
+CREATE INDEX loans_borrower_user_id_idx ON loans (borrower_user_id) WHERE borrower_user_id IS NOT NULL;
+CREATE INDEX loans_overdue_idx ON loans (due_on) WHERE returned_on IS NULL AND overdue_flagged_at IS NULL;
+
+-- A copy can only be lent to one borrower at a time.
+CREATE UNIQUE INDEX loans_outstanding_book_idx ON loans (book_id) WHERE returned_on IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE loans;
+-- +goose StatementEnd
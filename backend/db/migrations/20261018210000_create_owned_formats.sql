@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- The formats in which a user owns a readlist entry, with how each was acquired.
+-- A user owns a work in a given format at most once.
+CREATE TABLE owned_formats (
+    book_id      INTEGER NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    user_id      TEXT NOT NULL,
+    format       TEXT NOT NULL,           -- hardcover, paperback, ebook, audiobook, other
+    purchased_on DATE,
+    price_cents  INTEGER,                 -- in the minor unit of currency
+    currency     TEXT,                    -- ISO 4217 code; set whenever price_cents is
+    store        TEXT,
+    source       TEXT,                    -- purchase, gift, trade, giveaway, other
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (book_id, format)
+);
+
+CREATE INDEX owned_formats_user_id_idx ON owned_formats (user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE owned_formats;
+-- +goose StatementEnd
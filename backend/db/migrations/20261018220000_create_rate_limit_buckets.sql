@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Token buckets shared by every API instance when RATE_LIMIT_STORE=postgres. A key
+-- names a budget and a caller, e.g. "proxy:ip:203.0.113.7". allowed records whether
+-- the last request took a token.
+CREATE UNLOGGED TABLE rate_limit_buckets (
+    key        TEXT PRIMARY KEY,
+    tokens     DOUBLE PRECISION NOT NULL,
+    allowed    BOOLEAN NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX rate_limit_buckets_updated_at_idx ON rate_limit_buckets (updated_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE rate_limit_buckets;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Deleted comments used to keep their body, hidden only in responses.
+UPDATE comments SET body = '' WHERE deleted_at IS NOT NULL AND body <> '';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- Erased bodies cannot be restored.
+SELECT 1;
+
+-- +goose StatementEnd
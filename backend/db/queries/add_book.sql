@@ -1,4 +1,4 @@
 -- name: AddBook :one
-INSERT INTO books (user_id, title, authors, subjects, description, cover_art_url, work_id)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO books (user_id, title, authors, subjects, description, cover_art_url, work_id, status)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING id;
@@ -0,0 +1,4 @@
+-- name: AddComment :one
+INSERT INTO comments (work_id, user_id, parent_id, body, spoiler, chapter, page)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
@@ -0,0 +1,4 @@
+-- name: AddHouseholdMember :one
+INSERT INTO household_members (household_id, user_id, role)
+VALUES ($1, $2, $3)
+RETURNING *;
@@ -0,0 +1,6 @@
+-- name: AnonymizeUserComments :exec
+-- Comments stay in their threads as deleted placeholders so replies keep their
+-- parent, but lose their body and author.
+UPDATE comments
+SET user_id = '', body = '', deleted_at = COALESCE(deleted_at, NOW())
+WHERE user_id = $1;
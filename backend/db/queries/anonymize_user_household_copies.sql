@@ -0,0 +1,3 @@
+-- name: AnonymizeUserHouseholdCopies :exec
+-- Copies belong to the household, so they stay when the member who added them goes.
+UPDATE household_copies SET added_by = '' WHERE added_by = $1;
@@ -0,0 +1,4 @@
+-- name: CancelAccountDeletion :execrows
+UPDATE users
+SET deletion_scheduled_for = NULL
+WHERE user_id = $1 AND deletion_scheduled_for IS NOT NULL;
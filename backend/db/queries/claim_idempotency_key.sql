@@ -0,0 +1,13 @@
+-- name: ClaimIdempotencyKey :execrows
+-- Records a new key, or takes over an expired one. Affects no rows if the key is
+-- already held, in which case the caller should look it up.
+INSERT INTO idempotency_keys (user_id, key, request_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, key) DO UPDATE
+SET request_hash     = EXCLUDED.request_hash,
+    status_code      = NULL,
+    response_headers = '{}',
+    response_body    = '',
+    created_at       = NOW(),
+    expires_at       = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at <= NOW();
@@ -0,0 +1,19 @@
+-- name: ClaimWebhookDeliveries :many
+-- Leases due deliveries by pushing next_attempt_at forward, so a crashed worker's
+-- batch is retried after the lease and concurrent workers never share a row. The
+-- lease is measured on the database clock, which is also what decides when a row
+-- is due. The new next_attempt_at is returned as leased_until: recording the
+-- outcome requires it to be unchanged, so a worker whose lease ran out cannot
+-- overwrite the result of the worker that claimed the row after it.
+WITH due AS (
+    SELECT d.id FROM webhook_deliveries d
+    WHERE d.status = 'pending' AND d.next_attempt_at <= NOW()
+    ORDER BY d.next_attempt_at
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE webhook_deliveries
+SET next_attempt_at = NOW() + sqlc.arg(lease_seconds)::int * INTERVAL '1 second'
+FROM due, webhook_subscriptions s
+WHERE webhook_deliveries.id = due.id AND s.id = webhook_deliveries.subscription_id
+RETURNING webhook_deliveries.id, webhook_deliveries.event_type, webhook_deliveries.payload, webhook_deliveries.attempts, webhook_deliveries.next_attempt_at AS leased_until, s.url, s.secret;
@@ -0,0 +1,6 @@
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET status_code      = $1,
+    response_headers = $2,
+    response_body    = $3
+WHERE user_id = $4 AND key = $5;
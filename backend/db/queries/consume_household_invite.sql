@@ -0,0 +1,7 @@
+-- name: ConsumeHouseholdInvite :one
+-- Marks an unused, unexpired invite as accepted. No rows means the token is
+-- unknown, used or expired.
+UPDATE household_invites
+SET accepted_by = $2, accepted_at = NOW()
+WHERE token_hash = $1 AND accepted_at IS NULL AND expires_at > NOW()
+RETURNING *;
@@ -0,0 +1,3 @@
+-- name: CreateHousehold :one
+INSERT INTO households (name) VALUES ($1)
+RETURNING *;
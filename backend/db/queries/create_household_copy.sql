@@ -0,0 +1,5 @@
+-- name: CreateHouseholdCopy :one
+INSERT INTO household_copies (
+    household_id, work_id, title, authors, cover_art_url, format, condition, location, added_by
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
@@ -0,0 +1,4 @@
+-- name: CreateHouseholdInvite :one
+INSERT INTO household_invites (household_id, token_hash, role, created_by, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
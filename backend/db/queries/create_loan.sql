@@ -0,0 +1,4 @@
+-- name: CreateLoan :one
+INSERT INTO loans (book_id, user_id, borrower_name, borrower_user_id, lent_on, due_on, notes)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
@@ -0,0 +1,4 @@
+-- name: CreatePersonalAccessToken :one
+INSERT INTO personal_access_tokens (user_id, name, token_hash, prefix, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
@@ -0,0 +1,4 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (user_id, url, secret, event_types)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
@@ -1,2 +1,10 @@
 -- name: DeleteBookByID :execrows
-DELETE FROM books WHERE id = $1 AND user_id = $2;
+-- Moves the entry to the trash; PurgeTrashedBooks removes it for good later.
+-- When if_match is non-empty the row must currently be at one of those versions;
+-- an empty or NULL if_match (a nil Go slice) accepts any version.
+UPDATE books
+SET deleted_at = NOW(),
+    version    = version + 1,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id) AND deleted_at IS NULL
+  AND (COALESCE(cardinality(sqlc.arg(if_match)::int[]), 0) = 0 OR version = ANY(sqlc.arg(if_match)::int[]));
@@ -0,0 +1,6 @@
+-- name: DeleteComment :execrows
+-- The comment stays in its thread as a deleted placeholder so replies keep their
+-- parent, but its body is erased.
+UPDATE comments
+SET body = '', deleted_at = NOW()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- name: DeleteEmptyHousehold :exec
+DELETE FROM households
+WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM household_members WHERE household_id = $1);
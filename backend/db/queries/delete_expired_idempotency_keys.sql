@@ -0,0 +1,2 @@
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys WHERE expires_at < $1;
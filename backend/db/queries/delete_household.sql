@@ -0,0 +1,3 @@
+-- name: DeleteHousehold :execrows
+-- Members, invites and copies are removed by ON DELETE CASCADE.
+DELETE FROM households WHERE id = $1;
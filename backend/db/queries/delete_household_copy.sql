@@ -0,0 +1,2 @@
+-- name: DeleteHouseholdCopy :execrows
+DELETE FROM household_copies WHERE id = $1 AND household_id = $2;
@@ -0,0 +1,2 @@
+-- name: DeleteIdleRateLimitBuckets :execrows
+DELETE FROM rate_limit_buckets WHERE updated_at < $1;
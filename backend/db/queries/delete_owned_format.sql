@@ -0,0 +1,2 @@
+-- name: DeleteOwnedFormat :execrows
+DELETE FROM owned_formats WHERE book_id = $1 AND format = $2 AND user_id = $3;
@@ -0,0 +1,2 @@
+-- name: DeleteReadlistEventsBefore :execrows
+DELETE FROM readlist_events WHERE created_at < $1;
@@ -0,0 +1,6 @@
+-- name: EnqueueWebhookDeliveries :execrows
+-- Queues one delivery per subscription of the user that listens for the event type.
+INSERT INTO webhook_deliveries (subscription_id, event_type, payload)
+SELECT id, sqlc.arg(event_type)::text, sqlc.arg(payload)::jsonb
+FROM webhook_subscriptions
+WHERE user_id = sqlc.arg(user_id) AND sqlc.arg(event_type)::text = ANY(event_types);
@@ -0,0 +1,3 @@
+-- name: ExportBooks :many
+-- Includes entries in the trash.
+SELECT * FROM books WHERE user_id = $1 ORDER BY id;
@@ -0,0 +1,2 @@
+-- name: ExportComments :many
+SELECT * FROM comments WHERE user_id = $1 ORDER BY id;
@@ -0,0 +1,2 @@
+-- name: ExportHouseholdCopies :many
+SELECT * FROM household_copies WHERE added_by = $1 ORDER BY id;
@@ -0,0 +1,2 @@
+-- name: ExportHouseholdInvites :many
+SELECT * FROM household_invites WHERE created_by = $1 ORDER BY id;
@@ -0,0 +1,2 @@
+-- name: ExportHouseholdMemberships :many
+SELECT * FROM household_members WHERE user_id = $1 ORDER BY joined_at;
@@ -0,0 +1,2 @@
+-- name: ExportIdempotencyKeys :many
+SELECT * FROM idempotency_keys WHERE user_id = $1 ORDER BY created_at;
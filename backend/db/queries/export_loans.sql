@@ -0,0 +1,3 @@
+-- name: ExportLoans :many
+-- Includes loans to the user from others who linked them as the borrower.
+SELECT * FROM loans WHERE user_id = $1 OR borrower_user_id = $1 ORDER BY id;
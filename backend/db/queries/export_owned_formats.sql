@@ -0,0 +1,2 @@
+-- name: ExportOwnedFormats :many
+SELECT * FROM owned_formats WHERE user_id = $1 ORDER BY book_id, format;
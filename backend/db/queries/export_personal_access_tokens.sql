@@ -0,0 +1,3 @@
+-- name: ExportPersonalAccessTokens :many
+-- Includes revoked and expired tokens.
+SELECT * FROM personal_access_tokens WHERE user_id = $1 ORDER BY id;
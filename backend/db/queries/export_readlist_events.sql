@@ -0,0 +1,2 @@
+-- name: ExportReadlistEvents :many
+SELECT * FROM readlist_events WHERE user_id = $1 ORDER BY id;
@@ -0,0 +1,5 @@
+-- name: ExportWebhookDeliveries :many
+SELECT d.* FROM webhook_deliveries d
+JOIN webhook_subscriptions s ON s.id = d.subscription_id
+WHERE s.user_id = $1
+ORDER BY d.id;
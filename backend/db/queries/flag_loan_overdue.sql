@@ -0,0 +1,4 @@
+-- name: FlagLoanOverdue :execrows
+-- Claims a loan's loan.overdue event. Affects no row if the loan has already been
+-- flagged, by an earlier run or by another instance.
+UPDATE loans SET overdue_flagged_at = NOW() WHERE id = $1 AND overdue_flagged_at IS NULL;
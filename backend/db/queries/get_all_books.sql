@@ -1,2 +1,2 @@
 -- name: GetAllBooks :many
-SELECT * FROM books WHERE user_id = $1 ORDER BY id DESC;
+SELECT * FROM books WHERE user_id = $1 AND deleted_at IS NULL ORDER BY id DESC;
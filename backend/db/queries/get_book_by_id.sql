@@ -1,2 +1,2 @@
 -- name: GetBookByID :one
-SELECT * FROM books WHERE id = $1 AND user_id = $2;
+SELECT * FROM books WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL;
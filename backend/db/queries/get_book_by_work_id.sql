@@ -1,2 +1,2 @@
 -- name: GetBookByWorkID :one
-SELECT * FROM books WHERE work_id = $1 AND user_id = $2;
+SELECT * FROM books WHERE work_id = $1 AND user_id = $2 AND deleted_at IS NULL;
@@ -0,0 +1,2 @@
+-- name: GetCommentByID :one
+SELECT * FROM comments WHERE id = $1;
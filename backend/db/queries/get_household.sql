@@ -0,0 +1,2 @@
+-- name: GetHousehold :one
+SELECT * FROM households WHERE id = $1;
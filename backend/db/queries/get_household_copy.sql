@@ -0,0 +1,2 @@
+-- name: GetHouseholdCopy :one
+SELECT * FROM household_copies WHERE id = $1 AND household_id = $2;
@@ -0,0 +1,2 @@
+-- name: GetHouseholdMember :one
+SELECT * FROM household_members WHERE household_id = $1 AND user_id = $2;
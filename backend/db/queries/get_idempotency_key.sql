@@ -0,0 +1,2 @@
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys WHERE user_id = $1 AND key = $2;
@@ -0,0 +1,2 @@
+-- name: GetLatestReadlistEventID :one
+SELECT COALESCE(MAX(id), 0)::bigint FROM readlist_events WHERE user_id = $1;
@@ -0,0 +1,2 @@
+-- name: GetLoan :one
+SELECT * FROM loans WHERE id = $1 AND user_id = $2;
@@ -0,0 +1,2 @@
+-- name: GetWebhookSubscription :one
+SELECT * FROM webhook_subscriptions WHERE id = $1 AND user_id = $2;
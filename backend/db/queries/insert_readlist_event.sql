@@ -0,0 +1,13 @@
+-- name: InsertReadlistEvent :one
+-- Appends an event to a user's log. Ids are drawn when a row is inserted, not when
+-- it commits, so two concurrent inserts could become visible out of id order and a
+-- stream that had already moved past the later id would never see the earlier one.
+-- The transaction-scoped advisory lock serialises a user's inserts until commit:
+-- each draws its id only once the previous one is visible, so per user the ids
+-- follow commit order.
+WITH lock AS (
+    SELECT pg_advisory_xact_lock(hashtextextended('readlist_events:' || $1::text, 0))
+)
+INSERT INTO readlist_events (user_id, type, payload)
+SELECT $1::text, $2::text, $3::jsonb FROM lock
+RETURNING id;
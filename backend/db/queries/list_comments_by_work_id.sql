@@ -0,0 +1,13 @@
+-- name: ListCommentsByWorkID :many
+-- Comments whose chapter/page marker lies beyond the reader's position are
+-- excluded, as are spoilers unless the reader opted in or has reached them.
+SELECT * FROM comments
+WHERE work_id = sqlc.arg(work_id)
+  AND (sqlc.narg(chapter)::int IS NULL OR chapter IS NULL OR chapter <= sqlc.narg(chapter)::int)
+  AND (sqlc.narg(page)::int IS NULL OR page IS NULL OR page <= sqlc.narg(page)::int)
+  AND (NOT spoiler
+       OR sqlc.arg(include_spoilers)::bool
+       OR chapter <= sqlc.narg(chapter)::int
+       OR page <= sqlc.narg(page)::int)
+ORDER BY id
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
@@ -0,0 +1,4 @@
+-- name: ListDueAccountDeletions :many
+SELECT user_id FROM users
+WHERE deletion_scheduled_for <= $1
+ORDER BY deletion_scheduled_for;
@@ -0,0 +1,7 @@
+-- name: ListHouseholdCopies :many
+-- Each copy carries user_id's own readlist status and rating for its work, if any.
+SELECT c.*, b.status AS readlist_status, b.rating AS readlist_rating
+FROM household_copies c
+LEFT JOIN books b ON b.work_id = c.work_id AND b.user_id = @user_id AND b.deleted_at IS NULL
+WHERE c.household_id = @household_id
+ORDER BY c.title, c.id;
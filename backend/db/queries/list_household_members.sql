@@ -0,0 +1,6 @@
+-- name: ListHouseholdMembers :many
+SELECT m.household_id, m.user_id, m.role, m.joined_at, u.username, u.name
+FROM household_members m
+LEFT JOIN users u ON u.user_id = m.user_id
+WHERE m.household_id = $1
+ORDER BY m.joined_at, m.user_id;
@@ -0,0 +1,6 @@
+-- name: ListHouseholdsForUser :many
+SELECT h.id, h.name, h.created_at, m.role
+FROM households h
+JOIN household_members m ON m.household_id = h.id
+WHERE m.user_id = $1
+ORDER BY h.name, h.id;
@@ -0,0 +1,17 @@
+-- name: ListLoans :many
+-- status is outstanding, overdue (outstanding and due before today) or returned;
+-- anything else lists every loan. Loans of trashed entries are left out until the
+-- entry is restored.
+SELECT l.*, b.work_id, b.title
+FROM loans l
+JOIN books b ON b.id = l.book_id
+WHERE l.user_id = @user_id
+  AND b.deleted_at IS NULL
+  AND CASE @status::text
+        WHEN 'outstanding' THEN l.returned_on IS NULL
+        WHEN 'overdue' THEN l.returned_on IS NULL AND l.due_on < @today::date
+        WHEN 'returned' THEN l.returned_on IS NOT NULL
+        ELSE TRUE
+      END
+ORDER BY l.lent_on DESC, l.id DESC
+LIMIT @page_limit OFFSET @page_offset;
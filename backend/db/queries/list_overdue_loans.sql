@@ -0,0 +1,13 @@
+-- name: ListOverdueLoans :many
+-- Outstanding loans past their due date in the lender's time zone that have not
+-- been flagged yet. Trashed entries are skipped; their loans are flagged once the
+-- entry is restored.
+SELECT l.*, b.work_id, b.title
+FROM loans l
+JOIN books b ON b.id = l.book_id
+LEFT JOIN users u ON u.user_id = l.user_id
+WHERE l.returned_on IS NULL
+  AND l.overdue_flagged_at IS NULL
+  AND b.deleted_at IS NULL
+  AND l.due_on < (@now::timestamptz AT TIME ZONE COALESCE(u.timezone, 'UTC'))::date
+ORDER BY l.due_on, l.id;
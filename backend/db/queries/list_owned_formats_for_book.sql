@@ -0,0 +1,2 @@
+-- name: ListOwnedFormatsForBook :many
+SELECT * FROM owned_formats WHERE book_id = $1 AND user_id = $2 ORDER BY format;
@@ -0,0 +1,4 @@
+-- name: ListPersonalAccessTokens :many
+SELECT * FROM personal_access_tokens
+WHERE user_id = $1 AND revoked_at IS NULL
+ORDER BY id;
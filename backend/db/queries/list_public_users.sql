@@ -0,0 +1,4 @@
+-- name: ListPublicUsers :many
+-- Returns the users among user_ids who chose to be shown by name to others.
+SELECT user_id, username, name FROM users
+WHERE user_id = ANY(@user_ids::text[]) AND privacy = 'public';
@@ -0,0 +1,5 @@
+-- name: ListReadlistEventsSince :many
+SELECT * FROM readlist_events
+WHERE user_id = $1 AND id > $2
+ORDER BY id
+LIMIT $3;
@@ -0,0 +1,2 @@
+-- name: ListTrashedBooks :many
+SELECT * FROM books WHERE user_id = $1 AND deleted_at IS NOT NULL ORDER BY deleted_at DESC;
@@ -0,0 +1,2 @@
+-- name: ListWebhookSubscriptions :many
+SELECT * FROM webhook_subscriptions WHERE user_id = $1 ORDER BY id;
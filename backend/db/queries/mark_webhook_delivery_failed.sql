@@ -0,0 +1,12 @@
+-- name: MarkWebhookDeliveryFailed :execrows
+-- Records a failed attempt if the caller still holds the lease from
+-- ClaimWebhookDeliveries; affects no row otherwise.
+UPDATE webhook_deliveries
+SET status           = sqlc.arg(status),
+    attempts         = attempts + 1,
+    next_attempt_at  = sqlc.arg(next_attempt_at),
+    last_status_code = sqlc.arg(last_status_code),
+    last_error       = sqlc.arg(last_error)
+WHERE id = sqlc.arg(id)
+  AND status = 'pending'
+  AND next_attempt_at = sqlc.arg(leased_until)::timestamptz;
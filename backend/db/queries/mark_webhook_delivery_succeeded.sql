@@ -0,0 +1,12 @@
+-- name: MarkWebhookDeliverySucceeded :execrows
+-- Records a successful attempt if the caller still holds the lease from
+-- ClaimWebhookDeliveries; affects no row otherwise.
+UPDATE webhook_deliveries
+SET status           = 'succeeded',
+    attempts         = attempts + 1,
+    last_status_code = sqlc.arg(last_status_code),
+    last_error       = NULL,
+    delivered_at     = NOW()
+WHERE id = sqlc.arg(id)
+  AND status = 'pending'
+  AND next_attempt_at = sqlc.arg(leased_until)::timestamptz;
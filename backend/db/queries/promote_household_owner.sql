@@ -0,0 +1,11 @@
+-- name: PromoteHouseholdOwner :exec
+-- Makes the longest-standing member the owner of a household left without one.
+UPDATE household_members SET role = 'owner'
+WHERE household_id = $1
+  AND user_id = (
+    SELECT user_id FROM household_members
+    WHERE household_id = $1
+    ORDER BY joined_at, user_id
+    LIMIT 1
+  )
+  AND NOT EXISTS (SELECT 1 FROM household_members WHERE household_id = $1 AND role = 'owner');
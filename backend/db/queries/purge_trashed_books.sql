@@ -0,0 +1,6 @@
+-- name: PurgeTrashedBooks :execrows
+-- Purging an entry deletes its loans with it, so an entry with a loan outstanding
+-- stays in the trash until the copy is returned.
+DELETE FROM books
+WHERE deleted_at < $1
+  AND NOT EXISTS (SELECT 1 FROM loans l WHERE l.book_id = books.id AND l.returned_on IS NULL);
@@ -0,0 +1,2 @@
+-- name: PurgeUser :exec
+DELETE FROM users WHERE user_id = $1;
@@ -0,0 +1,2 @@
+-- name: PurgeUserBooks :exec
+DELETE FROM books WHERE user_id = $1;
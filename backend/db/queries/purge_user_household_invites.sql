@@ -0,0 +1,2 @@
+-- name: PurgeUserHouseholdInvites :exec
+DELETE FROM household_invites WHERE created_by = $1 OR accepted_by = $1;
@@ -0,0 +1,3 @@
+-- name: PurgeUserHouseholdMemberships :many
+DELETE FROM household_members WHERE user_id = $1
+RETURNING household_id;
@@ -0,0 +1,2 @@
+-- name: PurgeUserIdempotencyKeys :exec
+DELETE FROM idempotency_keys WHERE user_id = $1;
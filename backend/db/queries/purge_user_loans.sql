@@ -0,0 +1,2 @@
+-- name: PurgeUserLoans :exec
+DELETE FROM loans WHERE user_id = $1;
@@ -0,0 +1,2 @@
+-- name: PurgeUserOwnedFormats :exec
+DELETE FROM owned_formats WHERE user_id = $1;
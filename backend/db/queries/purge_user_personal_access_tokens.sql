@@ -0,0 +1,2 @@
+-- name: PurgeUserPersonalAccessTokens :exec
+DELETE FROM personal_access_tokens WHERE user_id = $1;
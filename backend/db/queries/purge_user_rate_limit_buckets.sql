@@ -0,0 +1,6 @@
+-- name: PurgeUserRateLimitBuckets :exec
+-- Signed-in callers' buckets are keyed "<limit>:sub:<user id>"; see
+-- ratelimit.bucketKey. Buckets keyed by IP are not tied to a user.
+DELETE FROM rate_limit_buckets
+WHERE position(':sub:' IN key) > 0
+  AND substr(key, position(':sub:' IN key) + 5) = sqlc.arg(user_id)::text;
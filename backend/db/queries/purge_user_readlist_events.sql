@@ -0,0 +1,2 @@
+-- name: PurgeUserReadlistEvents :exec
+DELETE FROM readlist_events WHERE user_id = $1;
@@ -0,0 +1,3 @@
+-- name: PurgeUserWebhookSubscriptions :exec
+-- Deliveries are removed by ON DELETE CASCADE.
+DELETE FROM webhook_subscriptions WHERE user_id = $1;
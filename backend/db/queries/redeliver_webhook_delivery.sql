@@ -0,0 +1,7 @@
+-- name: RedeliverWebhookDelivery :one
+-- Queues a fresh copy of an earlier delivery, leaving the original in the log.
+INSERT INTO webhook_deliveries (subscription_id, event_type, payload)
+SELECT subscription_id, event_type, payload
+FROM webhook_deliveries
+WHERE webhook_deliveries.id = $1 AND webhook_deliveries.subscription_id = $2
+RETURNING *;
@@ -0,0 +1,2 @@
+-- name: ReleaseIdempotencyKey :exec
+DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2;
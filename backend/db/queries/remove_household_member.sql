@@ -0,0 +1,2 @@
+-- name: RemoveHouseholdMember :execrows
+DELETE FROM household_members WHERE household_id = $1 AND user_id = $2;
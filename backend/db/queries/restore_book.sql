@@ -0,0 +1,7 @@
+-- name: RestoreBook :one
+UPDATE books
+SET deleted_at = NULL,
+    version    = version + 1,
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+RETURNING *;
@@ -0,0 +1,4 @@
+-- name: ReturnLoan :one
+UPDATE loans SET returned_on = $3
+WHERE id = $1 AND user_id = $2 AND returned_on IS NULL
+RETURNING *;
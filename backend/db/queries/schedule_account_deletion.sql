@@ -0,0 +1,8 @@
+-- name: ScheduleAccountDeletion :one
+-- Consumes the confirmation token and schedules the purge.
+UPDATE users
+SET deletion_scheduled_for    = $2,
+    deletion_token_hash       = NULL,
+    deletion_token_expires_at = NULL
+WHERE user_id = $1
+RETURNING *;
@@ -0,0 +1,4 @@
+-- name: SetAccountDeletionToken :exec
+UPDATE users
+SET deletion_token_hash = $2, deletion_token_expires_at = $3
+WHERE user_id = $1;
@@ -0,0 +1,4 @@
+-- name: SetHouseholdMemberRole :one
+UPDATE household_members SET role = $3
+WHERE household_id = $1 AND user_id = $2
+RETURNING *;
@@ -0,0 +1,10 @@
+-- name: TakeRateLimitToken :one
+-- Refills the bucket for the time since its last request, at rate tokens per
+-- second up to burst, then takes a token if one is available.
+INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+VALUES (@key, @burst::float8 - 1, TRUE, NOW())
+ON CONFLICT (key) DO UPDATE
+SET tokens = LEAST(@burst::float8, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::float8 * @rate::float8) - CASE WHEN LEAST(@burst::float8, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::float8 * @rate::float8) >= 1 THEN 1 ELSE 0 END,
+    allowed = LEAST(@burst::float8, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::float8 * @rate::float8) >= 1,
+    updated_at = NOW()
+RETURNING tokens, allowed;
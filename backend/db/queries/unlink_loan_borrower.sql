@@ -0,0 +1,3 @@
+-- name: UnlinkLoanBorrower :exec
+-- Loans stay with their lender; the borrower's name, if given, is kept.
+UPDATE loans SET borrower_user_id = NULL WHERE borrower_user_id = $1;
@@ -1,7 +1,20 @@
 -- name: UpdateBook :one
+-- Applies a partial update and bumps the version, in one statement. status is
+-- left alone when NULL; rating and notes are only written (possibly to NULL)
+-- when their set_ flag is true. When if_match is non-empty the row must
+-- currently be at one of those versions; an empty or NULL if_match (a nil Go
+-- slice) accepts any version. previous_status is the status before this update.
 UPDATE books
-SET status = $1,
-    rating = $2,
-    notes  = $3
-WHERE id = $4 AND user_id = $5
-RETURNING *;
+SET status     = COALESCE(sqlc.narg(status)::text, books.status),
+    rating     = CASE WHEN sqlc.arg(set_rating)::bool THEN sqlc.narg(rating)::int ELSE books.rating END,
+    notes      = CASE WHEN sqlc.arg(set_notes)::bool THEN sqlc.narg(notes)::text ELSE books.notes END,
+    version    = books.version + 1,
+    updated_at = NOW()
+FROM (
+    SELECT b.id, b.status FROM books b
+    WHERE b.id = sqlc.arg(id) AND b.user_id = sqlc.arg(user_id) AND b.deleted_at IS NULL
+    FOR UPDATE
+) AS prev
+WHERE books.id = prev.id
+  AND (COALESCE(cardinality(sqlc.arg(if_match)::int[]), 0) = 0 OR books.version = ANY(sqlc.arg(if_match)::int[]))
+RETURNING books.*, prev.status AS previous_status;
@@ -0,0 +1,9 @@
+-- name: UpdateComment :one
+UPDATE comments
+SET body       = $1,
+    spoiler    = $2,
+    chapter    = $3,
+    page       = $4,
+    updated_at = NOW()
+WHERE id = $5 AND user_id = $6 AND deleted_at IS NULL
+RETURNING *;
@@ -0,0 +1,8 @@
+-- name: UpdateHouseholdCopy :one
+UPDATE household_copies
+SET format     = $3,
+    condition  = $4,
+    location   = $5,
+    updated_at = NOW()
+WHERE id = $1 AND household_id = $2
+RETURNING *;
@@ -0,0 +1,10 @@
+-- name: UpdateUserPreferences :one
+UPDATE users
+SET default_sort   = $2,
+    privacy        = $3,
+    default_status = $4,
+    timezone       = $5,
+    language       = $6,
+    updated_at     = NOW()
+WHERE user_id = $1
+RETURNING *;
@@ -0,0 +1,11 @@
+-- name: UpsertOwnedFormat :one
+INSERT INTO owned_formats (book_id, user_id, format, purchased_on, price_cents, currency, store, source)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (book_id, format) DO UPDATE
+SET purchased_on = EXCLUDED.purchased_on,
+    price_cents = EXCLUDED.price_cents,
+    currency = EXCLUDED.currency,
+    store = EXCLUDED.store,
+    source = EXCLUDED.source,
+    updated_at = NOW()
+RETURNING *;
@@ -0,0 +1,11 @@
+-- name: UpsertUser :one
+-- Creates the user on first sight; later calls refresh the profile claims, keeping
+-- stored values for claims the token did not carry.
+INSERT INTO users (user_id, username, email, name)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE
+SET username   = COALESCE(EXCLUDED.username, users.username),
+    email      = COALESCE(EXCLUDED.email, users.email),
+    name       = COALESCE(EXCLUDED.name, users.name),
+    updated_at = NOW()
+RETURNING *;
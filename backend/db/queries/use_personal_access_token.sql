@@ -0,0 +1,8 @@
+-- name: UsePersonalAccessToken :one
+-- Looks up a live token by hash and records that it was used.
+UPDATE personal_access_tokens
+SET last_used_at = NOW()
+WHERE token_hash = $1
+  AND revoked_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+RETURNING *;
@@ -0,0 +1,36 @@
+// Package deprecation marks routes that are still served but due to be removed,
+// using the Deprecation (RFC 9745), Sunset (RFC 8594) and Link headers.
+package deprecation
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Policy describes when a set of routes was deprecated and what replaces them.
+type Policy struct {
+	// Since is when the routes were deprecated.
+	Since time.Time
+	// Sunset is when they are expected to stop being served.
+	Sunset time.Time
+	// Successor prefixes the request path to give the route that replaces it, as
+	// in "/v1" for /readlist becoming /v1/readlist.
+	Successor string
+}
+
+// Middleware adds p's deprecation headers to every response. Requests are served
+// as usual.
+func Middleware(p Policy) func(http.Handler) http.Handler {
+	deprecation := "@" + strconv.FormatInt(p.Since.Unix(), 10)
+	sunset := p.Sunset.UTC().Format(http.TimeFormat)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			h.Set("Deprecation", deprecation)
+			h.Set("Sunset", sunset)
+			h.Add("Link", "<"+p.Successor+r.URL.Path+`>; rel="successor-version"`)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
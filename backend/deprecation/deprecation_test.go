@@ -0,0 +1,36 @@
+package deprecation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMiddleware(t *testing.T) {
+	p := Policy{
+		Since:     time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+		Sunset:    time.Date(2027, 4, 30, 0, 0, 0, 0, time.UTC),
+		Successor: "/v1",
+	}
+	h := Middleware(p)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readlist/OL1W?x=1", nil))
+
+	if w.Code != http.StatusTeapot {
+		t.Errorf("status: got %d, want the handler's %d", w.Code, http.StatusTeapot)
+	}
+	want := map[string]string{
+		"Deprecation": "@1792281600",
+		"Sunset":      "Fri, 30 Apr 2027 00:00:00 GMT",
+		"Link":        `</v1/readlist/OL1W>; rel="successor-version"`,
+	}
+	for k, v := range want {
+		if got := w.Header().Get(k); got != v {
+			t.Errorf("%s: got %q, want %q", k, got, v)
+		}
+	}
+}
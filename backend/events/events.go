@@ -0,0 +1,62 @@
+// Package events carries readlist change notifications from the handlers to
+// whatever fans them out: webhook deliveries and the live readlist stream.
+// Handlers depend only on Publisher.
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Event types emitted by the readlist handlers.
+const (
+	BookAdded         = "book.added"
+	BookUpdated       = "book.updated"
+	BookStatusChanged = "book.status_changed"
+	BookDeleted       = "book.deleted"
+)
+
+// LoanOverdue is emitted once per loan by the overdue-loans job when a lent copy
+// passes its due date.
+const LoanOverdue = "loan.overdue"
+
+// Types lists every event type a subscriber may ask for.
+var Types = []string{BookAdded, BookUpdated, BookStatusChanged, BookDeleted, LoanOverdue}
+
+// Valid reports whether t is a known event type.
+func Valid(t string) bool {
+	for _, known := range Types {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a single change to one user's readlist. Data is marshalled to JSON as-is.
+type Event struct {
+	Type       string    `json:"type"`
+	UserID     string    `json:"-"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// Publisher accepts events for delivery. Implementations must not block on
+// slow consumers; the handlers call Publish inline.
+type Publisher interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+// Multi fans an event out to several publishers, returning every error joined.
+type Multi []Publisher
+
+func (m Multi) Publish(ctx context.Context, e Event) error {
+	var errs []error
+	for _, p := range m {
+		if err := p.Publish(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
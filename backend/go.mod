@@ -7,10 +7,20 @@ require (
 	github.com/lib/pq v1.10.9
 )
 
-require github.com/go-chi/cors v1.2.2 // indirect
+require (
+	connectrpc.com/connect v1.19.1
+	github.com/go-chi/cors v1.2.2
+	github.com/graph-gophers/dataloader v5.0.0+incompatible
+	github.com/graph-gophers/graphql-go v1.9.0
+	github.com/santhosh-tekuri/jsonschema/v6 v6.0.2
+	golang.org/x/text v0.14.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260720211330-0afa2a65878a
+	google.golang.org/protobuf v1.36.11
+)
 
 require (
 	github.com/coreos/go-oidc/v3 v3.18.0
-	github.com/go-jose/go-jose/v4 v4.1.4 // indirect
+	github.com/go-jose/go-jose/v4 v4.1.4
+	github.com/opentracing/opentracing-go v1.2.0 // indirect
 	golang.org/x/oauth2 v0.36.0 // indirect
 )
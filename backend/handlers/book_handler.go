@@ -6,6 +6,9 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+
+	"github.com/dcrespo1/book-list-app/problem"
+	"github.com/dcrespo1/book-list-app/users"
 )
 
 type BookHandler struct {
@@ -40,16 +43,27 @@ func (h *BookHandler) openLibraryURL() string {
 	return "https://openlibrary.org"
 }
 
+// Search proxies Open Library search. Results favour editions in the language given
+// by the lang query parameter or, for signed-in callers, their language preference.
 func (h *BookHandler) Search(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
 	if query == "" {
-		WriteError(w, http.StatusBadRequest, "missing query parameter 'q'")
+		problem.Write(w, r, problem.InvalidParameter, "missing query parameter 'q'")
+		return
+	}
+	lang := r.URL.Query().Get("lang")
+	if lang == "" {
+		prefs, _ := users.FromContext(r.Context())
+		lang = prefs.Language.String
+	}
+	if lang != "" && !languageCode.MatchString(lang) {
+		problem.Write(w, r, problem.InvalidParameter, "lang must be a two-letter ISO 639-1 code")
 		return
 	}
 
-	books, err := h.SearchBooks(query)
+	books, err := h.SearchBooks(query, lang)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, "failed to search books")
+		problem.Write(w, r, problem.Internal, "failed to search books")
 		return
 	}
 
@@ -59,26 +73,31 @@ func (h *BookHandler) Search(w http.ResponseWriter, r *http.Request) {
 func (h *BookHandler) Details(w http.ResponseWriter, r *http.Request) {
 	workID := r.URL.Query().Get("id")
 	if workID == "" {
-		WriteError(w, http.StatusBadRequest, "missing query parameter 'id'")
+		problem.Write(w, r, problem.InvalidParameter, "missing query parameter 'id'")
 		return
 	}
 
 	details, err := h.GetBookDetails(workID)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, "failed to fetch book details")
+		problem.Write(w, r, problem.Internal, "failed to fetch book details")
 		return
 	}
 
 	WriteJSON(w, http.StatusOK, details)
 }
 
-func (h *BookHandler) SearchBooks(query string) ([]Book, error) {
+// SearchBooks queries Open Library. lang is optional.
+func (h *BookHandler) SearchBooks(query, lang string) ([]Book, error) {
 	reqURL, err := url.Parse(h.openLibraryURL() + "/search.json")
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse base URL: %w", err)
 	}
 
-	reqURL.RawQuery = url.Values{"q": {query}}.Encode()
+	params := url.Values{"q": {query}}
+	if lang != "" {
+		params.Set("lang", lang)
+	}
+	reqURL.RawQuery = params.Encode()
 
 	resp, err := http.Get(reqURL.String())
 	if err != nil {
@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/users"
 )
 
 func newTestBookServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *BookHandler) {
@@ -34,7 +38,7 @@ func TestSearchBooks_ParsesResponse(t *testing.T) {
 		})
 	})
 
-	books, err := h.SearchBooks("dune")
+	books, err := h.SearchBooks("dune", "")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -61,12 +65,42 @@ func TestSearchBooks_NonOKResponse(t *testing.T) {
 		w.WriteHeader(http.StatusServiceUnavailable)
 	})
 
-	_, err := h.SearchBooks("anything")
+	_, err := h.SearchBooks("anything", "")
 	if err == nil {
 		t.Fatal("expected error for non-200 response, got nil")
 	}
 }
 
+func TestSearch_UsesLanguagePreference(t *testing.T) {
+	var gotLang string
+	_, h := newTestBookServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gotLang = r.URL.Query().Get("lang")
+		json.NewEncoder(w).Encode(map[string]any{"docs": []any{}})
+	})
+	prefs := database.User{Language: sql.NullString{String: "fr", Valid: true}}
+
+	cases := []struct {
+		target string
+		want   string
+	}{
+		{"/search?q=dune", "fr"},
+		{"/search?q=dune&lang=de", "de"},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+		r = r.WithContext(users.ToContext(r.Context(), prefs))
+		w := httptest.NewRecorder()
+		h.Search(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status: got %d, want %d", tc.target, w.Code, http.StatusOK)
+		}
+		if gotLang != tc.want {
+			t.Errorf("%s: lang: got %q, want %q", tc.target, gotLang, tc.want)
+		}
+	}
+}
+
 func TestGetBookDetails_StringDescription(t *testing.T) {
 	_, h := newTestBookServer(t, func(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(map[string]any{
@@ -0,0 +1,388 @@
+package handlers
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/problem"
+	"github.com/go-chi/chi/v5"
+)
+
+// CommentStore is the persistence interface for discussion threads. *database.Queries satisfies it.
+type CommentStore interface {
+	AddComment(ctx context.Context, arg database.AddCommentParams) (database.Comment, error)
+	GetCommentByID(ctx context.Context, id int32) (database.Comment, error)
+	ListCommentsByWorkID(ctx context.Context, arg database.ListCommentsByWorkIDParams) ([]database.Comment, error)
+	UpdateComment(ctx context.Context, arg database.UpdateCommentParams) (database.Comment, error)
+	DeleteComment(ctx context.Context, arg database.DeleteCommentParams) (int64, error)
+	ListPublicUsers(ctx context.Context, userIds []string) ([]database.ListPublicUsersRow, error)
+}
+
+const maxCommentLength = 10000
+
+type CommentHandler struct {
+	Queries CommentStore
+}
+
+type commentListResponse struct {
+	Comments   []CommentResponse `json:"comments"`
+	Limit      int32             `json:"limit"`
+	Offset     int32             `json:"offset"`
+	NextOffset *int32            `json:"next_offset"`
+}
+
+// ListComments returns the thread for a work. The reader's position is passed as
+// chapter and/or page query parameters; comments marked beyond it are hidden, and
+// spoilers are hidden unless include_spoilers=true. Authors who made their profile
+// public are shown by name.
+func (h *CommentHandler) ListComments(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidParameter, err.Error())
+		return
+	}
+
+	q := r.URL.Query()
+	chapter, err := parsePositionParam(q.Get("chapter"))
+	if err != nil {
+		problem.Write(w, r, problem.InvalidParameter, "chapter must be a positive integer")
+		return
+	}
+	page, err := parsePositionParam(q.Get("page"))
+	if err != nil {
+		problem.Write(w, r, problem.InvalidParameter, "page must be a positive integer")
+		return
+	}
+
+	// Fetch one extra row to learn whether another page exists.
+	comments, err := h.Queries.ListCommentsByWorkID(r.Context(), database.ListCommentsByWorkIDParams{
+		WorkID:          chi.URLParam(r, "workID"),
+		Chapter:         chapter,
+		Page:            page,
+		IncludeSpoilers: q.Get("include_spoilers") == "true",
+		RowLimit:        limit + 1,
+		RowOffset:       offset,
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to retrieve comments")
+		return
+	}
+
+	resp := commentListResponse{Comments: []CommentResponse{}, Limit: limit, Offset: offset}
+	if int32(len(comments)) > limit {
+		comments = comments[:limit]
+		next := offset + limit
+		resp.NextOffset = &next
+	}
+	names, err := h.authorNames(r.Context(), comments)
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to retrieve comment authors")
+		return
+	}
+	for _, c := range comments {
+		cr := toCommentResponse(c)
+		if name, ok := names[c.UserID]; ok {
+			cr.AuthorName = &name
+		}
+		resp.Comments = append(resp.Comments, cr)
+	}
+	WriteJSON(w, http.StatusOK, resp)
+}
+
+// authorNames maps the authors of comments who chose public profiles to their
+// display name: their name, or else their username.
+func (h *CommentHandler) authorNames(ctx context.Context, comments []database.Comment) (map[string]string, error) {
+	var ids []string
+	for _, c := range comments {
+		if !slices.Contains(ids, c.UserID) {
+			ids = append(ids, c.UserID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	public, err := h.Queries.ListPublicUsers(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(public))
+	for _, u := range public {
+		if name := cmp.Or(u.Name.String, u.Username.String); name != "" {
+			names[u.UserID] = name
+		}
+	}
+	return names, nil
+}
+
+func (h *CommentHandler) AddComment(w http.ResponseWriter, r *http.Request) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+
+	var input struct {
+		Body     string `json:"body"`
+		ParentID *int32 `json:"parent_id"`
+		Spoiler  bool   `json:"spoiler"`
+		Chapter  *int32 `json:"chapter"`
+		Page     *int32 `json:"page"`
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+	if errs := validateComment(input.Body, input.Chapter, input.Page); len(errs) > 0 {
+		problem.WriteInvalid(w, r, errs)
+		return
+	}
+
+	workID := chi.URLParam(r, "workID")
+	if input.ParentID != nil {
+		// Unknown, deleted and other works' comments are refused alike.
+		parent, err := h.Queries.GetCommentByID(r.Context(), *input.ParentID)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && (parent.WorkID != workID || parent.DeletedAt.Valid)) {
+			problem.WriteInvalid(w, r, fieldErrors{"parent_id": "must reference a comment on the same work that has not been deleted"})
+			return
+		}
+		if err != nil {
+			problem.Write(w, r, problem.Internal, "failed to retrieve parent comment")
+			return
+		}
+	}
+
+	comment, err := h.Queries.AddComment(r.Context(), database.AddCommentParams{
+		WorkID:   workID,
+		UserID:   sub,
+		ParentID: toNullInt32(input.ParentID),
+		Body:     input.Body,
+		Spoiler:  input.Spoiler,
+		Chapter:  toNullInt32(input.Chapter),
+		Page:     toNullInt32(input.Page),
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to add comment")
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, toCommentResponse(comment))
+}
+
+// PatchComment edits a comment with a JSON merge patch. Only its author may do so.
+// Absent members are left unchanged; chapter and page may be null to clear the
+// reader position the comment is marked at.
+func (h *CommentHandler) PatchComment(w http.ResponseWriter, r *http.Request) {
+	current, sub, ok := h.loadOwnComment(w, r)
+	if !ok {
+		return
+	}
+
+	body, ok := readMergePatch(w, r)
+	if !ok {
+		return
+	}
+	patch, errs, err := decodeCommentPatch(body)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidBody, err.Error())
+		return
+	}
+
+	params := database.UpdateCommentParams{
+		ID:      current.ID,
+		UserID:  sub,
+		Body:    current.Body,
+		Spoiler: current.Spoiler,
+		Chapter: current.Chapter,
+		Page:    current.Page,
+	}
+	if patch.Body.Set {
+		params.Body = *patch.Body.Value
+	}
+	if patch.Spoiler.Set {
+		params.Spoiler = *patch.Spoiler.Value
+	}
+	if patch.Chapter.Set {
+		params.Chapter = toNullInt32(patch.Chapter.Value)
+	}
+	if patch.Page.Set {
+		params.Page = toNullInt32(patch.Page.Value)
+	}
+
+	var chapter, page *int32
+	if params.Chapter.Valid {
+		chapter = &params.Chapter.Int32
+	}
+	if params.Page.Valid {
+		page = &params.Page.Int32
+	}
+	maps.Copy(errs, validateComment(params.Body, chapter, page))
+	if len(errs) > 0 {
+		problem.WriteInvalid(w, r, errs)
+		return
+	}
+
+	updated, err := h.Queries.UpdateComment(r.Context(), params)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Deleted between the ownership check and the update.
+		problem.Write(w, r, problem.NotFound, "comment not found")
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to update comment")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, toCommentResponse(updated))
+}
+
+// commentPatch is the set of changes a PATCH request makes to a comment.
+type commentPatch struct {
+	Body    optional[string]
+	Spoiler optional[bool]
+	Chapter optional[int32]
+	Page    optional[int32]
+}
+
+// decodeCommentPatch parses a merge patch of a comment. A body that is not a JSON
+// object is returned as err; problems with individual members are collected in
+// fieldErrors.
+func decodeCommentPatch(body []byte) (commentPatch, fieldErrors, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(body, &doc); err != nil || doc == nil {
+		return commentPatch{}, nil, errors.New("merge patch must be a JSON object")
+	}
+
+	var p commentPatch
+	errs := fieldErrors{}
+	for name, raw := range doc {
+		isNull := bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+		switch name {
+		case "body":
+			var v string
+			if isNull || json.Unmarshal(raw, &v) != nil {
+				errs[name] = "must be a string"
+			} else {
+				p.Body = optional[string]{Set: true, Value: &v}
+			}
+		case "spoiler":
+			var v bool
+			if isNull || json.Unmarshal(raw, &v) != nil {
+				errs[name] = "must be a boolean"
+			} else {
+				p.Spoiler = optional[bool]{Set: true, Value: &v}
+			}
+		case "chapter", "page":
+			var v int32
+			position := optional[int32]{Set: true}
+			if !isNull {
+				if json.Unmarshal(raw, &v) != nil {
+					errs[name] = "must be a positive integer"
+					continue
+				}
+				position.Value = &v
+			}
+			if name == "chapter" {
+				p.Chapter = position
+			} else {
+				p.Page = position
+			}
+		default:
+			errs[name] = "is not a known field"
+		}
+	}
+	return p, errs, nil
+}
+
+// DeleteComment removes a comment's body while keeping it in the thread. Only its
+// author may do so.
+func (h *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	current, sub, ok := h.loadOwnComment(w, r)
+	if !ok {
+		return
+	}
+
+	n, err := h.Queries.DeleteComment(r.Context(), database.DeleteCommentParams{
+		ID:     current.ID,
+		UserID: sub,
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to delete comment")
+		return
+	}
+	if n == 0 {
+		problem.Write(w, r, problem.NotFound, "comment not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadOwnComment resolves the {id} URL parameter to a live comment authored by the
+// caller, writing the appropriate error response and returning ok=false otherwise.
+func (h *CommentHandler) loadOwnComment(w http.ResponseWriter, r *http.Request) (database.Comment, string, bool) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return database.Comment{}, "", false
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidParameter, "invalid id")
+		return database.Comment{}, "", false
+	}
+
+	comment, err := h.Queries.GetCommentByID(r.Context(), int32(id))
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && comment.DeletedAt.Valid) {
+		problem.Write(w, r, problem.NotFound, "comment not found")
+		return database.Comment{}, "", false
+	}
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to retrieve comment")
+		return database.Comment{}, "", false
+	}
+	if comment.UserID != sub {
+		problem.Write(w, r, problem.Forbidden, "only the author can modify this comment")
+		return database.Comment{}, "", false
+	}
+
+	return comment, sub, true
+}
+
+func validateComment(body string, chapter, page *int32) fieldErrors {
+	errs := fieldErrors{}
+	switch {
+	case body == "":
+		errs["body"] = "is required"
+	case utf8.RuneCountInString(body) > maxCommentLength:
+		errs["body"] = "must be at most 10000 characters"
+	}
+	if chapter != nil && *chapter < 1 {
+		errs["chapter"] = "must be a positive integer"
+	}
+	if page != nil && *page < 1 {
+		errs["page"] = "must be a positive integer"
+	}
+	return errs
+}
+
+func parsePositionParam(v string) (sql.NullInt32, error) {
+	if v == "" {
+		return sql.NullInt32{}, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 32)
+	if err != nil || n < 1 {
+		return sql.NullInt32{}, errors.New("invalid position")
+	}
+	return sql.NullInt32{Int32: int32(n), Valid: true}, nil
+}
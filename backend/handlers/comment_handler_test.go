@@ -0,0 +1,465 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dcrespo1/book-list-app/pkg/database"
+)
+
+// fakeCommentStore is a test double for CommentStore.
+type fakeCommentStore struct {
+	comments   []database.Comment
+	listArg    database.ListCommentsByWorkIDParams
+	addArg     database.AddCommentParams
+	updateArg  database.UpdateCommentParams
+	listErr    error
+	addErr     error
+	updateErr  error
+	deleteErr  error
+	deletedIDs []int32
+	// public are the users with a public profile.
+	public []database.ListPublicUsersRow
+}
+
+func (f *fakeCommentStore) AddComment(_ context.Context, arg database.AddCommentParams) (database.Comment, error) {
+	f.addArg = arg
+	if f.addErr != nil {
+		return database.Comment{}, f.addErr
+	}
+	return database.Comment{ID: 100, WorkID: arg.WorkID, UserID: arg.UserID, ParentID: arg.ParentID, Body: arg.Body}, nil
+}
+
+func (f *fakeCommentStore) GetCommentByID(_ context.Context, id int32) (database.Comment, error) {
+	for _, c := range f.comments {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return database.Comment{}, sql.ErrNoRows
+}
+
+func (f *fakeCommentStore) ListCommentsByWorkID(_ context.Context, arg database.ListCommentsByWorkIDParams) ([]database.Comment, error) {
+	f.listArg = arg
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	var out []database.Comment
+	for _, c := range f.comments {
+		if c.WorkID == arg.WorkID {
+			out = append(out, c)
+		}
+	}
+	if int32(len(out)) > arg.RowLimit {
+		out = out[:arg.RowLimit]
+	}
+	return out, nil
+}
+
+func (f *fakeCommentStore) UpdateComment(_ context.Context, arg database.UpdateCommentParams) (database.Comment, error) {
+	f.updateArg = arg
+	if f.updateErr != nil {
+		return database.Comment{}, f.updateErr
+	}
+	return database.Comment{ID: arg.ID, UserID: arg.UserID, Body: arg.Body, Spoiler: arg.Spoiler}, nil
+}
+
+func (f *fakeCommentStore) DeleteComment(_ context.Context, arg database.DeleteCommentParams) (int64, error) {
+	if f.deleteErr != nil {
+		return 0, f.deleteErr
+	}
+	f.deletedIDs = append(f.deletedIDs, arg.ID)
+	return 1, nil
+}
+
+func (f *fakeCommentStore) ListPublicUsers(_ context.Context, userIds []string) ([]database.ListPublicUsersRow, error) {
+	var out []database.ListPublicUsersRow
+	for _, u := range f.public {
+		if slices.Contains(userIds, u.UserID) {
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+func seedComments() []database.Comment {
+	return []database.Comment{
+		{ID: 1, WorkID: "OL12345W", UserID: testSub, Body: "Loved the opening"},
+		{ID: 2, WorkID: "OL12345W", UserID: "someone-else", Body: "Same here", ParentID: sql.NullInt32{Int32: 1, Valid: true}},
+		{ID: 3, WorkID: "OL99999W", UserID: testSub, Body: "Different book"},
+	}
+}
+
+func commentRequest(method, target, param, val, body string) *http.Request {
+	return withSub(withChiParam(httptest.NewRequest(method, target, bytes.NewBufferString(body)), param, val), testSub)
+}
+
+// --- ListComments ---
+
+func TestListComments_ReturnsThreadForWork(t *testing.T) {
+	store := &fakeCommentStore{comments: seedComments()}
+	h := &CommentHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.ListComments(w, commentRequest(http.MethodGet, "/works/OL12345W/comments", "workID", "OL12345W", ""))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	var got commentListResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if len(got.Comments) != 2 {
+		t.Fatalf("expected 2 comments, got %d", len(got.Comments))
+	}
+	if got.Comments[1].ParentID == nil || *got.Comments[1].ParentID != 1 {
+		t.Errorf("parent_id: got %v, want 1", got.Comments[1].ParentID)
+	}
+	if got.NextOffset != nil {
+		t.Errorf("next_offset: got %d, want nil", *got.NextOffset)
+	}
+}
+
+func TestListComments_NamesPublicAuthors(t *testing.T) {
+	store := &fakeCommentStore{
+		comments: seedComments(),
+		public: []database.ListPublicUsersRow{
+			{UserID: "someone-else", Username: sql.NullString{String: "bob", Valid: true}},
+		},
+	}
+	h := &CommentHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.ListComments(w, commentRequest(http.MethodGet, "/works/OL12345W/comments", "workID", "OL12345W", ""))
+
+	var got commentListResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if len(got.Comments) != 2 {
+		t.Fatalf("expected 2 comments, got %d", len(got.Comments))
+	}
+	if got.Comments[0].AuthorName != nil {
+		t.Errorf("private author named: %q", *got.Comments[0].AuthorName)
+	}
+	if got.Comments[1].AuthorName == nil || *got.Comments[1].AuthorName != "bob" {
+		t.Errorf("author_name: got %v, want bob", got.Comments[1].AuthorName)
+	}
+}
+
+func TestListComments_PassesReaderPosition(t *testing.T) {
+	store := &fakeCommentStore{}
+	h := &CommentHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.ListComments(w, commentRequest(http.MethodGet, "/works/OL12345W/comments?chapter=3&page=120&include_spoilers=true", "workID", "OL12345W", ""))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	arg := store.listArg
+	if !arg.Chapter.Valid || arg.Chapter.Int32 != 3 {
+		t.Errorf("chapter: got %+v, want 3", arg.Chapter)
+	}
+	if !arg.Page.Valid || arg.Page.Int32 != 120 {
+		t.Errorf("page: got %+v, want 120", arg.Page)
+	}
+	if !arg.IncludeSpoilers {
+		t.Error("expected include_spoilers to be passed through")
+	}
+}
+
+func TestListComments_Pagination(t *testing.T) {
+	store := &fakeCommentStore{comments: seedComments()}
+	h := &CommentHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.ListComments(w, commentRequest(http.MethodGet, "/works/OL12345W/comments?limit=1", "workID", "OL12345W", ""))
+
+	var got commentListResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if len(got.Comments) != 1 {
+		t.Fatalf("expected 1 comment, got %d", len(got.Comments))
+	}
+	if got.NextOffset == nil || *got.NextOffset != 1 {
+		t.Errorf("next_offset: got %v, want 1", got.NextOffset)
+	}
+}
+
+func TestListComments_InvalidParams(t *testing.T) {
+	cases := []string{"limit=0", "limit=101", "offset=-1", "chapter=0", "page=abc"}
+	for _, q := range cases {
+		t.Run(q, func(t *testing.T) {
+			h := &CommentHandler{Queries: &fakeCommentStore{}}
+			w := httptest.NewRecorder()
+			h.ListComments(w, commentRequest(http.MethodGet, "/works/OL12345W/comments?"+q, "workID", "OL12345W", ""))
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status: got %d, want %d", w.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestListComments_DeletedCommentHasNoBody(t *testing.T) {
+	comments := []database.Comment{
+		{ID: 1, WorkID: "OL12345W", UserID: testSub, Body: "secret", DeletedAt: sql.NullTime{Valid: true}},
+	}
+	h := &CommentHandler{Queries: &fakeCommentStore{comments: comments}}
+
+	w := httptest.NewRecorder()
+	h.ListComments(w, commentRequest(http.MethodGet, "/works/OL12345W/comments", "workID", "OL12345W", ""))
+
+	if strings.Contains(w.Body.String(), "secret") {
+		t.Error("deleted comment body leaked into response")
+	}
+}
+
+func TestListComments_DBError(t *testing.T) {
+	h := &CommentHandler{Queries: &fakeCommentStore{listErr: errors.New("db down")}}
+
+	w := httptest.NewRecorder()
+	h.ListComments(w, commentRequest(http.MethodGet, "/works/OL12345W/comments", "workID", "OL12345W", ""))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
+
+// --- AddComment ---
+
+func TestAddComment_Success(t *testing.T) {
+	store := &fakeCommentStore{comments: seedComments()}
+	h := &CommentHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.AddComment(w, commentRequest(http.MethodPost, "/works/OL12345W/comments", "workID", "OL12345W",
+		`{"body":"Chapter 3 twist!","parent_id":1,"spoiler":true,"chapter":3}`))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusCreated)
+	}
+	if store.addArg.UserID != testSub || store.addArg.WorkID != "OL12345W" {
+		t.Errorf("unexpected add params: %+v", store.addArg)
+	}
+	if !store.addArg.Spoiler || store.addArg.Chapter.Int32 != 3 {
+		t.Errorf("spoiler/chapter not stored: %+v", store.addArg)
+	}
+}
+
+func TestAddComment_Validation(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+	}{
+		{"missing body", `{"spoiler":true}`},
+		{"too long", `{"body":"` + strings.Repeat("a", maxCommentLength+1) + `"}`},
+		{"zero chapter", `{"body":"hi","chapter":0}`},
+		{"negative page", `{"body":"hi","page":-4}`},
+		{"unknown parent", `{"body":"hi","parent_id":999}`},
+		{"parent on other work", `{"body":"hi","parent_id":3}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := &CommentHandler{Queries: &fakeCommentStore{comments: seedComments()}}
+			w := httptest.NewRecorder()
+			h.AddComment(w, commentRequest(http.MethodPost, "/works/OL12345W/comments", "workID", "OL12345W", tc.body))
+
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Errorf("status: got %d, want %d", w.Code, http.StatusUnprocessableEntity)
+			}
+		})
+	}
+}
+
+func TestAddComment_DeletedParent(t *testing.T) {
+	comments := seedComments()
+	comments[0].DeletedAt = sql.NullTime{Valid: true}
+	store := &fakeCommentStore{comments: comments}
+	h := &CommentHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.AddComment(w, commentRequest(http.MethodPost, "/works/OL12345W/comments", "workID", "OL12345W",
+		`{"body":"Replying to nothing","parent_id":1}`))
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+	if fields := responseFields(t, w); fields["parent_id"] == "" {
+		t.Errorf("expected error for parent_id, got %v", fields)
+	}
+	if store.addArg.Body != "" {
+		t.Errorf("reply was stored: %+v", store.addArg)
+	}
+}
+
+func TestAddComment_InvalidJSON(t *testing.T) {
+	h := &CommentHandler{Queries: &fakeCommentStore{}}
+
+	w := httptest.NewRecorder()
+	h.AddComment(w, commentRequest(http.MethodPost, "/works/OL12345W/comments", "workID", "OL12345W", "not json"))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+// --- PatchComment ---
+
+func TestPatchComment_AuthorCanEdit(t *testing.T) {
+	store := &fakeCommentStore{comments: seedComments()}
+	h := &CommentHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.PatchComment(w, commentRequest(http.MethodPatch, "/comments/1", "id", "1", `{"body":"Edited"}`))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	if store.updateArg.Body != "Edited" || store.updateArg.UserID != testSub {
+		t.Errorf("unexpected update params: %+v", store.updateArg)
+	}
+}
+
+func TestPatchComment_Position(t *testing.T) {
+	cases := []struct {
+		name        string
+		body        string
+		wantChapter sql.NullInt32
+		wantPage    sql.NullInt32
+	}{
+		{"absent fields unchanged", `{"body":"Edited"}`, sql.NullInt32{Int32: 3, Valid: true}, sql.NullInt32{Int32: 40, Valid: true}},
+		{"set", `{"chapter":5,"page":80}`, sql.NullInt32{Int32: 5, Valid: true}, sql.NullInt32{Int32: 80, Valid: true}},
+		{"cleared", `{"chapter":null,"page":null}`, sql.NullInt32{}, sql.NullInt32{}},
+		{"one cleared", `{"page":null}`, sql.NullInt32{Int32: 3, Valid: true}, sql.NullInt32{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			comments := seedComments()
+			comments[0].Chapter = sql.NullInt32{Int32: 3, Valid: true}
+			comments[0].Page = sql.NullInt32{Int32: 40, Valid: true}
+			store := &fakeCommentStore{comments: comments}
+			h := &CommentHandler{Queries: store}
+
+			w := httptest.NewRecorder()
+			h.PatchComment(w, commentRequest(http.MethodPatch, "/comments/1", "id", "1", tc.body))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+			}
+			if store.updateArg.Chapter != tc.wantChapter || store.updateArg.Page != tc.wantPage {
+				t.Errorf("position: got chapter %v page %v, want %v %v",
+					store.updateArg.Chapter, store.updateArg.Page, tc.wantChapter, tc.wantPage)
+			}
+		})
+	}
+
+	h := &CommentHandler{Queries: &fakeCommentStore{comments: seedComments()}}
+	for _, body := range []string{`{"chapter":0}`, `{"page":"ten"}`} {
+		w := httptest.NewRecorder()
+		h.PatchComment(w, commentRequest(http.MethodPatch, "/comments/1", "id", "1", body))
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: status got %d, want %d", body, w.Code, http.StatusUnprocessableEntity)
+		}
+	}
+}
+
+func TestPatchComment_MediaType(t *testing.T) {
+	for ct, want := range map[string]int{
+		"application/merge-patch+json": http.StatusOK,
+		"application/json":             http.StatusOK,
+		"text/plain":                   http.StatusUnsupportedMediaType,
+	} {
+		h := &CommentHandler{Queries: &fakeCommentStore{comments: seedComments()}}
+		r := commentRequest(http.MethodPatch, "/comments/1", "id", "1", `{"body":"Edited"}`)
+		r.Header.Set("Content-Type", ct)
+		w := httptest.NewRecorder()
+		h.PatchComment(w, r)
+
+		if w.Code != want {
+			t.Errorf("%s: status: got %d, want %d", ct, w.Code, want)
+		}
+	}
+}
+
+func TestPatchComment_NonAuthorForbidden(t *testing.T) {
+	h := &CommentHandler{Queries: &fakeCommentStore{comments: seedComments()}}
+
+	w := httptest.NewRecorder()
+	h.PatchComment(w, commentRequest(http.MethodPatch, "/comments/2", "id", "2", `{"body":"hijacked"}`))
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestPatchComment_NotFound(t *testing.T) {
+	h := &CommentHandler{Queries: &fakeCommentStore{}}
+
+	w := httptest.NewRecorder()
+	h.PatchComment(w, commentRequest(http.MethodPatch, "/comments/99", "id", "99", `{"body":"x"}`))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestPatchComment_EmptyBody(t *testing.T) {
+	h := &CommentHandler{Queries: &fakeCommentStore{comments: seedComments()}}
+
+	w := httptest.NewRecorder()
+	h.PatchComment(w, commentRequest(http.MethodPatch, "/comments/1", "id", "1", `{"body":""}`))
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+// --- DeleteComment ---
+
+func TestDeleteComment_AuthorCanDelete(t *testing.T) {
+	store := &fakeCommentStore{comments: seedComments()}
+	h := &CommentHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.DeleteComment(w, commentRequest(http.MethodDelete, "/comments/1", "id", "1", ""))
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if len(store.deletedIDs) != 1 || store.deletedIDs[0] != 1 {
+		t.Errorf("deleted ids: got %v, want [1]", store.deletedIDs)
+	}
+}
+
+func TestDeleteComment_NonAuthorForbidden(t *testing.T) {
+	store := &fakeCommentStore{comments: seedComments()}
+	h := &CommentHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.DeleteComment(w, commentRequest(http.MethodDelete, "/comments/2", "id", "2", ""))
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if len(store.deletedIDs) != 0 {
+		t.Error("comment should not have been deleted")
+	}
+}
+
+func TestDeleteComment_AlreadyDeleted(t *testing.T) {
+	comments := []database.Comment{{ID: 1, WorkID: "OL12345W", UserID: testSub, DeletedAt: sql.NullTime{Valid: true}}}
+	h := &CommentHandler{Queries: &fakeCommentStore{comments: comments}}
+
+	w := httptest.NewRecorder()
+	h.DeleteComment(w, commentRequest(http.MethodDelete, "/comments/1", "id", "1", ""))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/openapi"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/go-chi/chi/v5"
+)
+
+// The contract tests send requests through the routes main.go serves, with the
+// OpenAPI validator enforcing the spec. A handler whose response drifts from the
+// spec fails here.
+
+type contractCase struct {
+	name    string
+	method  string
+	target  string
+	header  map[string]string
+	body    string
+	want    int
+	timeout time.Duration // for streams
+	// anonymous leaves out the Authorization header every other case sends.
+	anonymous bool
+}
+
+var contractCases = []contractCase{
+	{name: "health", method: "GET", target: "/health", want: 200},
+	{name: "search", method: "GET", target: "/v1/search?q=dune&lang=en", want: 200},
+	{name: "search without q", method: "GET", target: "/v1/search", want: 400},
+	{name: "search anonymous", method: "GET", target: "/v1/search?q=dune", anonymous: true, want: 200},
+	{name: "details", method: "GET", target: "/v1/details?id=OL1W", want: 200},
+	{name: "list", method: "GET", target: "/v1/readlist?sort=title&owned=true", want: 200},
+	{name: "list bad filter", method: "GET", target: "/v1/readlist?format=scroll", want: 400},
+	{name: "list bad filter anonymous", method: "GET", target: "/v1/readlist?format=scroll", anonymous: true, want: 401},
+	{name: "add", method: "POST", target: "/v1/readlist", body: `{"title":"Emma","authors":"Jane Austen","work_id":"OL2W"}`, want: 201},
+	{name: "add invalid", method: "POST", target: "/v1/readlist", body: `{"title":"Emma","colour":"red"}`, want: 422},
+	{name: "bulk", method: "POST", target: "/v1/readlist/bulk", body: `{"mode":"best_effort","operations":[{"op":"update","ids":[1,9],"patch":{"status":"reading"}}]}`, want: 200},
+	{name: "bulk atomic rollback", method: "POST", target: "/v1/readlist/bulk", body: `{"operations":[{"op":"delete","ids":[1,9]}]}`, want: 409},
+	{name: "events", method: "GET", target: "/v1/readlist/events", timeout: 50 * time.Millisecond, want: 200},
+	{name: "get", method: "GET", target: "/v1/readlist/OL1W", want: 200},
+	{name: "get not modified", method: "GET", target: "/v1/readlist/OL1W", header: map[string]string{"If-None-Match": `"3"`}, want: 304},
+	{name: "get missing", method: "GET", target: "/v1/readlist/OL9W", want: 404},
+	{name: "merge patch", method: "PATCH", target: "/v1/readlist/1", header: map[string]string{"Content-Type": "application/merge-patch+json"}, body: `{"status":"reading","notes":null}`, want: 200},
+	{name: "json patch", method: "PATCH", target: "/v1/readlist/1", header: map[string]string{"Content-Type": "application/json-patch+json"}, body: `[{"op":"replace","path":"/rating","value":4}]`, want: 200},
+	{name: "patch stale", method: "PATCH", target: "/v1/readlist/1", header: map[string]string{"If-Match": `"2"`}, body: `{"status":"reading"}`, want: 412},
+	{name: "patch media type", method: "PATCH", target: "/v1/readlist/1", header: map[string]string{"Content-Type": "text/plain"}, body: `status=reading`, want: 415},
+	{name: "delete", method: "DELETE", target: "/v1/readlist/1", want: 204},
+	{name: "delete missing", method: "DELETE", target: "/v1/readlist/9", want: 404},
+	{name: "lend", method: "POST", target: "/v1/readlist/7/loans", body: `{"borrower_name":"Sam","due_on":"2026-12-01"}`, want: 201},
+	{name: "formats", method: "GET", target: "/v1/readlist/1/formats", want: 200},
+	{name: "put format", method: "PUT", target: "/v1/readlist/1/formats/hardcover", body: `{"purchased_on":"2026-01-02","price_cents":1999,"currency":"USD","source":"purchase"}`, want: 200},
+	{name: "put format empty", method: "PUT", target: "/v1/readlist/1/formats/audiobook", want: 200},
+	{name: "delete format", method: "DELETE", target: "/v1/readlist/1/formats/ebook", want: 204},
+	{name: "delete format not owned", method: "DELETE", target: "/v1/readlist/1/formats/hardcover", want: 404},
+}
+
+// contractRouter serves the API's routes, as registered by Routes, against fake
+// stores seeded with readlist entry 1 (OL1W, version 3, owned as an ebook) and
+// loanable entry 7. The bearer token "jwt" authenticates as testSub. Handlers for
+// routes the spec does not describe are left nil and never reached.
+func contractRouter(t *testing.T, v *openapi.Validator) http.Handler {
+	t.Helper()
+	openLibrary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search.json":
+			w.Write([]byte(`{"docs":[{"title":"Dune","author_name":["Frank Herbert"],"first_publish_year":1965,"key":"/works/OL1W"},{"title":"Anonymous","key":"/works/OL3W"}]}`))
+		default:
+			w.Write([]byte(`{"title":"Dune","description":{"value":"Spice."},"subjects":["Science fiction"],"covers":[42]}`))
+		}
+	}))
+	t.Cleanup(openLibrary.Close)
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	store := &fakeStore{
+		addedID: 2,
+		books: []database.Book{{
+			ID: 1, UserID: testSub, Title: "Dune", Authors: "Frank Herbert", WorkID: "OL1W",
+			Status: "want_to_read", Version: 3, UpdatedAt: now,
+			Notes: sql.NullString{String: "Reread", Valid: true},
+		}},
+		updatedBook: database.Book{ID: 1, UserID: testSub, Status: "reading", Version: 4},
+		owned: []database.OwnedFormat{
+			{BookID: 1, UserID: testSub, Format: "ebook", Source: sql.NullString{String: "gift", Valid: true}, UpdatedAt: now},
+		},
+	}
+	health := &HealthHandler{Ping: func(context.Context) error { return nil }, AuthReady: func() bool { return true }}
+	books := &BookHandler{baseURL: openLibrary.URL}
+	readlist := newHandler(store)
+	stream := &EventStreamHandler{Queries: &fakeEventLog{}, Notifier: &fakeNotifier{wake: make(chan struct{})}}
+	loans := &LoanHandler{Queries: &fakeLoanStore{}}
+
+	tokens := tokenPrincipals{"jwt": {Subject: testSub}}
+	validate := v.Middleware(func(r *http.Request, err error) {
+		t.Errorf("%s %s: response does not match the spec: %v", r.Method, r.URL, err)
+	})
+	routes := &Routes{
+		Books:    books,
+		Readlist: readlist,
+		Events:   stream,
+		Loans:    loans,
+		Middleware: RouteMiddleware{
+			OptionalAuth: appauth.OptionalAuthMiddleware(tokens),
+			TokenAuth:    appauth.AuthMiddleware(tokens),
+			Auth:         appauth.AuthMiddleware(tokens),
+			Validate:     validate,
+		},
+	}
+
+	r := chi.NewRouter()
+	r.With(validate).Get("/health", health.Health)
+	r.Route("/v1", func(r chi.Router) {
+		r.Use(WithMapper(V1))
+		routes.API(r)
+	})
+	return r
+}
+
+func TestContract(t *testing.T) {
+	v, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("load spec: %v", err)
+	}
+
+	exercised := map[string]bool{}
+	for _, tc := range contractCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			if !tc.anonymous {
+				r.Header.Set("Authorization", "Bearer jwt")
+			}
+			for k, val := range tc.header {
+				r.Header.Set(k, val)
+			}
+			if tc.timeout > 0 {
+				ctx, cancel := context.WithTimeout(r.Context(), tc.timeout)
+				defer cancel()
+				r = r.WithContext(ctx)
+			}
+			exercised[v.Operation(r)] = true
+
+			w := httptest.NewRecorder()
+			contractRouter(t, v).ServeHTTP(w, r)
+			if w.Code != tc.want {
+				t.Errorf("status: got %d, want %d: %s", w.Code, tc.want, w.Body)
+			}
+		})
+	}
+
+	for _, id := range specOperations(t) {
+		if !exercised[id] {
+			t.Errorf("operation %s has no contract case", id)
+		}
+	}
+}
+
+// specOperations lists the operationId of every operation in the spec.
+func specOperations(t *testing.T) []string {
+	t.Helper()
+	var doc struct {
+		Paths map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(openapi.Spec(), &doc); err != nil {
+		t.Fatalf("parse spec: %v", err)
+	}
+	var ids []string
+	for _, item := range doc.Paths {
+		for _, op := range item {
+			if op, ok := op.(map[string]any); ok && op["operationId"] != nil {
+				ids = append(ids, op["operationId"].(string))
+			}
+		}
+	}
+	if len(ids) == 0 {
+		t.Fatal("spec has no operations")
+	}
+	return ids
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dcrespo1/book-list-app/problem"
+)
+
+// MaxBodyBytes caps every JSON request body, and the messages of the RPC services.
+const MaxBodyBytes = 1 << 20
+
+// maxNotesLength caps the notes on a readlist entry.
+const maxNotesLength = 10000
+
+// decodeJSON decodes a request body holding exactly one JSON object into dst, a
+// pointer to a struct. It writes the error response and returns false when the body
+// is not JSON (415), too large (413) or malformed (400), or when fields are unknown,
+// have the wrong type or are longer than their maxlen tag allows (422). A missing
+// Content-Type is taken to be application/json.
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	return decodeBody(w, r, dst, false)
+}
+
+// decodeOptionalJSON is decodeJSON for endpoints whose body may be left empty, in
+// which case dst is unchanged.
+func decodeOptionalJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	return decodeBody(w, r, dst, true)
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, dst any, optional bool) bool {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mt, _, err := mime.ParseMediaType(ct)
+		if err != nil || mt != "application/json" {
+			problem.Write(w, r, problem.UnsupportedMediaType, "content type must be application/json")
+			return false
+		}
+	}
+
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxBodyBytes))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(dst)
+	if errors.Is(err, io.EOF) && optional {
+		return true
+	}
+	if err == nil && dec.Decode(&struct{}{}) != io.EOF {
+		problem.Write(w, r, problem.InvalidBody, "request body must contain a single JSON object")
+		return false
+	}
+	if err != nil {
+		writeDecodeError(w, r, err)
+		return false
+	}
+
+	errs := fieldErrors{}
+	checkLengths(reflect.ValueOf(dst).Elem(), "", errs)
+	if len(errs) > 0 {
+		problem.WriteInvalid(w, r, errs)
+		return false
+	}
+	return true
+}
+
+// readMergePatch reads a JSON merge patch body with readBody. The Content-Type must
+// be application/merge-patch+json or application/json (415 otherwise); a missing
+// Content-Type is taken to be application/json.
+func readMergePatch(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mt, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mt != mediaTypeMergePatch && mt != "application/json") {
+			w.Header().Set("Accept-Patch", mediaTypeMergePatch)
+			problem.Write(w, r, problem.UnsupportedMediaType, "content type must be "+mediaTypeMergePatch+" or application/json")
+			return nil, false
+		}
+	}
+	return readBody(w, r)
+}
+
+// readBody reads a raw request body, such as a patch the handler parses itself, up
+// to MaxBodyBytes. It does not look at the Content-Type: callers check it, as
+// readMergePatch and PatchReadlist do.
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxBodyBytes))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeTooLarge(w, r)
+		return nil, false
+	}
+	if err != nil {
+		problem.Write(w, r, problem.InvalidBody, "invalid request body")
+		return nil, false
+	}
+	return body, true
+}
+
+func writeDecodeError(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		tooLarge  *http.MaxBytesError
+		typeErr   *json.UnmarshalTypeError
+		syntaxErr *json.SyntaxError
+	)
+	switch {
+	case errors.As(err, &tooLarge):
+		writeTooLarge(w, r)
+	case errors.Is(err, io.EOF):
+		problem.Write(w, r, problem.InvalidBody, "request body is required")
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		problem.Write(w, r, problem.InvalidBody, "invalid request body: malformed JSON")
+	case errors.As(err, &typeErr) && typeErr.Field == "":
+		problem.Write(w, r, problem.InvalidBody, "request body must be a JSON object")
+	case errors.As(err, &typeErr):
+		problem.WriteInvalid(w, r, fieldErrors{typeErr.Field: "must be " + jsonKind(typeErr.Type)})
+	default:
+		// encoding/json reports unknown fields only as text.
+		if name, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			name, _ = strconv.Unquote(name)
+			problem.WriteInvalid(w, r, fieldErrors{name: "is not a known field"})
+			return
+		}
+		problem.Write(w, r, problem.InvalidBody, "invalid request body")
+	}
+}
+
+func writeTooLarge(w http.ResponseWriter, r *http.Request) {
+	problem.Write(w, r, problem.BodyTooLarge, "request body must be at most "+strconv.Itoa(MaxBodyBytes)+" bytes")
+}
+
+// jsonKind describes the JSON value a Go type is decoded from.
+func jsonKind(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+// checkLengths records every string field, directly or in nested structs and
+// slices, that is longer than its maxlen tag. Keys are JSON field names joined
+// with "/", as in "operations/0/notes".
+func checkLengths(v reflect.Value, prefix string, errs fieldErrors) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			checkLengths(v.Elem(), prefix, errs)
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return // []byte and json.RawMessage
+		}
+		for i := range v.Len() {
+			checkLengths(v.Index(i), prefix+strconv.Itoa(i)+"/", errs)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if !f.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			fv := v.Field(i)
+			limit, err := strconv.Atoi(f.Tag.Get("maxlen"))
+			if err != nil {
+				checkLengths(fv, prefix+name+"/", errs)
+				continue
+			}
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.String && utf8.RuneCountInString(fv.String()) > limit {
+				errs[prefix+name] = "must be at most " + strconv.Itoa(limit) + " characters"
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dcrespo1/book-list-app/problem"
+)
+
+type decodeTarget struct {
+	Title string `json:"title" maxlen:"5"`
+	Count int    `json:"count"`
+	Items []struct {
+		Note *string `json:"note" maxlen:"3"`
+	} `json:"items"`
+}
+
+func decodeRequest(contentType, body string, optional bool) (*httptest.ResponseRecorder, bool) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	w := httptest.NewRecorder()
+	var dst decodeTarget
+	if optional {
+		return w, decodeOptionalJSON(w, r, &dst)
+	}
+	return w, decodeJSON(w, r, &dst)
+}
+
+// responseFields returns the field errors of a validation problem, keyed by
+// field path without the leading "#/".
+func responseFields(t *testing.T, w *httptest.ResponseRecorder) map[string]string {
+	t.Helper()
+	var got problem.Problem
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	fields := map[string]string{}
+	for _, e := range got.Errors {
+		fields[strings.TrimPrefix(e.Pointer, "#/")] = e.Detail
+	}
+	return fields
+}
+
+func TestDecodeJSON_Accepts(t *testing.T) {
+	for _, ct := range []string{"", "application/json", "application/json; charset=utf-8"} {
+		if w, ok := decodeRequest(ct, `{"title":"Dune","count":1}`, false); !ok {
+			t.Errorf("%q: status: got %d, want success", ct, w.Code)
+		}
+	}
+}
+
+func TestDecodeJSON_Rejects(t *testing.T) {
+	cases := []struct {
+		name, contentType, body string
+		want                    int
+	}{
+		{"wrong content type", "text/plain", `{"title":"Dune"}`, http.StatusUnsupportedMediaType},
+		{"empty body", "", "", http.StatusBadRequest},
+		{"malformed", "", `{"title":`, http.StatusBadRequest},
+		{"not an object", "", `["Dune"]`, http.StatusBadRequest},
+		{"trailing object", "", `{"title":"Dune"}{"title":"Emma"}`, http.StatusBadRequest},
+		{"trailing garbage", "", `{"title":"Dune"} x`, http.StatusBadRequest},
+		{"too large", "", `{"title":"` + strings.Repeat("a", MaxBodyBytes) + `"}`, http.StatusRequestEntityTooLarge},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, ok := decodeRequest(tc.contentType, tc.body, false)
+			if ok || w.Code != tc.want {
+				t.Errorf("status: got %d, want %d", w.Code, tc.want)
+			}
+		})
+	}
+}
+
+func TestDecodeJSON_FieldErrors(t *testing.T) {
+	cases := []struct {
+		name, body string
+		want       []string
+	}{
+		{"unknown field", `{"title":"Dune","colour":"red"}`, []string{"colour"}},
+		{"wrong type", `{"count":"one"}`, []string{"count"}},
+		{"too long", `{"title":"Dune Messiah","items":[{"note":"ok"},{"note":"long"}]}`, []string{"title", "items/1/note"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, ok := decodeRequest("", tc.body, false)
+			if ok || w.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("status: got %d, want %d", w.Code, http.StatusUnprocessableEntity)
+			}
+			fields := responseFields(t, w)
+			if len(fields) != len(tc.want) {
+				t.Errorf("fields: got %v, want %v", fields, tc.want)
+			}
+			for _, f := range tc.want {
+				if _, ok := fields[f]; !ok {
+					t.Errorf("expected an error for %s, got %v", f, fields)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeOptionalJSON_EmptyBody(t *testing.T) {
+	if w, ok := decodeRequest("", "", true); !ok {
+		t.Errorf("status: got %d, want success", w.Code)
+	}
+	if w, ok := decodeRequest("", `{"colour":"red"}`, true); ok || w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestAddToReadlist_FieldTooLong(t *testing.T) {
+	h := newHandler(&fakeStore{})
+
+	body := `{"title":"` + strings.Repeat("a", 501) + `","authors":"Frank Herbert","work_id":"OL12345W"}`
+	w := httptest.NewRecorder()
+	r := withSub(httptest.NewRequest(http.MethodPost, "/readlist", bytes.NewBufferString(body)), testSub)
+	h.AddToReadlist(w, r)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+	if _, ok := responseFields(t, w)["title"]; !ok {
+		t.Error("expected an error for title")
+	}
+}
+
+func TestPatchReadlist_NotesTooLong(t *testing.T) {
+	h := newHandler(&fakeStore{books: seedBook()})
+
+	w := httptest.NewRecorder()
+	h.PatchReadlist(w, patchRequest("1", `{"notes":"`+strings.Repeat("a", maxNotesLength+1)+`"}`))
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+}
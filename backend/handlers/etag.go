@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+)
+
+// bookETag renders a readlist entry's version as a strong entity tag.
+func bookETag(version int32) string {
+	return `"` + strconv.Itoa(int(version)) + `"`
+}
+
+// parseIfMatch turns an If-Match header into the versions it names. An absent
+// header or "*" yields no versions, meaning any current version is acceptable.
+// ok is false when the header is present but names no version this API issued
+// (weak or foreign tags), in which case the precondition can never hold.
+func parseIfMatch(header string) (versions []int32, ok bool) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) || len(tag) < 2 {
+			continue // weak tags never match under If-Match's strong comparison
+		}
+		v, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 32)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, int32(v))
+	}
+	return versions, len(versions) > 0
+}
+
+// etagMatches implements the weak comparison used by If-None-Match.
+func etagMatches(header, etag string) bool {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
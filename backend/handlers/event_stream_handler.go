@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/problem"
+)
+
+// EventLog is the persistence interface for the readlist event stream. *database.Queries satisfies it.
+type EventLog interface {
+	ListReadlistEventsSince(ctx context.Context, arg database.ListReadlistEventsSinceParams) ([]database.ReadlistEvent, error)
+	GetLatestReadlistEventID(ctx context.Context, userID string) (int64, error)
+}
+
+// EventNotifier wakes a stream when new events may exist for a user. *stream.Broker implements this.
+type EventNotifier interface {
+	Subscribe(userID string) (<-chan struct{}, func())
+}
+
+const (
+	defaultHeartbeat = 25 * time.Second
+	eventBatchSize   = 100
+)
+
+type EventStreamHandler struct {
+	Queries  EventLog
+	Notifier EventNotifier
+	// Heartbeat is the interval between keep-alive comments. Defaults to 25s.
+	Heartbeat time.Duration
+}
+
+// StreamReadlistEvents serves the caller's readlist changes as Server-Sent Events.
+// Each event's id is its position in the log, so a reconnecting client that sends
+// Last-Event-ID receives everything it missed.
+func (h *EventStreamHandler) StreamReadlistEvents(w http.ResponseWriter, r *http.Request) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+
+	ctx := r.Context()
+	rc := http.NewResponseController(w)
+
+	// Subscribe before reading the log so nothing committed in between is missed.
+	wake, unsubscribe := h.Notifier.Subscribe(sub)
+	defer unsubscribe()
+
+	var lastID int64
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id < 0 {
+			problem.Write(w, r, problem.InvalidParameter, "invalid Last-Event-ID")
+			return
+		}
+		lastID = id
+	} else {
+		id, err := h.Queries.GetLatestReadlistEventID(ctx, sub)
+		if err != nil {
+			problem.Write(w, r, problem.Internal, "failed to open event stream")
+			return
+		}
+		lastID = id
+	}
+
+	// The server's WriteTimeout would otherwise cut the stream off.
+	rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 3000\n\n")
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat())
+	defer heartbeat.Stop()
+
+	for {
+		var err error
+		if lastID, err = h.sendSince(ctx, w, sub, lastID); err != nil {
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-wake:
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// sendSince writes every logged event after lastID and returns the new last id.
+func (h *EventStreamHandler) sendSince(ctx context.Context, w http.ResponseWriter, sub string, lastID int64) (int64, error) {
+	for {
+		batch, err := h.Queries.ListReadlistEventsSince(ctx, database.ListReadlistEventsSinceParams{
+			UserID: sub,
+			ID:     lastID,
+			Limit:  eventBatchSize,
+		})
+		if err != nil {
+			return lastID, err
+		}
+		for _, e := range batch {
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, e.Payload); err != nil {
+				return lastID, err
+			}
+			lastID = e.ID
+		}
+		if len(batch) < eventBatchSize {
+			return lastID, nil
+		}
+	}
+}
+
+func (h *EventStreamHandler) heartbeat() time.Duration {
+	if h.Heartbeat > 0 {
+		return h.Heartbeat
+	}
+	return defaultHeartbeat
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dcrespo1/book-list-app/pkg/database"
+)
+
+// fakeEventLog is a test double for EventLog, safe for use by a live stream.
+type fakeEventLog struct {
+	mu     sync.Mutex
+	events []database.ReadlistEvent
+}
+
+func (f *fakeEventLog) append(userID, typ string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := int64(len(f.events) + 1)
+	payload, _ := json.Marshal(map[string]any{"type": typ})
+	f.events = append(f.events, database.ReadlistEvent{ID: id, UserID: userID, Type: typ, Payload: payload})
+}
+
+func (f *fakeEventLog) ListReadlistEventsSince(_ context.Context, arg database.ListReadlistEventsSinceParams) ([]database.ReadlistEvent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []database.ReadlistEvent
+	for _, e := range f.events {
+		if e.UserID == arg.UserID && e.ID > arg.ID && int32(len(out)) < arg.Limit {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeEventLog) GetLatestReadlistEventID(_ context.Context, userID string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var latest int64
+	for _, e := range f.events {
+		if e.UserID == userID {
+			latest = e.ID
+		}
+	}
+	return latest, nil
+}
+
+// fakeNotifier hands every subscriber the same wake channel.
+type fakeNotifier struct {
+	wake chan struct{}
+}
+
+func (f *fakeNotifier) Subscribe(string) (<-chan struct{}, func()) {
+	return f.wake, func() {}
+}
+
+type sseEvent struct {
+	id, event, data string
+}
+
+// startStream opens the stream against a test server and returns a reader of parsed events.
+func startStream(t *testing.T, h *EventStreamHandler, lastEventID string) <-chan sseEvent {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.StreamReadlistEvents(w, withSub(r, testSub))
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type: got %q", ct)
+	}
+
+	out := make(chan sseEvent, 10)
+	go func() {
+		defer close(out)
+		var cur sseEvent
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				if cur.id != "" {
+					out <- cur
+				}
+				cur = sseEvent{}
+			case strings.HasPrefix(line, "id: "):
+				cur.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				cur.event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				cur.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+	return out
+}
+
+func nextEvent(t *testing.T, events <-chan sseEvent) sseEvent {
+	t.Helper()
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return sseEvent{}
+	}
+}
+
+func TestStreamReadlistEvents_PushesNewEvents(t *testing.T) {
+	log := &fakeEventLog{}
+	log.append(testSub, "book.added") // before connecting; must not be replayed
+	notifier := &fakeNotifier{wake: make(chan struct{}, 1)}
+	events := startStream(t, &EventStreamHandler{Queries: log, Notifier: notifier}, "")
+
+	log.append("someone-else", "book.added")
+	log.append(testSub, "book.updated")
+	notifier.wake <- struct{}{}
+
+	e := nextEvent(t, events)
+	if e.id != "3" || e.event != "book.updated" {
+		t.Errorf("got %+v, want id 3 book.updated", e)
+	}
+	if !strings.Contains(e.data, "book.updated") {
+		t.Errorf("unexpected data: %s", e.data)
+	}
+}
+
+func TestStreamReadlistEvents_ResumesFromLastEventID(t *testing.T) {
+	log := &fakeEventLog{}
+	log.append(testSub, "book.added")
+	log.append(testSub, "book.updated")
+	log.append(testSub, "book.deleted")
+	events := startStream(t, &EventStreamHandler{Queries: log, Notifier: &fakeNotifier{}}, "1")
+
+	if e := nextEvent(t, events); e.id != "2" || e.event != "book.updated" {
+		t.Errorf("first replayed: got %+v", e)
+	}
+	if e := nextEvent(t, events); e.id != "3" || e.event != "book.deleted" {
+		t.Errorf("second replayed: got %+v", e)
+	}
+}
+
+func TestStreamReadlistEvents_InvalidLastEventID(t *testing.T) {
+	h := &EventStreamHandler{Queries: &fakeEventLog{}, Notifier: &fakeNotifier{}}
+
+	w := httptest.NewRecorder()
+	r := withSub(httptest.NewRequest(http.MethodGet, "/readlist/events", nil), testSub)
+	r.Header.Set("Last-Event-ID", "abc")
+	h.StreamReadlistEvents(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"net/http"
+	"sync"
+
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/problem"
+	"github.com/graph-gophers/dataloader"
+	"github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var graphQLSchema string
+
+const (
+	// maxQueryDepth bounds how deeply a GraphQL query may nest selections.
+	maxQueryDepth = 8
+	// maxEditionsPerWork is the most editions Work.editions returns, and the page
+	// the editions loader fetches for every work.
+	maxEditionsPerWork = 20
+	// maxConcurrentFetches bounds the Open Library requests one batch makes at once
+	// when an endpoint has to be called once per work.
+	maxConcurrentFetches = 4
+)
+
+// GraphQLHandler serves POST /graphql, a schema over the caller's readlist and the
+// Open Library metadata behind it, so a page can load entries with their works,
+// authors and editions in one round trip. Mutations share their validation and
+// events with the REST handlers.
+//
+// Open Library lookups go through dataloaders created for each request, which
+// collect the works every resolver asks for and fetch them together.
+type GraphQLHandler struct {
+	Readlist *ReadlistHandler
+	Books    *BookHandler
+	// AllowUpstream, when set, is asked before every Open Library call a request
+	// makes. One query can fan out into many calls, so each is charged to the caller
+	// like a request to the REST proxies; fields it refuses fail with rate_limited.
+	AllowUpstream func(r *http.Request) bool
+
+	once   sync.Once
+	schema *graphql.Schema
+}
+
+func (h *GraphQLHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var params struct {
+		Query         string         `json:"query"`
+		OperationName string         `json:"operationName"`
+		Variables     map[string]any `json:"variables"`
+		// Extensions is accepted for clients that always send it, and ignored.
+		Extensions map[string]any `json:"extensions"`
+	}
+	if !decodeJSON(w, r, &params) {
+		return
+	}
+	if params.Query == "" {
+		problem.WriteInvalid(w, r, fieldErrors{"query": "is required"})
+		return
+	}
+
+	h.once.Do(func() {
+		h.schema = graphql.MustParseSchema(graphQLSchema, &graphQLResolver{h},
+			graphql.UseStringDescriptions(),
+			graphql.MaxDepth(maxQueryDepth),
+		)
+	})
+	charge := func() error {
+		if h.AllowUpstream == nil || h.AllowUpstream(r) {
+			return nil
+		}
+		return &graphQLError{typ: problem.RateLimited, detail: "Open Library rate limit exceeded"}
+	}
+	ctx := context.WithValue(r.Context(), loadersKey{}, newLoaders(h.Books, charge))
+	WriteJSON(w, http.StatusOK, h.schema.Exec(ctx, params.Query, params.OperationName, params.Variables))
+}
+
+// graphQLError is a resolver error. Its extensions carry the problem code the REST
+// API answers the same failure with, and the invalid fields, if any.
+type graphQLError struct {
+	typ    problem.Type
+	detail string
+	fields fieldErrors
+}
+
+func (e *graphQLError) Error() string { return e.detail }
+
+func (e *graphQLError) Extensions() map[string]any {
+	ext := map[string]any{"code": e.typ.Code}
+	if len(e.fields) > 0 {
+		ext["fields"] = e.fields
+	}
+	return ext
+}
+
+// entryError converts an error from addEntry, updateEntry or deleteEntry, using
+// detail for unexpected failures as writeEntryError does.
+func entryError(err error, detail string) *graphQLError {
+	typ, msg := entryProblem(err, detail)
+	return &graphQLError{typ: typ, detail: msg}
+}
+
+// readlistCaller returns the caller's subject after checking that a personal access
+// token, if that is how they signed in, carries the scope the field needs: read or
+// write for queries, write for mutations. The HTTP route cannot tell them apart, as
+// every GraphQL request is a POST.
+func readlistCaller(ctx context.Context, write bool) (string, error) {
+	sub, ok := appauth.SubFromContext(ctx)
+	if !ok {
+		return "", &graphQLError{typ: problem.Internal, detail: "missing user context"}
+	}
+	p, ok := appauth.PrincipalFromContext(ctx)
+	if !ok || !p.PersonalToken || p.HasScope(appauth.ScopeReadlistWrite) || (!write && p.HasScope(appauth.ScopeReadlistRead)) {
+		return sub, nil
+	}
+	return "", &graphQLError{typ: problem.InsufficientScope, detail: "insufficient scope"}
+}
+
+type loadersKey struct{}
+
+// loaders batch and cache the Open Library lookups of one GraphQL request, keyed by
+// work ID. works resolves to *Work, or nil for unknown works; descriptions to
+// BookDetails; editions to []Edition. charge is called before every upstream call,
+// the loaders' and any a resolver makes itself, and fails once the caller's budget
+// is spent.
+type loaders struct {
+	works        *dataloader.Loader
+	descriptions *dataloader.Loader
+	editions     *dataloader.Loader
+	charge       func() error
+}
+
+func newLoaders(books *BookHandler, charge func() error) *loaders {
+	return &loaders{
+		charge: charge,
+		works: dataloader.NewBatchedLoader(func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+			var works map[string]Work
+			err := charge()
+			if err == nil {
+				works, err = books.GetWorks(ctx, keys.Keys())
+			}
+			results := make([]*dataloader.Result, len(keys))
+			for i, key := range keys {
+				switch w, ok := works[key.String()]; {
+				case err != nil:
+					results[i] = &dataloader.Result{Error: err}
+				case ok:
+					results[i] = &dataloader.Result{Data: &w}
+				default:
+					results[i] = &dataloader.Result{}
+				}
+			}
+			return results
+		}, dataloader.WithBatchCapacity(maxWorksPerSearch)),
+		descriptions: dataloader.NewBatchedLoader(eachKey(charge, func(_ context.Context, id string) (any, error) {
+			return books.GetBookDetails(id)
+		})),
+		editions: dataloader.NewBatchedLoader(eachKey(charge, func(ctx context.Context, id string) (any, error) {
+			return books.GetEditions(ctx, id, maxEditionsPerWork)
+		})),
+	}
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// eachKey adapts fetch, which loads a single key, to a batch function for upstream
+// endpoints with no batch form. The batch still costs one call per distinct key,
+// each charged separately, but the calls run concurrently and the loader's cache
+// means no key is fetched twice in a request.
+func eachKey(charge func() error, fetch func(context.Context, string) (any, error)) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		results := make([]*dataloader.Result, len(keys))
+		sem := make(chan struct{}, maxConcurrentFetches)
+		var wg sync.WaitGroup
+		for i, key := range keys {
+			wg.Go(func() {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				if err := charge(); err != nil {
+					results[i] = &dataloader.Result{Error: err}
+					return
+				}
+				data, err := fetch(ctx, key.String())
+				results[i] = &dataloader.Result{Data: data, Error: err}
+			})
+		}
+		wg.Wait()
+		return results
+	}
+}
+
+// prefetch queues the lookups the query selects under path for every work in a
+// list, before the list's items resolve. The items resolve only a few at a time,
+// so without it each group of items would end up in a batch of its own.
+func (l *loaders) prefetch(ctx context.Context, path string, workIDs []string) {
+	for _, sel := range []struct {
+		path   string
+		loader *dataloader.Loader
+	}{
+		{path, l.works},
+		{path + ".description", l.descriptions},
+		{path + ".editions", l.editions},
+	} {
+		if !graphql.HasSelectedField(ctx, sel.path) {
+			continue
+		}
+		for _, id := range workIDs {
+			sel.loader.Load(ctx, dataloader.StringKey(id))
+		}
+	}
+}
+
+// load waits for the value loader has for workID.
+func load[T any](ctx context.Context, loader *dataloader.Loader, workID string) (T, error) {
+	var zero T
+	data, err := loader.Load(ctx, dataloader.StringKey(workID))()
+	var gqlErr *graphQLError
+	if errors.As(err, &gqlErr) {
+		return zero, gqlErr
+	}
+	if err != nil {
+		return zero, &graphQLError{typ: problem.Internal, detail: "failed to fetch book details"}
+	}
+	if data == nil {
+		return zero, nil
+	}
+	return data.(T), nil
+}
@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/problem"
+	"github.com/dcrespo1/book-list-app/users"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// graphQLResolver resolves the Query and Mutation fields of schema.graphql. Enum
+// values are the REST API's lower-case strings in upper case.
+type graphQLResolver struct {
+	h *GraphQLHandler
+}
+
+func (q *graphQLResolver) Readlist(ctx context.Context, args struct {
+	Status *string
+	Sort   *string
+}) ([]*entryResolver, error) {
+	sub, err := readlistCaller(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	sortBy := ""
+	if args.Sort != nil {
+		sortBy = strings.ToLower(*args.Sort)
+	}
+	var filter readlistFilter
+	if args.Status != nil {
+		filter.status = strings.ToLower(*args.Status)
+	}
+	listed, err := q.h.Readlist.listEntries(ctx, sub, sortBy, filter)
+	if err != nil {
+		typ, detail := listProblem(err)
+		return nil, &graphQLError{typ: typ, detail: detail}
+	}
+
+	entries := make([]*entryResolver, len(listed))
+	workIDs := make([]string, len(listed))
+	for i, e := range listed {
+		entries[i] = &entryResolver{toBookResponse(e.book)}
+		workIDs[i] = e.book.WorkID
+	}
+	loadersFrom(ctx).prefetch(ctx, "work", workIDs)
+	return entries, nil
+}
+
+func (q *graphQLResolver) Entry(ctx context.Context, args struct{ WorkID graphql.ID }) (*entryResolver, error) {
+	sub, err := readlistCaller(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	book, err := q.h.Readlist.Queries.GetBookByWorkID(ctx, database.GetBookByWorkIDParams{
+		WorkID: string(args.WorkID),
+		UserID: sub,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, &graphQLError{typ: problem.Internal, detail: "failed to retrieve book"}
+	}
+	return &entryResolver{toBookResponse(book)}, nil
+}
+
+func (q *graphQLResolver) Search(ctx context.Context, args struct {
+	Query string
+	Lang  *string
+}) ([]*searchResultResolver, error) {
+	if args.Query == "" {
+		return nil, &graphQLError{typ: problem.InvalidParameter, detail: "query must not be empty"}
+	}
+	lang := ""
+	if args.Lang != nil {
+		lang = *args.Lang
+	} else {
+		prefs, _ := users.FromContext(ctx)
+		lang = prefs.Language.String
+	}
+	if lang != "" && !languageCode.MatchString(lang) {
+		return nil, &graphQLError{typ: problem.InvalidParameter, detail: "lang must be a two-letter ISO 639-1 code"}
+	}
+
+	if err := loadersFrom(ctx).charge(); err != nil {
+		return nil, err
+	}
+	books, err := q.h.Books.SearchBooks(args.Query, lang)
+	if err != nil {
+		return nil, &graphQLError{typ: problem.Internal, detail: "failed to search books"}
+	}
+	results := make([]*searchResultResolver, len(books))
+	workIDs := make([]string, len(books))
+	for i, b := range books {
+		results[i] = &searchResultResolver{b}
+		workIDs[i] = b.WorkID
+	}
+	loadersFrom(ctx).prefetch(ctx, "work", workIDs)
+	return results, nil
+}
+
+func (q *graphQLResolver) Work(ctx context.Context, args struct{ ID graphql.ID }) (*workResolver, error) {
+	return loadWork(ctx, string(args.ID))
+}
+
+func (q *graphQLResolver) AddToReadlist(ctx context.Context, args struct{ Input addToReadlistInput }) (*entryResolver, error) {
+	sub, err := readlistCaller(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	in := newEntry{
+		Title:       args.Input.Title,
+		Authors:     args.Input.Authors,
+		Subjects:    args.Input.Subjects,
+		Description: args.Input.Description,
+		CoverArtURL: args.Input.CoverArtURL,
+		WorkID:      string(args.Input.WorkID),
+	}
+	errs := in.validate()
+	checkLengths(reflect.ValueOf(in), "", errs)
+	if len(errs) > 0 {
+		return nil, invalidInput(errs, map[string]string{"work_id": "workId", "cover_art_url": "coverArtUrl"})
+	}
+
+	book, err := q.h.Readlist.addEntry(ctx, sub, in)
+	if err != nil {
+		return nil, entryError(err, "failed to add book")
+	}
+	return &entryResolver{toBookResponse(book)}, nil
+}
+
+func (q *graphQLResolver) UpdateReadlistEntry(ctx context.Context, args struct {
+	ID      int32
+	Input   updateReadlistEntryInput
+	IfMatch *int32
+}) (*entryResolver, error) {
+	sub, err := readlistCaller(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var patch readlistPatch
+	errs := fieldErrors{}
+	if s := args.Input.Status; s != nil {
+		v := strings.ToLower(*s)
+		patch.Status = optional[string]{Set: true, Value: &v}
+	}
+	if r := args.Input.Rating; r.Set {
+		if r.Value != nil && (*r.Value < 1 || *r.Value > 5) {
+			errs["rating"] = "must be between 1 and 5"
+		}
+		patch.Rating = optional[int32]{Set: true, Value: r.Value}
+	}
+	if n := args.Input.Notes; n.Set {
+		if n.Value != nil && utf8.RuneCountInString(*n.Value) > maxNotesLength {
+			errs["notes"] = "must be at most " + strconv.Itoa(maxNotesLength) + " characters"
+		}
+		patch.Notes = optional[string]{Set: true, Value: n.Value}
+	}
+	if len(errs) > 0 {
+		return nil, invalidInput(errs, nil)
+	}
+
+	book, err := q.h.Readlist.updateEntry(ctx, sub, args.ID, patch, versions(args.IfMatch))
+	if err != nil {
+		return nil, entryError(err, "failed to update book")
+	}
+	return &entryResolver{toBookResponse(book)}, nil
+}
+
+func (q *graphQLResolver) DeleteReadlistEntry(ctx context.Context, args struct {
+	ID      int32
+	IfMatch *int32
+}) (int32, error) {
+	sub, err := readlistCaller(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+	if err := q.h.Readlist.deleteEntry(ctx, sub, args.ID, versions(args.IfMatch)); err != nil {
+		return 0, entryError(err, "failed to delete book")
+	}
+	return args.ID, nil
+}
+
+type addToReadlistInput struct {
+	WorkID      graphql.ID
+	Title       string
+	Authors     string
+	Subjects    *string
+	Description *string
+	CoverArtURL *string
+}
+
+type updateReadlistEntryInput struct {
+	Status *string
+	Rating graphql.NullInt
+	Notes  graphql.NullString
+}
+
+// invalidInput reports field errors under the schema's argument names, renaming
+// the REST field names listed in names.
+func invalidInput(errs fieldErrors, names map[string]string) *graphQLError {
+	fields := fieldErrors{}
+	for field, msg := range errs {
+		fields["input."+cmp.Or(names[field], field)] = msg
+	}
+	return &graphQLError{typ: problem.ValidationFailed, detail: "invalid input", fields: fields}
+}
+
+// versions turns the optional ifMatch argument into the versions updateEntry and
+// deleteEntry accept. Without it the result is nil, which UpdateBook and
+// DeleteBookByID read as "any version", like an absent If-Match header.
+func versions(ifMatch *int32) []int32 {
+	if ifMatch == nil {
+		return nil
+	}
+	return []int32{*ifMatch}
+}
+
+type entryResolver struct {
+	b BookResponse
+}
+
+func (e *entryResolver) ID() int32            { return e.b.ID }
+func (e *entryResolver) WorkID() graphql.ID   { return graphql.ID(e.b.WorkID) }
+func (e *entryResolver) Title() string        { return e.b.Title }
+func (e *entryResolver) Authors() string      { return e.b.Authors }
+func (e *entryResolver) Subjects() *string    { return e.b.Subjects }
+func (e *entryResolver) Description() *string { return e.b.Description }
+func (e *entryResolver) CoverArtURL() *string { return e.b.CoverArtURL }
+func (e *entryResolver) Status() string       { return strings.ToUpper(e.b.Status) }
+func (e *entryResolver) Rating() *int32       { return e.b.Rating }
+func (e *entryResolver) Notes() *string       { return e.b.Notes }
+func (e *entryResolver) Version() int32       { return e.b.Version }
+func (e *entryResolver) UpdatedAt() string    { return e.b.UpdatedAt.Format(time.RFC3339) }
+
+func (e *entryResolver) Work(ctx context.Context) (*workResolver, error) {
+	return loadWork(ctx, e.b.WorkID)
+}
+
+type searchResultResolver struct {
+	b Book
+}
+
+func (s *searchResultResolver) WorkID() graphql.ID { return graphql.ID(s.b.WorkID) }
+func (s *searchResultResolver) Title() string      { return s.b.Title }
+func (s *searchResultResolver) Authors() []string  { return s.b.Authors }
+
+func (s *searchResultResolver) FirstPublishYear() *int32 {
+	return nonZero(int32(s.b.PublishYear))
+}
+
+func (s *searchResultResolver) Work(ctx context.Context) (*workResolver, error) {
+	return loadWork(ctx, s.b.WorkID)
+}
+
+// loadWork resolves a work through the request's works loader, or to nil if Open
+// Library does not know it.
+func loadWork(ctx context.Context, id string) (*workResolver, error) {
+	w, err := load[*Work](ctx, loadersFrom(ctx).works, id)
+	if err != nil || w == nil {
+		return nil, err
+	}
+	return &workResolver{*w}, nil
+}
+
+type workResolver struct {
+	w Work
+}
+
+func (w *workResolver) ID() graphql.ID           { return graphql.ID(w.w.ID) }
+func (w *workResolver) Title() string            { return w.w.Title }
+func (w *workResolver) FirstPublishYear() *int32 { return nonZero(int32(w.w.FirstPublishYear)) }
+func (w *workResolver) Subjects() []string       { return w.w.Subjects }
+func (w *workResolver) EditionCount() int32      { return int32(w.w.EditionCount) }
+
+func (w *workResolver) Authors() []*authorResolver {
+	authors := make([]*authorResolver, len(w.w.Authors))
+	for i, a := range w.w.Authors {
+		authors[i] = &authorResolver{a}
+	}
+	return authors
+}
+
+func (w *workResolver) CoverURL() *string {
+	if w.w.CoverID == 0 {
+		return nil
+	}
+	u := fmt.Sprintf("https://covers.openlibrary.org/b/id/%d-L.jpg", w.w.CoverID)
+	return &u
+}
+
+func (w *workResolver) Description(ctx context.Context) (*string, error) {
+	details, err := load[BookDetails](ctx, loadersFrom(ctx).descriptions, w.w.ID)
+	if err != nil || details.Description == "" {
+		return nil, err
+	}
+	return &details.Description, nil
+}
+
+func (w *workResolver) Editions(ctx context.Context, args struct{ First int32 }) ([]*editionResolver, error) {
+	if args.First < 0 || args.First > maxEditionsPerWork {
+		return nil, &graphQLError{typ: problem.InvalidParameter, detail: "first must be between 0 and " + strconv.Itoa(maxEditionsPerWork)}
+	}
+	editions, err := load[[]Edition](ctx, loadersFrom(ctx).editions, w.w.ID)
+	if err != nil {
+		return nil, err
+	}
+	out := []*editionResolver{}
+	for _, e := range editions[:min(len(editions), int(args.First))] {
+		out = append(out, &editionResolver{e})
+	}
+	return out, nil
+}
+
+type authorResolver struct {
+	a Author
+}
+
+func (a *authorResolver) ID() graphql.ID { return graphql.ID(a.a.ID) }
+func (a *authorResolver) Name() string   { return a.a.Name }
+
+type editionResolver struct {
+	e Edition
+}
+
+func (e *editionResolver) ID() graphql.ID       { return graphql.ID(e.e.ID) }
+func (e *editionResolver) Title() string        { return e.e.Title }
+func (e *editionResolver) Publishers() []string { return e.e.Publishers }
+func (e *editionResolver) ISBN13() []string     { return e.e.ISBN13 }
+func (e *editionResolver) Languages() []string  { return e.e.Languages }
+func (e *editionResolver) Pages() *int32        { return nonZero(int32(e.e.Pages)) }
+
+func (e *editionResolver) PublishDate() *string {
+	if e.e.PublishDate == "" {
+		return nil
+	}
+	return &e.e.PublishDate
+}
+
+// nonZero maps Open Library's missing numbers, decoded as zero, to null.
+func nonZero(n int32) *int32 {
+	if n == 0 {
+		return nil
+	}
+	return &n
+}
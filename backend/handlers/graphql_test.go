@@ -0,0 +1,378 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+)
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string `json:"message"`
+		Extensions struct {
+			Code   string            `json:"code"`
+			Fields map[string]string `json:"fields"`
+		} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// fakeOpenLibrary serves the Open Library endpoints the GraphQL resolvers use for
+// every work but OL9W, and records every request it receives.
+type fakeOpenLibrary struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+var workKeys = regexp.MustCompile(`/works/(OL[0-9]+W)`)
+
+func (f *fakeOpenLibrary) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests = append(f.requests, r.URL.RequestURI())
+	f.mu.Unlock()
+
+	q := r.URL.Query().Get("q")
+	switch {
+	case r.URL.Path == "/search.json" && strings.HasPrefix(q, "key:"):
+		var docs []map[string]any
+		for _, m := range workKeys.FindAllStringSubmatch(q, -1) {
+			if m[1] == "OL9W" {
+				continue // unknown to Open Library
+			}
+			docs = append(docs, map[string]any{
+				"key": "/works/" + m[1], "title": "Work " + m[1],
+				"author_name": []string{"Author of " + m[1]}, "author_key": []string{"OL1A"},
+				"subject": []string{"Fiction"}, "cover_i": 42, "edition_count": 3,
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"docs": docs})
+	case r.URL.Path == "/search.json":
+		w.Write([]byte(`{"docs":[{"title":"Dune","author_name":["Frank Herbert"],"first_publish_year":1965,"key":"/works/OL1W"},` +
+			`{"title":"Dune Messiah","key":"/works/OL2W"},{"title":"Dune again","key":"/works/OL1W"}]}`))
+	case strings.HasSuffix(r.URL.Path, "/editions.json"):
+		w.Write([]byte(`{"entries":[{"key":"/books/OL1M","title":"Dune","publishers":["Chilton"],"isbn_13":["9780000000001"],` +
+			`"languages":[{"key":"/languages/eng"}],"number_of_pages":412},{"key":"/books/OL2M","title":"Dune"}]}`))
+	default:
+		w.Write([]byte(`{"title":"Dune","description":{"value":"Spice."}}`))
+	}
+}
+
+// count returns how many requests had a path starting with prefix.
+func (f *fakeOpenLibrary) count(prefix string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, req := range f.requests {
+		if strings.HasPrefix(req, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func newGraphQLHandler(t *testing.T, store TxBookStore) (*GraphQLHandler, *fakeOpenLibrary) {
+	t.Helper()
+	ol := &fakeOpenLibrary{}
+	srv := httptest.NewServer(ol)
+	t.Cleanup(srv.Close)
+	return &GraphQLHandler{Readlist: newHandler(store), Books: &BookHandler{baseURL: srv.URL}}, ol
+}
+
+// execGraphQL posts query to h as testSub, authenticated with p when it is not nil.
+func execGraphQL(t *testing.T, h *GraphQLHandler, p *appauth.Principal, query string, variables map[string]any) graphQLResponse {
+	t.Helper()
+	body, _ := json.Marshal(map[string]any{"query": query, "variables": variables})
+	r := withSub(httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))), testSub)
+	if p != nil {
+		r = r.WithContext(appauth.PrincipalToContext(r.Context(), p))
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want 200: %s", w.Code, w.Body)
+	}
+	var resp graphQLResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return resp
+}
+
+func threeBooks() []database.Book {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return []database.Book{
+		{ID: 1, UserID: testSub, Title: "Dune", Authors: "Frank Herbert", WorkID: "OL1W", Status: "reading", Version: 3, UpdatedAt: now},
+		{ID: 2, UserID: testSub, Title: "Dune Messiah", Authors: "Frank Herbert", WorkID: "OL2W", Status: "want_to_read", Version: 1, UpdatedAt: now},
+		{ID: 3, UserID: testSub, Title: "Lost", Authors: "Nobody", WorkID: "OL9W", Status: "want_to_read", Version: 1, UpdatedAt: now},
+	}
+}
+
+func TestGraphQL_ReadlistBatchesWorks(t *testing.T) {
+	h, ol := newGraphQLHandler(t, &fakeStore{books: threeBooks()})
+
+	resp := execGraphQL(t, h, nil, `{ readlist(sort: TITLE) { id status work { title coverUrl authors { id name } } } }`, nil)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("errors: %+v", resp.Errors)
+	}
+	want := `{"readlist":[` +
+		`{"id":1,"status":"READING","work":{"title":"Work OL1W","coverUrl":"https://covers.openlibrary.org/b/id/42-L.jpg","authors":[{"id":"OL1A","name":"Author of OL1W"}]}},` +
+		`{"id":2,"status":"WANT_TO_READ","work":{"title":"Work OL2W","coverUrl":"https://covers.openlibrary.org/b/id/42-L.jpg","authors":[{"id":"OL1A","name":"Author of OL2W"}]}},` +
+		`{"id":3,"status":"WANT_TO_READ","work":null}]}`
+	if string(resp.Data) != want {
+		t.Errorf("data:\ngot  %s\nwant %s", resp.Data, want)
+	}
+	if n := len(ol.requests); n != 1 {
+		t.Errorf("upstream requests: got %d (%v), want 1", n, ol.requests)
+	}
+}
+
+func TestGraphQL_FetchesEachWorkOnce(t *testing.T) {
+	h, ol := newGraphQLHandler(t, &fakeStore{books: threeBooks()})
+
+	// Search returns OL1W twice, and a readlist entry shares it too.
+	resp := execGraphQL(t, h, nil, `{
+		search(query: "dune") { workId work { description editions(first: 1) { id isbn13 languages pages publishDate } } }
+		entry(workId: "OL1W") { work { description } }
+	}`, nil)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("errors: %+v", resp.Errors)
+	}
+	var data struct {
+		Search []struct {
+			WorkID string
+			Work   struct {
+				Description string
+				Editions    []map[string]any
+			}
+		}
+	}
+	json.Unmarshal(resp.Data, &data)
+	if len(data.Search) != 3 || data.Search[2].Work.Description != "Spice." {
+		t.Fatalf("unexpected data: %s", resp.Data)
+	}
+	if eds := data.Search[0].Work.Editions; len(eds) != 1 || eds[0]["id"] != "OL1M" || eds[0]["publishDate"] != nil {
+		t.Errorf("editions: got %v", eds)
+	}
+
+	for prefix, want := range map[string]int{
+		"/search.json?q=dune":       1,
+		"/search.json?fields":       1,
+		"/works/OL1W.json":          1,
+		"/works/OL2W.json":          1,
+		"/works/OL1W/editions.json": 1,
+		"/works/OL2W/editions.json": 1,
+	} {
+		if got := ol.count(prefix); got != want {
+			t.Errorf("requests to %s: got %d, want %d (%v)", prefix, got, want, ol.requests)
+		}
+	}
+}
+
+func TestGraphQL_UpstreamBudget(t *testing.T) {
+	h, ol := newGraphQLHandler(t, &fakeStore{books: threeBooks()})
+	var mu sync.Mutex
+	budget := 3
+	h.AllowUpstream = func(*http.Request) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		budget--
+		return budget >= 0
+	}
+
+	// Three aliased searches, plus the works and descriptions under the first, need
+	// more upstream calls than the budget allows.
+	resp := execGraphQL(t, h, nil, `{
+		a: search(query: "dune") { work { description } }
+		b: search(query: "dune messiah") { workId }
+		c: search(query: "children of dune") { workId }
+	}`, nil)
+
+	if len(ol.requests) != 3 {
+		t.Errorf("upstream requests: got %d (%v), want the 3 budgeted", len(ol.requests), ol.requests)
+	}
+	limited := 0
+	for _, e := range resp.Errors {
+		if e.Extensions.Code != "rate_limited" {
+			t.Errorf("unexpected error: %+v", e)
+		}
+		limited++
+	}
+	if limited == 0 {
+		t.Error("expected rate_limited errors once the budget was spent")
+	}
+}
+
+func TestGraphQL_ReadlistFilter(t *testing.T) {
+	h, ol := newGraphQLHandler(t, &fakeStore{books: threeBooks()})
+
+	resp := execGraphQL(t, h, nil, `{ readlist(status: WANT_TO_READ, sort: ADDED_DESC) { id workId updatedAt } }`, nil)
+	want := `{"readlist":[{"id":3,"workId":"OL9W","updatedAt":"2026-01-02T03:04:05Z"},{"id":2,"workId":"OL2W","updatedAt":"2026-01-02T03:04:05Z"}]}`
+	if len(resp.Errors) > 0 || string(resp.Data) != want {
+		t.Errorf("got %s %+v, want %s", resp.Data, resp.Errors, want)
+	}
+	if len(ol.requests) != 0 {
+		t.Errorf("work was not selected, yet Open Library was called: %v", ol.requests)
+	}
+}
+
+func TestGraphQL_AddToReadlist(t *testing.T) {
+	store := &fakeStore{addedID: 42}
+	h, _ := newGraphQLHandler(t, store)
+	const mutation = `mutation($input: AddToReadlistInput!) { addToReadlist(input: $input) { id status version } }`
+
+	resp := execGraphQL(t, h, nil, mutation, map[string]any{
+		"input": map[string]any{"workId": "OL1W", "title": "Dune", "authors": "Frank Herbert"},
+	})
+	if len(resp.Errors) > 0 || string(resp.Data) != `{"addToReadlist":{"id":42,"status":"WANT_TO_READ","version":1}}` {
+		t.Errorf("got %s %+v", resp.Data, resp.Errors)
+	}
+	if store.addArg.WorkID != "OL1W" || store.addArg.UserID != testSub {
+		t.Errorf("store got %+v", store.addArg)
+	}
+
+	resp = execGraphQL(t, h, nil, mutation, map[string]any{
+		"input": map[string]any{"workId": "", "title": strings.Repeat("a", 501), "authors": "Frank Herbert"},
+	})
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions.Code != "validation_failed" {
+		t.Fatalf("errors: got %+v, want validation_failed", resp.Errors)
+	}
+	if f := resp.Errors[0].Extensions.Fields; len(f) != 2 || f["input.workId"] != "is required" || f["input.title"] == "" {
+		t.Errorf("fields: got %v", f)
+	}
+}
+
+func TestGraphQL_UpdateReadlistEntry(t *testing.T) {
+	store := &fakeStore{books: seedBook(), updatedBook: database.Book{ID: 1, UserID: testSub, Status: "finished", Version: 4}}
+	h, _ := newGraphQLHandler(t, store)
+	const mutation = `mutation($input: UpdateReadlistEntryInput!, $ifMatch: Int) {
+		updateReadlistEntry(id: 1, input: $input, ifMatch: $ifMatch) { status rating version }
+	}`
+
+	resp := execGraphQL(t, h, nil, mutation, map[string]any{"input": map[string]any{"status": "FINISHED", "rating": nil}, "ifMatch": 3})
+	if len(resp.Errors) > 0 || string(resp.Data) != `{"updateReadlistEntry":{"status":"FINISHED","rating":null,"version":4}}` {
+		t.Errorf("got %s %+v", resp.Data, resp.Errors)
+	}
+	arg := store.updateArg
+	if arg.Status.String != "finished" || !arg.SetRating || arg.Rating.Valid || arg.SetNotes || !slices.Equal(arg.IfMatch, []int32{3}) {
+		t.Errorf("store got %+v", arg)
+	}
+
+	resp = execGraphQL(t, h, nil, mutation, map[string]any{"input": map[string]any{"status": "FINISHED"}})
+	if len(resp.Errors) > 0 || store.updateArg.IfMatch != nil {
+		t.Errorf("without ifMatch: got %+v, IfMatch %v, want an unconditional update", resp.Errors, store.updateArg.IfMatch)
+	}
+
+	cases := []struct {
+		name      string
+		variables map[string]any
+		wantCode  string
+	}{
+		{"stale", map[string]any{"input": map[string]any{"status": "READING"}, "ifMatch": 2}, "version_mismatch"},
+		{"rating out of range", map[string]any{"input": map[string]any{"rating": 6}}, "validation_failed"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := execGraphQL(t, h, nil, mutation, tc.variables)
+			if len(resp.Errors) != 1 || resp.Errors[0].Extensions.Code != tc.wantCode {
+				t.Errorf("errors: got %+v, want %s", resp.Errors, tc.wantCode)
+			}
+		})
+	}
+}
+
+func TestGraphQL_DeleteReadlistEntry(t *testing.T) {
+	h, _ := newGraphQLHandler(t, &fakeStore{books: seedBook()})
+
+	resp := execGraphQL(t, h, nil, `mutation { deleteReadlistEntry(id: 1) }`, nil)
+	if len(resp.Errors) > 0 || string(resp.Data) != `{"deleteReadlistEntry":1}` {
+		t.Errorf("got %s %+v", resp.Data, resp.Errors)
+	}
+
+	resp = execGraphQL(t, h, nil, `mutation { deleteReadlistEntry(id: 9) }`, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions.Code != "not_found" || string(resp.Data) != "null" {
+		t.Errorf("got %s %+v, want not_found", resp.Data, resp.Errors)
+	}
+}
+
+func TestGraphQL_PersonalTokenScopes(t *testing.T) {
+	h, _ := newGraphQLHandler(t, &fakeStore{books: seedBook()})
+	readOnly := &appauth.Principal{Subject: testSub, PersonalToken: true, Scopes: []string{appauth.ScopeReadlistRead}}
+
+	resp := execGraphQL(t, h, readOnly, `{ readlist { id } }`, nil)
+	if len(resp.Errors) > 0 {
+		t.Errorf("query with read scope: %+v", resp.Errors)
+	}
+	resp = execGraphQL(t, h, readOnly, `mutation { deleteReadlistEntry(id: 1) }`, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions.Code != "insufficient_scope" {
+		t.Errorf("mutation with read scope: got %+v, want insufficient_scope", resp.Errors)
+	}
+	resp = execGraphQL(t, h, &appauth.Principal{Subject: testSub, PersonalToken: true}, `{ readlist { id } }`, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions.Code != "insufficient_scope" {
+		t.Errorf("query without scopes: got %+v, want insufficient_scope", resp.Errors)
+	}
+}
+
+func TestGraphQL_RequestBody(t *testing.T) {
+	h, _ := newGraphQLHandler(t, &fakeStore{})
+	cases := map[string]int{
+		`{"query":""}`:                   http.StatusUnprocessableEntity,
+		`{"query":"{ readlist { id } }"`: http.StatusBadRequest,
+		`{"query":"{}","colour":"red"}`:  http.StatusUnprocessableEntity,
+	}
+	for body, want := range cases {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, withSub(httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body)), testSub))
+		if w.Code != want {
+			t.Errorf("%s: got %d, want %d", body, w.Code, want)
+		}
+	}
+}
+
+func TestGraphQL_SyntaxError(t *testing.T) {
+	h, _ := newGraphQLHandler(t, &fakeStore{})
+	resp := execGraphQL(t, h, nil, `{ readlist { id `, nil)
+	if len(resp.Errors) == 0 || resp.Data != nil {
+		t.Errorf("got %s %+v, want an error and no data", resp.Data, resp.Errors)
+	}
+}
+
+func TestGraphQL_NotesStoredAsNull(t *testing.T) {
+	store := &fakeStore{books: seedBook(), updatedBook: database.Book{ID: 1, UserID: testSub, Status: "reading", Version: 4, Notes: sql.NullString{}}}
+	h, _ := newGraphQLHandler(t, store)
+
+	resp := execGraphQL(t, h, nil, `mutation { updateReadlistEntry(id: 1, input: {notes: null}) { notes } }`, nil)
+	if len(resp.Errors) > 0 || string(resp.Data) != `{"updateReadlistEntry":{"notes":null}}` {
+		t.Errorf("got %s %+v", resp.Data, resp.Errors)
+	}
+	if !store.updateArg.SetNotes || store.updateArg.Notes.Valid || store.updateArg.SetRating || store.updateArg.Status.Valid {
+		t.Errorf("store got %+v", store.updateArg)
+	}
+}
+
+func TestGraphQL_LongReadlistIsOneBatch(t *testing.T) {
+	// More entries than the executor resolves at once.
+	var books []database.Book
+	for i := range 30 {
+		books = append(books, database.Book{ID: int32(i + 1), UserID: testSub, WorkID: "OL" + strconv.Itoa(i+1) + "W", Status: "reading"})
+	}
+	h, ol := newGraphQLHandler(t, &fakeStore{books: books})
+
+	resp := execGraphQL(t, h, nil, `{ readlist { work { title } } }`, nil)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("errors: %+v", resp.Errors)
+	}
+	if n := len(ol.requests); n != 1 {
+		t.Errorf("upstream requests: got %d, want 1", n)
+	}
+}
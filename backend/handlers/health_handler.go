@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+)
+
+// HealthHandler reports whether the server can serve traffic.
+type HealthHandler struct {
+	// Ping checks the database connection.
+	Ping func(ctx context.Context) error
+	// AuthReady reports whether token verification keys have been loaded.
+	AuthReady func() bool
+}
+
+// Health responds 503 when the database is unreachable and 200 otherwise. While
+// token verification keys are still loading the body reports auth as "starting",
+// but the server stays healthy: anonymous routes already work, and failing the
+// check would have orchestrators restart it before discovery can finish.
+func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
+	status, code := "ok", http.StatusOK
+	db, auth := "ok", "ready"
+	if !h.AuthReady() {
+		auth, status = "starting", "starting"
+	}
+	if err := h.Ping(r.Context()); err != nil {
+		db, status, code = "unavailable", "unhealthy", http.StatusServiceUnavailable
+	}
+	WriteJSON(w, code, map[string]string{"status": status, "database": db, "auth": auth})
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealth(t *testing.T) {
+	down := errors.New("connection refused")
+	tests := []struct {
+		name      string
+		pingErr   error
+		authReady bool
+		wantCode  int
+		want      map[string]string
+	}{
+		{"ready", nil, true, http.StatusOK, map[string]string{"status": "ok", "database": "ok", "auth": "ready"}},
+		{"auth starting", nil, false, http.StatusOK, map[string]string{"status": "starting", "database": "ok", "auth": "starting"}},
+		{"database down", down, true, http.StatusServiceUnavailable, map[string]string{"status": "unhealthy", "database": "unavailable", "auth": "ready"}},
+		{"both", down, false, http.StatusServiceUnavailable, map[string]string{"status": "unhealthy", "database": "unavailable", "auth": "starting"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &HealthHandler{
+				Ping:      func(context.Context) error { return tt.pingErr },
+				AuthReady: func() bool { return tt.authReady },
+			}
+			w := httptest.NewRecorder()
+			h.Health(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+			if w.Code != tt.wantCode {
+				t.Errorf("status: got %d, want %d", w.Code, tt.wantCode)
+			}
+			var got map[string]string
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("%s: got %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
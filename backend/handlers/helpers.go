@@ -1,8 +1,18 @@
 package handlers
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 100
 )
 
 func WriteJSON(w http.ResponseWriter, status int, v any) {
@@ -11,6 +21,39 @@ func WriteJSON(w http.ResponseWriter, status int, v any) {
 	json.NewEncoder(w).Encode(v)
 }
 
-func WriteError(w http.ResponseWriter, status int, msg string) {
-	WriteJSON(w, status, map[string]string{"error": msg})
+// parsePagination reads the limit and offset query parameters, applying
+// defaultPageLimit when limit is absent and rejecting values above maxPageLimit.
+func parsePagination(r *http.Request) (limit, offset int32, err error) {
+	limit = defaultPageLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n < 1 || n > maxPageLimit {
+			return 0, 0, errors.New("limit must be between 1 and 100")
+		}
+		limit = int32(n)
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+		offset = int32(n)
+	}
+	return limit, offset, nil
+}
+
+// newSecretToken returns a random single-use token for the client and the SHA-256
+// hash to store in its place.
+func newSecretToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = hex.EncodeToString(b)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/problem"
+	"github.com/go-chi/chi/v5"
+)
+
+const maxCopyLocationLength = 200
+
+var (
+	copyFormats    = []string{"hardcover", "paperback", "ebook", "audiobook", "other"}
+	copyConditions = []string{"new", "like_new", "good", "fair", "poor"}
+)
+
+// HouseholdCopyResponse is one copy in the household library. ReadlistStatus and
+// ReadlistRating are the caller's own, from their readlist entry for the work.
+type HouseholdCopyResponse struct {
+	ID             int32     `json:"id"`
+	WorkID         string    `json:"work_id"`
+	Title          string    `json:"title"`
+	Authors        string    `json:"authors"`
+	CoverArtURL    *string   `json:"cover_art_url"`
+	Format         string    `json:"format"`
+	Condition      *string   `json:"condition"`
+	Location       *string   `json:"location"`
+	AddedBy        string    `json:"added_by"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	ReadlistStatus *string   `json:"readlist_status,omitempty"`
+	ReadlistRating *int32    `json:"readlist_rating,omitempty"`
+}
+
+func toHouseholdCopyResponse(c database.HouseholdCopy) HouseholdCopyResponse {
+	r := HouseholdCopyResponse{
+		ID:        c.ID,
+		WorkID:    c.WorkID,
+		Title:     c.Title,
+		Authors:   c.Authors,
+		Format:    c.Format,
+		AddedBy:   c.AddedBy,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+	if c.CoverArtUrl.Valid {
+		r.CoverArtURL = &c.CoverArtUrl.String
+	}
+	if c.Condition.Valid {
+		r.Condition = &c.Condition.String
+	}
+	if c.Location.Valid {
+		r.Location = &c.Location.String
+	}
+	return r
+}
+
+// ListCopies returns the household library, each copy annotated with the caller's
+// own readlist status and rating for its work.
+func (h *HouseholdHandler) ListCopies(w http.ResponseWriter, r *http.Request) {
+	member, ok := h.loadMembership(w, r)
+	if !ok {
+		return
+	}
+
+	copies, err := h.Queries.ListHouseholdCopies(r.Context(), database.ListHouseholdCopiesParams{
+		UserID:      member.UserID,
+		HouseholdID: member.HouseholdID,
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to retrieve copies")
+		return
+	}
+	out := make([]HouseholdCopyResponse, len(copies))
+	for i, c := range copies {
+		out[i] = toHouseholdCopyResponse(database.HouseholdCopy{
+			ID:          c.ID,
+			HouseholdID: c.HouseholdID,
+			WorkID:      c.WorkID,
+			Title:       c.Title,
+			Authors:     c.Authors,
+			CoverArtUrl: c.CoverArtUrl,
+			Format:      c.Format,
+			Condition:   c.Condition,
+			Location:    c.Location,
+			AddedBy:     c.AddedBy,
+			CreatedAt:   c.CreatedAt,
+			UpdatedAt:   c.UpdatedAt,
+		})
+		if c.ReadlistStatus.Valid {
+			out[i].ReadlistStatus = &c.ReadlistStatus.String
+		}
+		if c.ReadlistRating.Valid {
+			out[i].ReadlistRating = &c.ReadlistRating.Int32
+		}
+	}
+	WriteJSON(w, http.StatusOK, out)
+}
+
+// AddCopy records a copy the household owns. Any member may add copies.
+func (h *HouseholdHandler) AddCopy(w http.ResponseWriter, r *http.Request) {
+	member, ok := h.loadMembership(w, r)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		WorkID      string  `json:"work_id" maxlen:"50"`
+		Title       string  `json:"title" maxlen:"500"`
+		Authors     string  `json:"authors" maxlen:"1000"`
+		CoverArtURL *string `json:"cover_art_url" maxlen:"2000"`
+		Format      string  `json:"format"`
+		Condition   *string `json:"condition"`
+		Location    *string `json:"location"`
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	errs := map[string]string{}
+	for field, v := range map[string]string{"work_id": input.WorkID, "title": input.Title, "authors": input.Authors} {
+		if v == "" {
+			errs[field] = "is required"
+		}
+	}
+	validateCopy(errs, input.Format, input.Condition, input.Location)
+	if len(errs) > 0 {
+		problem.WriteInvalid(w, r, errs)
+		return
+	}
+
+	created, err := h.Queries.CreateHouseholdCopy(r.Context(), database.CreateHouseholdCopyParams{
+		HouseholdID: member.HouseholdID,
+		WorkID:      input.WorkID,
+		Title:       input.Title,
+		Authors:     input.Authors,
+		CoverArtUrl: toNullString(input.CoverArtURL),
+		Format:      input.Format,
+		Condition:   toNullString(input.Condition),
+		Location:    toNullString(input.Location),
+		AddedBy:     member.UserID,
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to add copy")
+		return
+	}
+	WriteJSON(w, http.StatusCreated, toHouseholdCopyResponse(created))
+}
+
+// PatchCopy updates a copy's format, condition and location with a JSON merge
+// patch; condition and location may be null. Admins, the owner and the member who
+// added the copy may edit it.
+func (h *HouseholdHandler) PatchCopy(w http.ResponseWriter, r *http.Request) {
+	member, current, ok := h.loadEditableCopy(w, r)
+	if !ok {
+		return
+	}
+
+	body, ok := readMergePatch(w, r)
+	if !ok {
+		return
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(body, &doc); err != nil || doc == nil {
+		problem.Write(w, r, problem.InvalidBody, "merge patch must be a JSON object")
+		return
+	}
+
+	arg := database.UpdateHouseholdCopyParams{
+		ID:          current.ID,
+		HouseholdID: member.HouseholdID,
+		Format:      current.Format,
+		Condition:   current.Condition,
+		Location:    current.Location,
+	}
+	errs := map[string]string{}
+	for field, raw := range doc {
+		var v *string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			errs[field] = "must be a string"
+			continue
+		}
+		switch field {
+		case "format":
+			if v == nil {
+				errs[field] = "cannot be null"
+				continue
+			}
+			arg.Format = *v
+		case "condition":
+			arg.Condition = toNullString(v)
+		case "location":
+			arg.Location = toNullString(v)
+		default:
+			errs[field] = "cannot be changed"
+		}
+	}
+	var condition, location *string
+	if arg.Condition.Valid {
+		condition = &arg.Condition.String
+	}
+	if arg.Location.Valid {
+		location = &arg.Location.String
+	}
+	validateCopy(errs, arg.Format, condition, location)
+	if len(errs) > 0 {
+		problem.WriteInvalid(w, r, errs)
+		return
+	}
+
+	updated, err := h.Queries.UpdateHouseholdCopy(r.Context(), arg)
+	if errors.Is(err, sql.ErrNoRows) {
+		problem.Write(w, r, problem.NotFound, "copy not found")
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to update copy")
+		return
+	}
+	WriteJSON(w, http.StatusOK, toHouseholdCopyResponse(updated))
+}
+
+// DeleteCopy removes a copy from the household library. Members' readlist entries
+// for the work are untouched.
+func (h *HouseholdHandler) DeleteCopy(w http.ResponseWriter, r *http.Request) {
+	member, current, ok := h.loadEditableCopy(w, r)
+	if !ok {
+		return
+	}
+
+	n, err := h.Queries.DeleteHouseholdCopy(r.Context(), database.DeleteHouseholdCopyParams{
+		ID:          current.ID,
+		HouseholdID: member.HouseholdID,
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to delete copy")
+		return
+	}
+	if n == 0 {
+		problem.Write(w, r, problem.NotFound, "copy not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadEditableCopy resolves {id} and {copyID} to a copy in one of the caller's
+// households that the caller may change.
+func (h *HouseholdHandler) loadEditableCopy(w http.ResponseWriter, r *http.Request) (database.HouseholdMember, database.HouseholdCopy, bool) {
+	member, ok := h.loadMembership(w, r)
+	if !ok {
+		return database.HouseholdMember{}, database.HouseholdCopy{}, false
+	}
+
+	copyID, err := strconv.ParseInt(chi.URLParam(r, "copyID"), 10, 32)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidParameter, "invalid copy id")
+		return database.HouseholdMember{}, database.HouseholdCopy{}, false
+	}
+
+	c, err := h.Queries.GetHouseholdCopy(r.Context(), database.GetHouseholdCopyParams{
+		ID:          int32(copyID),
+		HouseholdID: member.HouseholdID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		problem.Write(w, r, problem.NotFound, "copy not found")
+		return database.HouseholdMember{}, database.HouseholdCopy{}, false
+	}
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to retrieve copy")
+		return database.HouseholdMember{}, database.HouseholdCopy{}, false
+	}
+	if c.AddedBy != member.UserID && householdRoleRank[member.Role] < householdRoleRank[roleAdmin] {
+		problem.Write(w, r, problem.Forbidden, "only admins and the member who added a copy can change it")
+		return database.HouseholdMember{}, database.HouseholdCopy{}, false
+	}
+	return member, c, true
+}
+
+func validateCopy(errs map[string]string, format string, condition, location *string) {
+	if !slices.Contains(copyFormats, format) {
+		errs["format"] = "must be one of " + strings.Join(copyFormats, ", ")
+	}
+	if condition != nil && !slices.Contains(copyConditions, *condition) {
+		errs["condition"] = "must be one of " + strings.Join(copyConditions, ", ")
+	}
+	if location != nil && len(*location) > maxCopyLocationLength {
+		errs["location"] = "must be at most " + strconv.Itoa(maxCopyLocationLength) + " characters"
+	}
+}
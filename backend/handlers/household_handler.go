@@ -0,0 +1,523 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/problem"
+	"github.com/go-chi/chi/v5"
+	"github.com/lib/pq"
+)
+
+// Household roles, from most to least privileged. The owner manages roles and can
+// delete the household; admins invite and remove members and manage every copy;
+// members see the library and manage the copies they added.
+const (
+	roleOwner  = "owner"
+	roleAdmin  = "admin"
+	roleMember = "member"
+
+	maxHouseholdNameLength = 100
+	householdInviteTTL     = 7 * 24 * time.Hour
+)
+
+var householdRoleRank = map[string]int{roleMember: 1, roleAdmin: 2, roleOwner: 3}
+
+// errInviteInvalid and errAlreadyMember abort JoinHousehold's transaction.
+var (
+	errInviteInvalid = errors.New("invalid or expired invite")
+	errAlreadyMember = errors.New("already a member of this household")
+)
+
+// HouseholdStore is the persistence interface for households, their members,
+// invites and copies. *database.Queries satisfies it.
+type HouseholdStore interface {
+	CreateHousehold(ctx context.Context, name string) (database.Household, error)
+	GetHousehold(ctx context.Context, id int32) (database.Household, error)
+	DeleteHousehold(ctx context.Context, id int32) (int64, error)
+	ListHouseholdsForUser(ctx context.Context, userID string) ([]database.ListHouseholdsForUserRow, error)
+	AddHouseholdMember(ctx context.Context, arg database.AddHouseholdMemberParams) (database.HouseholdMember, error)
+	GetHouseholdMember(ctx context.Context, arg database.GetHouseholdMemberParams) (database.HouseholdMember, error)
+	ListHouseholdMembers(ctx context.Context, householdID int32) ([]database.ListHouseholdMembersRow, error)
+	SetHouseholdMemberRole(ctx context.Context, arg database.SetHouseholdMemberRoleParams) (database.HouseholdMember, error)
+	RemoveHouseholdMember(ctx context.Context, arg database.RemoveHouseholdMemberParams) (int64, error)
+	CreateHouseholdInvite(ctx context.Context, arg database.CreateHouseholdInviteParams) (database.HouseholdInvite, error)
+	ConsumeHouseholdInvite(ctx context.Context, arg database.ConsumeHouseholdInviteParams) (database.HouseholdInvite, error)
+	ListHouseholdCopies(ctx context.Context, arg database.ListHouseholdCopiesParams) ([]database.ListHouseholdCopiesRow, error)
+	GetHouseholdCopy(ctx context.Context, arg database.GetHouseholdCopyParams) (database.HouseholdCopy, error)
+	CreateHouseholdCopy(ctx context.Context, arg database.CreateHouseholdCopyParams) (database.HouseholdCopy, error)
+	UpdateHouseholdCopy(ctx context.Context, arg database.UpdateHouseholdCopyParams) (database.HouseholdCopy, error)
+	DeleteHouseholdCopy(ctx context.Context, arg database.DeleteHouseholdCopyParams) (int64, error)
+}
+
+// TxHouseholdStore is a HouseholdStore that can also group calls into one
+// transaction, like TxBookStore.
+type TxHouseholdStore interface {
+	HouseholdStore
+	WithinTx(ctx context.Context, fn func(HouseholdStore) error) error
+}
+
+// DBHouseholdStore implements TxHouseholdStore over the generated queries and their database.
+type DBHouseholdStore struct {
+	*database.Queries
+	DB *sql.DB
+}
+
+func (s *DBHouseholdStore) WithinTx(ctx context.Context, fn func(HouseholdStore) error) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(s.Queries.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// HouseholdHandler serves households. Every route under /households/{id} first
+// resolves the caller's membership; non-members get 404 so households they do
+// not belong to stay invisible.
+type HouseholdHandler struct {
+	Queries TxHouseholdStore
+}
+
+type HouseholdMemberResponse struct {
+	UserID   string    `json:"user_id"`
+	Username *string   `json:"username"`
+	Name     *string   `json:"name"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type HouseholdResponse struct {
+	ID        int32     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	// Role is the caller's role in the household.
+	Role string `json:"role"`
+	// Members is only set when a single household is requested.
+	Members []HouseholdMemberResponse `json:"members,omitempty"`
+}
+
+// HouseholdInviteResponse carries the invite token, which is only returned once.
+type HouseholdInviteResponse struct {
+	Token     string    `json:"token"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func toHouseholdMemberResponse(m database.HouseholdMember) HouseholdMemberResponse {
+	return HouseholdMemberResponse{UserID: m.UserID, Role: m.Role, JoinedAt: m.JoinedAt}
+}
+
+func (h *HouseholdHandler) CreateHousehold(w http.ResponseWriter, r *http.Request) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+
+	var input struct {
+		Name string `json:"name"`
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+	if msg := validateHouseholdName(input.Name); msg != "" {
+		problem.WriteInvalid(w, r, map[string]string{"name": msg})
+		return
+	}
+
+	var household database.Household
+	err := h.Queries.WithinTx(r.Context(), func(tx HouseholdStore) error {
+		var err error
+		household, err = tx.CreateHousehold(r.Context(), strings.TrimSpace(input.Name))
+		if err != nil {
+			return err
+		}
+		_, err = tx.AddHouseholdMember(r.Context(), database.AddHouseholdMemberParams{
+			HouseholdID: household.ID,
+			UserID:      sub,
+			Role:        roleOwner,
+		})
+		return err
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to create household")
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, HouseholdResponse{
+		ID:        household.ID,
+		Name:      household.Name,
+		CreatedAt: household.CreatedAt,
+		Role:      roleOwner,
+	})
+}
+
+// ListHouseholds returns the households the caller belongs to.
+func (h *HouseholdHandler) ListHouseholds(w http.ResponseWriter, r *http.Request) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+
+	households, err := h.Queries.ListHouseholdsForUser(r.Context(), sub)
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to retrieve households")
+		return
+	}
+	out := make([]HouseholdResponse, len(households))
+	for i, hh := range households {
+		out[i] = HouseholdResponse{ID: hh.ID, Name: hh.Name, CreatedAt: hh.CreatedAt, Role: hh.Role}
+	}
+	WriteJSON(w, http.StatusOK, out)
+}
+
+// GetHousehold returns a household the caller belongs to, with its members.
+func (h *HouseholdHandler) GetHousehold(w http.ResponseWriter, r *http.Request) {
+	member, ok := h.loadMembership(w, r)
+	if !ok {
+		return
+	}
+
+	household, err := h.Queries.GetHousehold(r.Context(), member.HouseholdID)
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to retrieve household")
+		return
+	}
+	members, err := h.Queries.ListHouseholdMembers(r.Context(), member.HouseholdID)
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to retrieve household members")
+		return
+	}
+
+	resp := HouseholdResponse{
+		ID:        household.ID,
+		Name:      household.Name,
+		CreatedAt: household.CreatedAt,
+		Role:      member.Role,
+		Members:   make([]HouseholdMemberResponse, len(members)),
+	}
+	for i, m := range members {
+		resp.Members[i] = HouseholdMemberResponse{UserID: m.UserID, Role: m.Role, JoinedAt: m.JoinedAt}
+		if m.Username.Valid {
+			resp.Members[i].Username = &m.Username.String
+		}
+		if m.Name.Valid {
+			resp.Members[i].Name = &m.Name.String
+		}
+	}
+	WriteJSON(w, http.StatusOK, resp)
+}
+
+// DeleteHousehold deletes a household with its members, invites and copies. Only
+// the owner may do so; members' readlists are untouched.
+func (h *HouseholdHandler) DeleteHousehold(w http.ResponseWriter, r *http.Request) {
+	member, ok := h.loadMembership(w, r)
+	if !ok {
+		return
+	}
+	if member.Role != roleOwner {
+		problem.Write(w, r, problem.Forbidden, "only the owner can delete the household")
+		return
+	}
+
+	if _, err := h.Queries.DeleteHousehold(r.Context(), member.HouseholdID); err != nil {
+		problem.Write(w, r, problem.Internal, "failed to delete household")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateInvite issues a single-use invite token for the household. Admins and the
+// owner may invite, with a role no higher than admin.
+func (h *HouseholdHandler) CreateInvite(w http.ResponseWriter, r *http.Request) {
+	member, ok := h.loadMembership(w, r)
+	if !ok {
+		return
+	}
+	if householdRoleRank[member.Role] < householdRoleRank[roleAdmin] {
+		problem.Write(w, r, problem.Forbidden, "only admins can invite members")
+		return
+	}
+
+	var input struct {
+		Role string `json:"role"`
+	}
+	if !decodeOptionalJSON(w, r, &input) {
+		return
+	}
+	if input.Role == "" {
+		input.Role = roleMember
+	}
+	if input.Role != roleMember && input.Role != roleAdmin {
+		problem.WriteInvalid(w, r, map[string]string{"role": "must be member or admin"})
+		return
+	}
+
+	token, hash, err := newSecretToken()
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to generate invite")
+		return
+	}
+	invite, err := h.Queries.CreateHouseholdInvite(r.Context(), database.CreateHouseholdInviteParams{
+		HouseholdID: member.HouseholdID,
+		TokenHash:   hash,
+		Role:        input.Role,
+		CreatedBy:   member.UserID,
+		ExpiresAt:   time.Now().Add(householdInviteTTL),
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to create invite")
+		return
+	}
+
+	// Only the hash is kept; the plaintext token must not outlive this response.
+	w.Header().Set("Cache-Control", "no-store")
+	WriteJSON(w, http.StatusCreated, HouseholdInviteResponse{
+		Token:     token,
+		Role:      invite.Role,
+		ExpiresAt: invite.ExpiresAt,
+	})
+}
+
+// JoinHousehold accepts an invite token, adding the caller to its household with
+// the invited role. The invite stays unused if the caller is already a member.
+func (h *HouseholdHandler) JoinHousehold(w http.ResponseWriter, r *http.Request) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+
+	var input struct {
+		Token string `json:"token"`
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+	if input.Token == "" {
+		problem.WriteInvalid(w, r, map[string]string{"token": "is required"})
+		return
+	}
+
+	var (
+		member    database.HouseholdMember
+		household database.Household
+	)
+	err := h.Queries.WithinTx(r.Context(), func(tx HouseholdStore) error {
+		invite, err := tx.ConsumeHouseholdInvite(r.Context(), database.ConsumeHouseholdInviteParams{
+			TokenHash:  hashToken(input.Token),
+			AcceptedBy: sql.NullString{String: sub, Valid: true},
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return errInviteInvalid
+		}
+		if err != nil {
+			return err
+		}
+		member, err = tx.AddHouseholdMember(r.Context(), database.AddHouseholdMemberParams{
+			HouseholdID: invite.HouseholdID,
+			UserID:      sub,
+			Role:        invite.Role,
+		})
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return errAlreadyMember
+		}
+		if err != nil {
+			return err
+		}
+		household, err = tx.GetHousehold(r.Context(), invite.HouseholdID)
+		return err
+	})
+	switch {
+	case errors.Is(err, errInviteInvalid):
+		problem.WriteInvalid(w, r, fieldErrors{"token": "is invalid or has expired"})
+		return
+	case errors.Is(err, errAlreadyMember):
+		problem.Write(w, r, problem.AlreadyMember, err.Error())
+		return
+	case err != nil:
+		problem.Write(w, r, problem.Internal, "failed to join household")
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, HouseholdResponse{
+		ID:        household.ID,
+		Name:      household.Name,
+		CreatedAt: household.CreatedAt,
+		Role:      member.Role,
+	})
+}
+
+// UpdateMember changes a member's role. Only the owner may do so; making another
+// member the owner transfers ownership and leaves the previous owner an admin.
+func (h *HouseholdHandler) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	caller, ok := h.loadMembership(w, r)
+	if !ok {
+		return
+	}
+	if caller.Role != roleOwner {
+		problem.Write(w, r, problem.Forbidden, "only the owner can change roles")
+		return
+	}
+	target, ok := h.loadMember(w, r, caller.HouseholdID)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Role string `json:"role"`
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+	if _, ok := householdRoleRank[input.Role]; !ok {
+		problem.WriteInvalid(w, r, map[string]string{"role": "must be one of owner, admin, member"})
+		return
+	}
+	if target.UserID == caller.UserID {
+		problem.Write(w, r, problem.OwnershipTransferRequired, "the owner's role changes only by transferring ownership")
+		return
+	}
+
+	var updated database.HouseholdMember
+	err := h.Queries.WithinTx(r.Context(), func(tx HouseholdStore) error {
+		// The previous owner steps down first: a household has one owner at a time.
+		if input.Role == roleOwner {
+			if _, err := tx.SetHouseholdMemberRole(r.Context(), database.SetHouseholdMemberRoleParams{
+				HouseholdID: caller.HouseholdID,
+				UserID:      caller.UserID,
+				Role:        roleAdmin,
+			}); err != nil {
+				return err
+			}
+		}
+		var err error
+		updated, err = tx.SetHouseholdMemberRole(r.Context(), database.SetHouseholdMemberRoleParams{
+			HouseholdID: caller.HouseholdID,
+			UserID:      target.UserID,
+			Role:        input.Role,
+		})
+		return err
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// Left between the lookup and the update.
+		problem.Write(w, r, problem.NotFound, "member not found")
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to update member")
+		return
+	}
+	WriteJSON(w, http.StatusOK, toHouseholdMemberResponse(updated))
+}
+
+// RemoveMember removes a member from the household. Anyone but the owner may leave;
+// admins and the owner may remove members ranked below them.
+func (h *HouseholdHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	caller, ok := h.loadMembership(w, r)
+	if !ok {
+		return
+	}
+	target, ok := h.loadMember(w, r, caller.HouseholdID)
+	if !ok {
+		return
+	}
+
+	if target.UserID == caller.UserID {
+		if caller.Role == roleOwner {
+			problem.Write(w, r, problem.OwnershipTransferRequired, "transfer ownership before leaving the household")
+			return
+		}
+	} else if householdRoleRank[caller.Role] < householdRoleRank[roleAdmin] ||
+		householdRoleRank[caller.Role] <= householdRoleRank[target.Role] {
+		problem.Write(w, r, problem.Forbidden, "not allowed to remove this member")
+		return
+	}
+
+	n, err := h.Queries.RemoveHouseholdMember(r.Context(), database.RemoveHouseholdMemberParams{
+		HouseholdID: caller.HouseholdID,
+		UserID:      target.UserID,
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to remove member")
+		return
+	}
+	if n == 0 {
+		problem.Write(w, r, problem.NotFound, "member not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadMembership resolves the {id} URL parameter to the caller's membership of that
+// household, writing the appropriate error response and returning ok=false
+// otherwise. This is what scopes every household route to its members.
+func (h *HouseholdHandler) loadMembership(w http.ResponseWriter, r *http.Request) (database.HouseholdMember, bool) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return database.HouseholdMember{}, false
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidParameter, "invalid id")
+		return database.HouseholdMember{}, false
+	}
+
+	member, err := h.Queries.GetHouseholdMember(r.Context(), database.GetHouseholdMemberParams{
+		HouseholdID: int32(id),
+		UserID:      sub,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		problem.Write(w, r, problem.NotFound, "household not found")
+		return database.HouseholdMember{}, false
+	}
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to retrieve household")
+		return database.HouseholdMember{}, false
+	}
+	return member, true
+}
+
+// loadMember resolves the {userID} URL parameter to a member of householdID.
+func (h *HouseholdHandler) loadMember(w http.ResponseWriter, r *http.Request, householdID int32) (database.HouseholdMember, bool) {
+	member, err := h.Queries.GetHouseholdMember(r.Context(), database.GetHouseholdMemberParams{
+		HouseholdID: householdID,
+		UserID:      chi.URLParam(r, "userID"),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		problem.Write(w, r, problem.NotFound, "member not found")
+		return database.HouseholdMember{}, false
+	}
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to retrieve member")
+		return database.HouseholdMember{}, false
+	}
+	return member, true
+}
+
+func validateHouseholdName(name string) string {
+	name = strings.TrimSpace(name)
+	switch {
+	case name == "":
+		return "is required"
+	case len(name) > maxHouseholdNameLength:
+		return "must be at most " + strconv.Itoa(maxHouseholdNameLength) + " characters"
+	}
+	return ""
+}
@@ -0,0 +1,505 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/lib/pq"
+)
+
+// fakeHouseholdStore is an in-memory TxHouseholdStore. WithinTx does not roll back.
+type fakeHouseholdStore struct {
+	households []database.Household
+	members    []database.HouseholdMember
+	invites    []database.HouseholdInvite
+	copies     []database.HouseholdCopy
+	statuses   map[string]string // work_id -> the caller's readlist status
+}
+
+func (f *fakeHouseholdStore) WithinTx(_ context.Context, fn func(HouseholdStore) error) error {
+	return fn(f)
+}
+
+func (f *fakeHouseholdStore) CreateHousehold(_ context.Context, name string) (database.Household, error) {
+	h := database.Household{ID: int32(len(f.households) + 1), Name: name, CreatedAt: time.Now()}
+	f.households = append(f.households, h)
+	return h, nil
+}
+
+func (f *fakeHouseholdStore) GetHousehold(_ context.Context, id int32) (database.Household, error) {
+	for _, h := range f.households {
+		if h.ID == id {
+			return h, nil
+		}
+	}
+	return database.Household{}, sql.ErrNoRows
+}
+
+func (f *fakeHouseholdStore) DeleteHousehold(_ context.Context, id int32) (int64, error) {
+	for i, h := range f.households {
+		if h.ID == id {
+			f.households = append(f.households[:i], f.households[i+1:]...)
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (f *fakeHouseholdStore) ListHouseholdsForUser(_ context.Context, userID string) ([]database.ListHouseholdsForUserRow, error) {
+	var out []database.ListHouseholdsForUserRow
+	for _, m := range f.members {
+		if m.UserID == userID {
+			h, _ := f.GetHousehold(context.Background(), m.HouseholdID)
+			out = append(out, database.ListHouseholdsForUserRow{ID: h.ID, Name: h.Name, CreatedAt: h.CreatedAt, Role: m.Role})
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeHouseholdStore) AddHouseholdMember(_ context.Context, arg database.AddHouseholdMemberParams) (database.HouseholdMember, error) {
+	for _, m := range f.members {
+		if m.HouseholdID == arg.HouseholdID && m.UserID == arg.UserID {
+			return database.HouseholdMember{}, &pq.Error{Code: "23505"}
+		}
+	}
+	m := database.HouseholdMember{HouseholdID: arg.HouseholdID, UserID: arg.UserID, Role: arg.Role, JoinedAt: time.Now()}
+	f.members = append(f.members, m)
+	return m, nil
+}
+
+func (f *fakeHouseholdStore) GetHouseholdMember(_ context.Context, arg database.GetHouseholdMemberParams) (database.HouseholdMember, error) {
+	for _, m := range f.members {
+		if m.HouseholdID == arg.HouseholdID && m.UserID == arg.UserID {
+			return m, nil
+		}
+	}
+	return database.HouseholdMember{}, sql.ErrNoRows
+}
+
+func (f *fakeHouseholdStore) ListHouseholdMembers(_ context.Context, householdID int32) ([]database.ListHouseholdMembersRow, error) {
+	var out []database.ListHouseholdMembersRow
+	for _, m := range f.members {
+		if m.HouseholdID == householdID {
+			out = append(out, database.ListHouseholdMembersRow{HouseholdID: m.HouseholdID, UserID: m.UserID, Role: m.Role, JoinedAt: m.JoinedAt})
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeHouseholdStore) SetHouseholdMemberRole(_ context.Context, arg database.SetHouseholdMemberRoleParams) (database.HouseholdMember, error) {
+	for i, m := range f.members {
+		if m.HouseholdID == arg.HouseholdID && m.UserID == arg.UserID {
+			f.members[i].Role = arg.Role
+			return f.members[i], nil
+		}
+	}
+	return database.HouseholdMember{}, sql.ErrNoRows
+}
+
+func (f *fakeHouseholdStore) RemoveHouseholdMember(_ context.Context, arg database.RemoveHouseholdMemberParams) (int64, error) {
+	for i, m := range f.members {
+		if m.HouseholdID == arg.HouseholdID && m.UserID == arg.UserID {
+			f.members = append(f.members[:i], f.members[i+1:]...)
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (f *fakeHouseholdStore) CreateHouseholdInvite(_ context.Context, arg database.CreateHouseholdInviteParams) (database.HouseholdInvite, error) {
+	inv := database.HouseholdInvite{
+		ID: int32(len(f.invites) + 1), HouseholdID: arg.HouseholdID, TokenHash: arg.TokenHash,
+		Role: arg.Role, CreatedBy: arg.CreatedBy, ExpiresAt: arg.ExpiresAt,
+	}
+	f.invites = append(f.invites, inv)
+	return inv, nil
+}
+
+func (f *fakeHouseholdStore) ConsumeHouseholdInvite(_ context.Context, arg database.ConsumeHouseholdInviteParams) (database.HouseholdInvite, error) {
+	for i, inv := range f.invites {
+		if inv.TokenHash == arg.TokenHash && !inv.AcceptedAt.Valid && inv.ExpiresAt.After(time.Now()) {
+			f.invites[i].AcceptedBy = arg.AcceptedBy
+			f.invites[i].AcceptedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			return f.invites[i], nil
+		}
+	}
+	return database.HouseholdInvite{}, sql.ErrNoRows
+}
+
+func (f *fakeHouseholdStore) ListHouseholdCopies(_ context.Context, arg database.ListHouseholdCopiesParams) ([]database.ListHouseholdCopiesRow, error) {
+	var out []database.ListHouseholdCopiesRow
+	for _, c := range f.copies {
+		if c.HouseholdID != arg.HouseholdID {
+			continue
+		}
+		row := database.ListHouseholdCopiesRow{ID: c.ID, HouseholdID: c.HouseholdID, WorkID: c.WorkID, Title: c.Title, Format: c.Format, AddedBy: c.AddedBy}
+		if s, ok := f.statuses[c.WorkID]; ok {
+			row.ReadlistStatus = sql.NullString{String: s, Valid: true}
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+func (f *fakeHouseholdStore) GetHouseholdCopy(_ context.Context, arg database.GetHouseholdCopyParams) (database.HouseholdCopy, error) {
+	for _, c := range f.copies {
+		if c.ID == arg.ID && c.HouseholdID == arg.HouseholdID {
+			return c, nil
+		}
+	}
+	return database.HouseholdCopy{}, sql.ErrNoRows
+}
+
+func (f *fakeHouseholdStore) CreateHouseholdCopy(_ context.Context, arg database.CreateHouseholdCopyParams) (database.HouseholdCopy, error) {
+	c := database.HouseholdCopy{
+		ID: int32(len(f.copies) + 1), HouseholdID: arg.HouseholdID, WorkID: arg.WorkID, Title: arg.Title,
+		Authors: arg.Authors, Format: arg.Format, Condition: arg.Condition, Location: arg.Location, AddedBy: arg.AddedBy,
+	}
+	f.copies = append(f.copies, c)
+	return c, nil
+}
+
+func (f *fakeHouseholdStore) UpdateHouseholdCopy(_ context.Context, arg database.UpdateHouseholdCopyParams) (database.HouseholdCopy, error) {
+	for i, c := range f.copies {
+		if c.ID == arg.ID && c.HouseholdID == arg.HouseholdID {
+			f.copies[i].Format, f.copies[i].Condition, f.copies[i].Location = arg.Format, arg.Condition, arg.Location
+			return f.copies[i], nil
+		}
+	}
+	return database.HouseholdCopy{}, sql.ErrNoRows
+}
+
+func (f *fakeHouseholdStore) DeleteHouseholdCopy(_ context.Context, arg database.DeleteHouseholdCopyParams) (int64, error) {
+	for i, c := range f.copies {
+		if c.ID == arg.ID && c.HouseholdID == arg.HouseholdID {
+			f.copies = append(f.copies[:i], f.copies[i+1:]...)
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// seedHouseholds sets up household 1 with testSub in role, an owner, an admin and a
+// member, and household 2 that testSub does not belong to.
+func seedHouseholds(role string) *fakeHouseholdStore {
+	f := &fakeHouseholdStore{
+		households: []database.Household{{ID: 1, Name: "Home"}, {ID: 2, Name: "Neighbours"}},
+		members: []database.HouseholdMember{
+			{HouseholdID: 1, UserID: testSub, Role: role},
+			{HouseholdID: 1, UserID: "admin-sub", Role: roleAdmin},
+			{HouseholdID: 1, UserID: "member-sub", Role: roleMember},
+			{HouseholdID: 2, UserID: "neighbour-sub", Role: roleOwner},
+		},
+		copies: []database.HouseholdCopy{
+			{ID: 1, HouseholdID: 1, WorkID: "OL1W", Title: "Dune", Format: "paperback", AddedBy: "member-sub"},
+			{ID: 2, HouseholdID: 2, WorkID: "OL2W", Title: "Emma", Format: "hardcover", AddedBy: "neighbour-sub"},
+		},
+	}
+	if role != roleOwner {
+		f.members = append(f.members, database.HouseholdMember{HouseholdID: 1, UserID: "owner-sub", Role: roleOwner})
+	}
+	return f
+}
+
+func TestCreateHousehold_CallerBecomesOwner(t *testing.T) {
+	store := &fakeHouseholdStore{}
+	h := &HouseholdHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.CreateHousehold(w, webhookRequest(http.MethodPost, "/households", `{"name":"  Home  "}`, nil))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusCreated)
+	}
+	var got HouseholdResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if got.Name != "Home" || got.Role != roleOwner {
+		t.Errorf("got %+v", got)
+	}
+	if len(store.members) != 1 || store.members[0].UserID != testSub || store.members[0].Role != roleOwner {
+		t.Errorf("members: got %+v", store.members)
+	}
+}
+
+func TestCreateHousehold_Validation(t *testing.T) {
+	h := &HouseholdHandler{Queries: &fakeHouseholdStore{}}
+
+	w := httptest.NewRecorder()
+	h.CreateHousehold(w, webhookRequest(http.MethodPost, "/households", `{"name":" "}`, nil))
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestHousehold_NonMembersGetNotFound(t *testing.T) {
+	h := &HouseholdHandler{Queries: seedHouseholds(roleOwner)}
+	params := map[string]string{"id": "2", "copyID": "2"}
+
+	for name, serve := range map[string]http.HandlerFunc{
+		"get":         h.GetHousehold,
+		"list copies": h.ListCopies,
+		"add copy":    h.AddCopy,
+		"patch copy":  h.PatchCopy,
+		"invite":      h.CreateInvite,
+	} {
+		w := httptest.NewRecorder()
+		serve(w, webhookRequest(http.MethodGet, "/households/2", `{}`, params))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s: status: got %d, want %d", name, w.Code, http.StatusNotFound)
+		}
+	}
+}
+
+func TestHousehold_CopiesOfOtherHouseholdsAreNotFound(t *testing.T) {
+	h := &HouseholdHandler{Queries: seedHouseholds(roleOwner)}
+
+	w := httptest.NewRecorder()
+	h.DeleteCopy(w, webhookRequest(http.MethodDelete, "/households/1/copies/2", "", map[string]string{"id": "1", "copyID": "2"}))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestGetHousehold_IncludesMembers(t *testing.T) {
+	h := &HouseholdHandler{Queries: seedHouseholds(roleMember)}
+
+	w := httptest.NewRecorder()
+	h.GetHousehold(w, webhookRequest(http.MethodGet, "/households/1", "", map[string]string{"id": "1"}))
+
+	var got HouseholdResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if w.Code != http.StatusOK || got.Role != roleMember || len(got.Members) != 4 {
+		t.Errorf("got %d %+v", w.Code, got)
+	}
+}
+
+func TestListCopies_IncludesCallersReadlistStatus(t *testing.T) {
+	store := seedHouseholds(roleMember)
+	store.statuses = map[string]string{"OL1W": "reading"}
+	h := &HouseholdHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.ListCopies(w, webhookRequest(http.MethodGet, "/households/1/copies", "", map[string]string{"id": "1"}))
+
+	var got []HouseholdCopyResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if len(got) != 1 || got[0].Title != "Dune" || got[0].ReadlistStatus == nil || *got[0].ReadlistStatus != "reading" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestCreateInvite_RequiresAdmin(t *testing.T) {
+	for role, want := range map[string]int{roleMember: http.StatusForbidden, roleAdmin: http.StatusCreated, roleOwner: http.StatusCreated} {
+		store := seedHouseholds(role)
+		h := &HouseholdHandler{Queries: store}
+
+		w := httptest.NewRecorder()
+		h.CreateInvite(w, webhookRequest(http.MethodPost, "/households/1/invites", "", map[string]string{"id": "1"}))
+
+		if w.Code != want {
+			t.Errorf("%s: status: got %d, want %d", role, w.Code, want)
+		}
+		if want == http.StatusCreated {
+			var got HouseholdInviteResponse
+			json.NewDecoder(w.Body).Decode(&got)
+			if got.Role != roleMember || store.invites[0].TokenHash != hashToken(got.Token) {
+				t.Errorf("%s: invite %+v, stored %+v", role, got, store.invites[0])
+			}
+			if w.Header().Get("Cache-Control") != "no-store" {
+				t.Errorf("%s: Cache-Control: got %q, want no-store", role, w.Header().Get("Cache-Control"))
+			}
+		}
+	}
+}
+
+func TestJoinHousehold(t *testing.T) {
+	store := &fakeHouseholdStore{
+		households: []database.Household{{ID: 1, Name: "Home"}},
+		members:    []database.HouseholdMember{{HouseholdID: 1, UserID: "owner-sub", Role: roleOwner}},
+		invites: []database.HouseholdInvite{
+			{ID: 1, HouseholdID: 1, TokenHash: hashToken("good"), Role: roleAdmin, ExpiresAt: time.Now().Add(time.Hour)},
+			{ID: 2, HouseholdID: 1, TokenHash: hashToken("stale"), Role: roleMember, ExpiresAt: time.Now().Add(-time.Hour)},
+		},
+	}
+	h := &HouseholdHandler{Queries: store}
+	join := func(token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.JoinHousehold(w, webhookRequest(http.MethodPost, "/households/join", `{"token":"`+token+`"}`, nil))
+		return w
+	}
+
+	if w := join("stale"); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expired invite: status: got %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+	w := join("good")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusCreated)
+	}
+	var got HouseholdResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if got.ID != 1 || got.Role != roleAdmin {
+		t.Errorf("got %+v", got)
+	}
+	if w := join("good"); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("reused invite: status: got %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestUpdateMember_TransfersOwnership(t *testing.T) {
+	store := seedHouseholds(roleOwner)
+	h := &HouseholdHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.UpdateMember(w, webhookRequest(http.MethodPatch, "/households/1/members/member-sub", `{"role":"owner"}`,
+		map[string]string{"id": "1", "userID": "member-sub"}))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	roles := map[string]string{}
+	for _, m := range store.members {
+		if m.HouseholdID == 1 {
+			roles[m.UserID] = m.Role
+		}
+	}
+	if roles["member-sub"] != roleOwner || roles[testSub] != roleAdmin {
+		t.Errorf("roles: got %v", roles)
+	}
+}
+
+func TestUpdateMember_OnlyOwner(t *testing.T) {
+	h := &HouseholdHandler{Queries: seedHouseholds(roleAdmin)}
+
+	w := httptest.NewRecorder()
+	h.UpdateMember(w, webhookRequest(http.MethodPatch, "/households/1/members/member-sub", `{"role":"admin"}`,
+		map[string]string{"id": "1", "userID": "member-sub"}))
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	cases := []struct {
+		name   string
+		role   string
+		target string
+		want   int
+	}{
+		{"member leaves", roleMember, testSub, http.StatusNoContent},
+		{"owner cannot leave", roleOwner, testSub, http.StatusConflict},
+		{"admin removes member", roleAdmin, "member-sub", http.StatusNoContent},
+		{"admin cannot remove admin", roleAdmin, "admin-sub", http.StatusForbidden},
+		{"member cannot remove member", roleMember, "member-sub", http.StatusForbidden},
+		{"unknown member", roleOwner, "nobody", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := &HouseholdHandler{Queries: seedHouseholds(tc.role)}
+
+			w := httptest.NewRecorder()
+			h.RemoveMember(w, webhookRequest(http.MethodDelete, "/households/1/members/"+tc.target, "",
+				map[string]string{"id": "1", "userID": tc.target}))
+
+			if w.Code != tc.want {
+				t.Errorf("status: got %d, want %d", w.Code, tc.want)
+			}
+		})
+	}
+}
+
+func TestAddCopy(t *testing.T) {
+	store := seedHouseholds(roleMember)
+	h := &HouseholdHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.AddCopy(w, webhookRequest(http.MethodPost, "/households/1/copies",
+		`{"work_id":"OL3W","title":"Persuasion","authors":"Jane Austen","format":"ebook","location":"Kobo"}`,
+		map[string]string{"id": "1"}))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusCreated)
+	}
+	added := store.copies[len(store.copies)-1]
+	if added.HouseholdID != 1 || added.AddedBy != testSub || added.Location.String != "Kobo" {
+		t.Errorf("got %+v", added)
+	}
+}
+
+func TestAddCopy_Validation(t *testing.T) {
+	h := &HouseholdHandler{Queries: seedHouseholds(roleMember)}
+
+	w := httptest.NewRecorder()
+	h.AddCopy(w, webhookRequest(http.MethodPost, "/households/1/copies",
+		`{"work_id":"OL3W","title":"Persuasion","format":"scroll","condition":"mint"}`, map[string]string{"id": "1"}))
+
+	if fields := responseFields(t, w); w.Code != http.StatusUnprocessableEntity || len(fields) != 3 {
+		t.Errorf("got %d %v", w.Code, fields)
+	}
+}
+
+func TestPatchCopy_Permissions(t *testing.T) {
+	cases := []struct {
+		name string
+		role string
+		sub  string
+		want int
+	}{
+		{"member who added it", roleMember, "member-sub", http.StatusOK},
+		{"admin", roleAdmin, testSub, http.StatusOK},
+		{"other member", roleMember, testSub, http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := seedHouseholds(tc.role)
+			h := &HouseholdHandler{Queries: store}
+
+			r := webhookRequest(http.MethodPatch, "/households/1/copies/1", `{"condition":"fair","location":null}`,
+				map[string]string{"id": "1", "copyID": "1"})
+			w := httptest.NewRecorder()
+			h.PatchCopy(w, withSub(r, tc.sub))
+
+			if w.Code != tc.want {
+				t.Fatalf("status: got %d, want %d", w.Code, tc.want)
+			}
+			if tc.want == http.StatusOK && store.copies[0].Condition.String != "fair" {
+				t.Errorf("copy: got %+v", store.copies[0])
+			}
+		})
+	}
+}
+
+func TestPatchCopy_MediaType(t *testing.T) {
+	h := &HouseholdHandler{Queries: seedHouseholds(roleAdmin)}
+
+	r := webhookRequest(http.MethodPatch, "/households/1/copies/1", `condition=fair`,
+		map[string]string{"id": "1", "copyID": "1"})
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	h.PatchCopy(w, r)
+
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusUnsupportedMediaType)
+	}
+}
+
+func TestDeleteHousehold_OnlyOwner(t *testing.T) {
+	for role, want := range map[string]int{roleAdmin: http.StatusForbidden, roleOwner: http.StatusNoContent} {
+		h := &HouseholdHandler{Queries: seedHouseholds(role)}
+
+		w := httptest.NewRecorder()
+		h.DeleteHousehold(w, webhookRequest(http.MethodDelete, "/households/1", "", map[string]string{"id": "1"}))
+
+		if w.Code != want {
+			t.Errorf("%s: status: got %d, want %d", role, w.Code, want)
+		}
+	}
+}
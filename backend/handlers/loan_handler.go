@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/problem"
+	"github.com/dcrespo1/book-list-app/users"
+	"github.com/go-chi/chi/v5"
+	"github.com/lib/pq"
+)
+
+const maxBorrowerNameLength = 100
+
+// loanStatuses are the values GET /loans accepts for status.
+var loanStatuses = []string{"outstanding", "overdue", "returned", "all"}
+
+// LoanStore is the persistence interface for loans. *database.Queries satisfies it.
+type LoanStore interface {
+	GetBookByID(ctx context.Context, arg database.GetBookByIDParams) (database.Book, error)
+	GetUser(ctx context.Context, userID string) (database.User, error)
+	CreateLoan(ctx context.Context, arg database.CreateLoanParams) (database.Loan, error)
+	GetLoan(ctx context.Context, arg database.GetLoanParams) (database.Loan, error)
+	ReturnLoan(ctx context.Context, arg database.ReturnLoanParams) (database.Loan, error)
+	ListLoans(ctx context.Context, arg database.ListLoansParams) ([]database.ListLoansRow, error)
+}
+
+// LoanHandler tracks who borrowed the caller's copies. Dates are calendar dates
+// (YYYY-MM-DD) in the caller's time zone preference.
+type LoanHandler struct {
+	Queries LoanStore
+}
+
+type LoanResponse struct {
+	ID             int32   `json:"id"`
+	BookID         int32   `json:"book_id"`
+	WorkID         string  `json:"work_id"`
+	Title          string  `json:"title"`
+	BorrowerName   *string `json:"borrower_name"`
+	BorrowerUserID *string `json:"borrower_user_id"`
+	LentOn         string  `json:"lent_on"`
+	DueOn          *string `json:"due_on"`
+	ReturnedOn     *string `json:"returned_on"`
+	Notes          *string `json:"notes"`
+	// Overdue is true for an outstanding loan whose due date has passed.
+	Overdue bool `json:"overdue"`
+}
+
+func toLoanResponse(l database.Loan, workID, title string, today time.Time) LoanResponse {
+	r := LoanResponse{
+		ID:      l.ID,
+		BookID:  l.BookID,
+		WorkID:  workID,
+		Title:   title,
+		LentOn:  l.LentOn.Format(time.DateOnly),
+		Overdue: !l.ReturnedOn.Valid && l.DueOn.Valid && l.DueOn.Time.Before(today),
+	}
+	if l.BorrowerName.Valid {
+		r.BorrowerName = &l.BorrowerName.String
+	}
+	if l.BorrowerUserID.Valid {
+		r.BorrowerUserID = &l.BorrowerUserID.String
+	}
+	if l.DueOn.Valid {
+		due := l.DueOn.Time.Format(time.DateOnly)
+		r.DueOn = &due
+	}
+	if l.ReturnedOn.Valid {
+		returned := l.ReturnedOn.Time.Format(time.DateOnly)
+		r.ReturnedOn = &returned
+	}
+	if l.Notes.Valid {
+		r.Notes = &l.Notes.String
+	}
+	return r
+}
+
+// LendBook records that the readlist entry {id} has been lent out. The borrower is
+// given by name, as a linked user, or both.
+func (h *LoanHandler) LendBook(w http.ResponseWriter, r *http.Request) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidParameter, "invalid id")
+		return
+	}
+
+	var input struct {
+		BorrowerName   *string `json:"borrower_name"`
+		BorrowerUserID *string `json:"borrower_user_id"`
+		LentOn         *string `json:"lent_on"`
+		DueOn          *string `json:"due_on"`
+		Notes          *string `json:"notes" maxlen:"1000"`
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	today := callerToday(r.Context())
+	errs := map[string]string{}
+	if input.BorrowerName == nil && input.BorrowerUserID == nil {
+		errs["borrower_name"] = "borrower_name or borrower_user_id is required"
+	}
+	if input.BorrowerName != nil && (*input.BorrowerName == "" || utf8.RuneCountInString(*input.BorrowerName) > maxBorrowerNameLength) {
+		errs["borrower_name"] = "must be 1 to " + strconv.Itoa(maxBorrowerNameLength) + " characters"
+	}
+	if input.BorrowerUserID != nil && *input.BorrowerUserID == sub {
+		errs["borrower_user_id"] = "cannot lend to yourself"
+	}
+	lentOn := sql.NullTime{Time: today, Valid: true}
+	if input.LentOn != nil {
+		lentOn = parseDate(errs, "lent_on", input.LentOn)
+	}
+	dueOn := parseDate(errs, "due_on", input.DueOn)
+	if dueOn.Valid && lentOn.Valid && dueOn.Time.Before(lentOn.Time) {
+		errs["due_on"] = "must not be before lent_on"
+	}
+	if len(errs) > 0 {
+		problem.WriteInvalid(w, r, errs)
+		return
+	}
+
+	if input.BorrowerUserID != nil {
+		_, err := h.Queries.GetUser(r.Context(), *input.BorrowerUserID)
+		if errors.Is(err, sql.ErrNoRows) {
+			problem.WriteInvalid(w, r, map[string]string{"borrower_user_id": "unknown user"})
+			return
+		}
+		if err != nil {
+			problem.Write(w, r, problem.Internal, "failed to look up borrower")
+			return
+		}
+	}
+
+	book, err := h.Queries.GetBookByID(r.Context(), database.GetBookByIDParams{ID: int32(id), UserID: sub})
+	if errors.Is(err, sql.ErrNoRows) {
+		problem.Write(w, r, problem.NotFound, "book not found")
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to retrieve book")
+		return
+	}
+
+	loan, err := h.Queries.CreateLoan(r.Context(), database.CreateLoanParams{
+		BookID:         book.ID,
+		UserID:         sub,
+		BorrowerName:   toNullString(input.BorrowerName),
+		BorrowerUserID: toNullString(input.BorrowerUserID),
+		LentOn:         lentOn.Time,
+		DueOn:          dueOn,
+		Notes:          toNullString(input.Notes),
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			problem.Write(w, r, problem.BookLentOut, "book is already lent out")
+			return
+		}
+		problem.Write(w, r, problem.Internal, "failed to record loan")
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, toLoanResponse(loan, book.WorkID, book.Title, today))
+}
+
+// ReturnLoan marks loan {id} as returned, today unless the body gives returned_on.
+func (h *LoanHandler) ReturnLoan(w http.ResponseWriter, r *http.Request) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidParameter, "invalid id")
+		return
+	}
+
+	var input struct {
+		ReturnedOn *string `json:"returned_on"`
+	}
+	if !decodeOptionalJSON(w, r, &input) {
+		return
+	}
+
+	loan, err := h.Queries.GetLoan(r.Context(), database.GetLoanParams{ID: int32(id), UserID: sub})
+	if errors.Is(err, sql.ErrNoRows) {
+		problem.Write(w, r, problem.NotFound, "loan not found")
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to retrieve loan")
+		return
+	}
+	if loan.ReturnedOn.Valid {
+		problem.Write(w, r, problem.LoanReturned, "loan already returned")
+		return
+	}
+
+	today := callerToday(r.Context())
+	errs := map[string]string{}
+	returnedOn := sql.NullTime{Time: today, Valid: true}
+	if input.ReturnedOn != nil {
+		returnedOn = parseDate(errs, "returned_on", input.ReturnedOn)
+	}
+	if returnedOn.Valid && returnedOn.Time.Before(loan.LentOn) {
+		errs["returned_on"] = "must not be before lent_on"
+	}
+	if len(errs) > 0 {
+		problem.WriteInvalid(w, r, errs)
+		return
+	}
+
+	returned, err := h.Queries.ReturnLoan(r.Context(), database.ReturnLoanParams{
+		ID:         loan.ID,
+		UserID:     sub,
+		ReturnedOn: returnedOn,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// Returned between the lookup and the update.
+		problem.Write(w, r, problem.LoanReturned, "loan already returned")
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to return loan")
+		return
+	}
+
+	book, err := h.Queries.GetBookByID(r.Context(), database.GetBookByIDParams{ID: returned.BookID, UserID: sub})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		problem.Write(w, r, problem.Internal, "failed to retrieve book")
+		return
+	}
+	WriteJSON(w, http.StatusOK, toLoanResponse(returned, book.WorkID, book.Title, today))
+}
+
+// ListLoans returns the caller's loans, newest first, filtered by the status query
+// parameter: outstanding (the default), overdue, returned or all.
+func (h *LoanHandler) ListLoans(w http.ResponseWriter, r *http.Request) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = "outstanding"
+	}
+	if !slices.Contains(loanStatuses, status) {
+		problem.Write(w, r, problem.InvalidParameter, "status must be one of outstanding, overdue, returned, all")
+		return
+	}
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidParameter, err.Error())
+		return
+	}
+
+	today := callerToday(r.Context())
+	loans, err := h.Queries.ListLoans(r.Context(), database.ListLoansParams{
+		UserID:     sub,
+		Status:     status,
+		Today:      today,
+		PageLimit:  limit,
+		PageOffset: offset,
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to retrieve loans")
+		return
+	}
+
+	out := make([]LoanResponse, len(loans))
+	for i, l := range loans {
+		out[i] = toLoanResponse(database.Loan{
+			ID:             l.ID,
+			BookID:         l.BookID,
+			UserID:         l.UserID,
+			BorrowerName:   l.BorrowerName,
+			BorrowerUserID: l.BorrowerUserID,
+			LentOn:         l.LentOn,
+			DueOn:          l.DueOn,
+			ReturnedOn:     l.ReturnedOn,
+			Notes:          l.Notes,
+		}, l.WorkID, l.Title, today)
+	}
+	WriteJSON(w, http.StatusOK, out)
+}
+
+// callerToday is the current date in the caller's time zone preference, as midnight UTC
+// so it compares with the dates the database returns.
+func callerToday(ctx context.Context) time.Time {
+	loc := time.UTC
+	if prefs, ok := users.FromContext(ctx); ok {
+		if l, err := time.LoadLocation(prefs.Timezone); err == nil {
+			loc = l
+		}
+	}
+	y, m, d := time.Now().In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// parseDate parses an optional YYYY-MM-DD field, recording a problem in errs.
+func parseDate(errs map[string]string, field string, v *string) sql.NullTime {
+	if v == nil {
+		return sql.NullTime{}
+	}
+	t, err := time.Parse(time.DateOnly, *v)
+	if err != nil {
+		errs[field] = "must be a date (YYYY-MM-DD)"
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t, Valid: true}
+}
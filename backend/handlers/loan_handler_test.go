@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/lib/pq"
+)
+
+// fakeLoanStore is an in-memory LoanStore holding one book, 7, for testSub.
+type fakeLoanStore struct {
+	loans    []database.Loan
+	listArgs database.ListLoansParams
+}
+
+func (f *fakeLoanStore) GetBookByID(_ context.Context, arg database.GetBookByIDParams) (database.Book, error) {
+	if arg.ID != 7 || arg.UserID != testSub {
+		return database.Book{}, sql.ErrNoRows
+	}
+	return database.Book{ID: 7, UserID: testSub, WorkID: "OL1W", Title: "Dune"}, nil
+}
+
+func (f *fakeLoanStore) GetUser(_ context.Context, userID string) (database.User, error) {
+	if userID != "friend-sub" {
+		return database.User{}, sql.ErrNoRows
+	}
+	return database.User{UserID: userID}, nil
+}
+
+func (f *fakeLoanStore) CreateLoan(_ context.Context, arg database.CreateLoanParams) (database.Loan, error) {
+	for _, l := range f.loans {
+		if l.BookID == arg.BookID && !l.ReturnedOn.Valid {
+			return database.Loan{}, &pq.Error{Code: "23505"}
+		}
+	}
+	l := database.Loan{
+		ID:             int32(len(f.loans) + 1),
+		BookID:         arg.BookID,
+		UserID:         arg.UserID,
+		BorrowerName:   arg.BorrowerName,
+		BorrowerUserID: arg.BorrowerUserID,
+		LentOn:         arg.LentOn,
+		DueOn:          arg.DueOn,
+		Notes:          arg.Notes,
+	}
+	f.loans = append(f.loans, l)
+	return l, nil
+}
+
+func (f *fakeLoanStore) GetLoan(_ context.Context, arg database.GetLoanParams) (database.Loan, error) {
+	for _, l := range f.loans {
+		if l.ID == arg.ID && l.UserID == arg.UserID {
+			return l, nil
+		}
+	}
+	return database.Loan{}, sql.ErrNoRows
+}
+
+func (f *fakeLoanStore) ReturnLoan(_ context.Context, arg database.ReturnLoanParams) (database.Loan, error) {
+	for i, l := range f.loans {
+		if l.ID == arg.ID && l.UserID == arg.UserID && !l.ReturnedOn.Valid {
+			f.loans[i].ReturnedOn = arg.ReturnedOn
+			return f.loans[i], nil
+		}
+	}
+	return database.Loan{}, sql.ErrNoRows
+}
+
+func (f *fakeLoanStore) ListLoans(_ context.Context, arg database.ListLoansParams) ([]database.ListLoansRow, error) {
+	f.listArgs = arg
+	var out []database.ListLoansRow
+	for _, l := range f.loans {
+		out = append(out, database.ListLoansRow{
+			ID:           l.ID,
+			BookID:       l.BookID,
+			UserID:       l.UserID,
+			BorrowerName: l.BorrowerName,
+			LentOn:       l.LentOn,
+			DueOn:        l.DueOn,
+			ReturnedOn:   l.ReturnedOn,
+			WorkID:       "OL1W",
+			Title:        "Dune",
+		})
+	}
+	return out, nil
+}
+
+func loanDate(s string) time.Time {
+	t, _ := time.Parse(time.DateOnly, s)
+	return t
+}
+
+func TestLendBook_Success(t *testing.T) {
+	store := &fakeLoanStore{}
+	h := &LoanHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.LendBook(w, webhookRequest(http.MethodPost, "/readlist/7/loans",
+		`{"borrower_name":"Sam","lent_on":"2026-01-01","due_on":"2026-02-01"}`, map[string]string{"id": "7"}))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusCreated)
+	}
+	var got LoanResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if got.Title != "Dune" || got.LentOn != "2026-01-01" || got.DueOn == nil || *got.DueOn != "2026-02-01" {
+		t.Errorf("got %+v", got)
+	}
+	if !got.Overdue {
+		t.Error("expected a loan due in the past to be overdue")
+	}
+}
+
+func TestLendBook_DefaultsLentOnToToday(t *testing.T) {
+	store := &fakeLoanStore{}
+	h := &LoanHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.LendBook(w, webhookRequest(http.MethodPost, "/readlist/7/loans",
+		`{"borrower_user_id":"friend-sub"}`, map[string]string{"id": "7"}))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusCreated)
+	}
+	today := time.Now().UTC().Format(time.DateOnly)
+	if got := store.loans[0].LentOn.Format(time.DateOnly); got != today {
+		t.Errorf("lent_on: got %s, want %s", got, today)
+	}
+}
+
+func TestLendBook_Validation(t *testing.T) {
+	h := &LoanHandler{Queries: &fakeLoanStore{}}
+
+	for name, body := range map[string]string{
+		"no borrower":     `{}`,
+		"lend to self":    `{"borrower_user_id":"` + testSub + `"}`,
+		"unknown user":    `{"borrower_user_id":"stranger"}`,
+		"bad date":        `{"borrower_name":"Sam","due_on":"next week"}`,
+		"due before lent": `{"borrower_name":"Sam","lent_on":"2026-02-01","due_on":"2026-01-01"}`,
+		"name too long":   `{"borrower_name":"` + strings.Repeat("é", maxBorrowerNameLength+1) + `"}`,
+	} {
+		w := httptest.NewRecorder()
+		h.LendBook(w, webhookRequest(http.MethodPost, "/readlist/7/loans", body, map[string]string{"id": "7"}))
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: status: got %d, want %d", name, w.Code, http.StatusUnprocessableEntity)
+		}
+	}
+}
+
+func TestLendBook_NameLengthCountsCharacters(t *testing.T) {
+	h := &LoanHandler{Queries: &fakeLoanStore{}}
+
+	// Two bytes per character: over the limit in bytes, within it in characters.
+	name := strings.Repeat("é", maxBorrowerNameLength)
+	w := httptest.NewRecorder()
+	h.LendBook(w, webhookRequest(http.MethodPost, "/readlist/7/loans",
+		`{"borrower_name":"`+name+`"}`, map[string]string{"id": "7"}))
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("status: got %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+}
+
+func TestLendBook_NotFound(t *testing.T) {
+	h := &LoanHandler{Queries: &fakeLoanStore{}}
+
+	w := httptest.NewRecorder()
+	h.LendBook(w, webhookRequest(http.MethodPost, "/readlist/8/loans", `{"borrower_name":"Sam"}`, map[string]string{"id": "8"}))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestLendBook_AlreadyLentOut(t *testing.T) {
+	store := &fakeLoanStore{loans: []database.Loan{{ID: 1, BookID: 7, UserID: testSub, LentOn: loanDate("2026-01-01")}}}
+	h := &LoanHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.LendBook(w, webhookRequest(http.MethodPost, "/readlist/7/loans", `{"borrower_name":"Sam"}`, map[string]string{"id": "7"}))
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusConflict)
+	}
+}
+
+func TestReturnLoan_Success(t *testing.T) {
+	store := &fakeLoanStore{loans: []database.Loan{{ID: 1, BookID: 7, UserID: testSub, LentOn: loanDate("2026-01-01")}}}
+	h := &LoanHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.ReturnLoan(w, webhookRequest(http.MethodPost, "/loans/1/return", `{"returned_on":"2026-01-15"}`, map[string]string{"id": "1"}))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	var got LoanResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if got.ReturnedOn == nil || *got.ReturnedOn != "2026-01-15" || got.Overdue {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestReturnLoan_EmptyBodyDefaultsToToday(t *testing.T) {
+	store := &fakeLoanStore{loans: []database.Loan{{ID: 1, BookID: 7, UserID: testSub, LentOn: loanDate("2026-01-01")}}}
+	h := &LoanHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.ReturnLoan(w, webhookRequest(http.MethodPost, "/loans/1/return", "", map[string]string{"id": "1"}))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	if !store.loans[0].ReturnedOn.Valid {
+		t.Error("expected the loan to be returned")
+	}
+}
+
+func TestReturnLoan_AlreadyReturned(t *testing.T) {
+	store := &fakeLoanStore{loans: []database.Loan{{
+		ID: 1, BookID: 7, UserID: testSub, LentOn: loanDate("2026-01-01"),
+		ReturnedOn: sql.NullTime{Time: loanDate("2026-01-10"), Valid: true},
+	}}}
+	h := &LoanHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.ReturnLoan(w, webhookRequest(http.MethodPost, "/loans/1/return", `{}`, map[string]string{"id": "1"}))
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusConflict)
+	}
+}
+
+func TestReturnLoan_OtherUsersLoanNotFound(t *testing.T) {
+	store := &fakeLoanStore{loans: []database.Loan{{ID: 1, BookID: 9, UserID: "someone-else", LentOn: loanDate("2026-01-01")}}}
+	h := &LoanHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.ReturnLoan(w, webhookRequest(http.MethodPost, "/loans/1/return", `{}`, map[string]string{"id": "1"}))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestListLoans_DefaultsToOutstanding(t *testing.T) {
+	store := &fakeLoanStore{}
+	h := &LoanHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.ListLoans(w, webhookRequest(http.MethodGet, "/loans", "", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	if store.listArgs.Status != "outstanding" {
+		t.Errorf("status filter: got %q, want outstanding", store.listArgs.Status)
+	}
+}
+
+func TestListLoans_InvalidStatus(t *testing.T) {
+	h := &LoanHandler{Queries: &fakeLoanStore{}}
+
+	w := httptest.NewRecorder()
+	h.ListLoans(w, webhookRequest(http.MethodGet, "/loans?status=lost", "", nil))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
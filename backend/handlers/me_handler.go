@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/problem"
+	"github.com/dcrespo1/book-list-app/users"
+)
+
+// languageCode matches an ISO 639-1 code, the form Open Library takes for lang.
+var languageCode = regexp.MustCompile(`^[a-z]{2}$`)
+
+// deletionConfirmationTTL is how long the token returned by the first DELETE /me
+// can be used to confirm the deletion.
+const deletionConfirmationTTL = 15 * time.Minute
+
+// MeStore is the persistence interface for the caller's profile and account.
+// *database.Queries satisfies it.
+type MeStore interface {
+	users.ExportStore
+	UpdateUserPreferences(ctx context.Context, arg database.UpdateUserPreferencesParams) (database.User, error)
+	SetAccountDeletionToken(ctx context.Context, arg database.SetAccountDeletionTokenParams) error
+	ScheduleAccountDeletion(ctx context.Context, arg database.ScheduleAccountDeletionParams) (database.User, error)
+	CancelAccountDeletion(ctx context.Context, userID string) (int64, error)
+}
+
+// MeHandler serves the caller's profile, preferences and account. The user row
+// itself is loaded by users.Middleware.
+type MeHandler struct {
+	Queries MeStore
+	// DeletionGrace is how long a confirmed account deletion can still be cancelled
+	// before everything is purged.
+	DeletionGrace time.Duration
+}
+
+type PreferencesResponse struct {
+	DefaultSort   string  `json:"default_sort"`
+	Privacy       string  `json:"privacy"`
+	DefaultStatus string  `json:"default_status"`
+	Timezone      string  `json:"timezone"`
+	Language      *string `json:"language"`
+}
+
+type MeResponse struct {
+	ID          string              `json:"id"`
+	Username    *string             `json:"username"`
+	Email       *string             `json:"email"`
+	Name        *string             `json:"name"`
+	Preferences PreferencesResponse `json:"preferences"`
+	CreatedAt   time.Time           `json:"created_at"`
+	// DeletionScheduledFor is set while the account is waiting to be purged.
+	DeletionScheduledFor *time.Time `json:"deletion_scheduled_for,omitempty"`
+}
+
+func toMeResponse(u database.User) MeResponse {
+	r := MeResponse{
+		ID: u.UserID,
+		Preferences: PreferencesResponse{
+			DefaultSort:   u.DefaultSort,
+			Privacy:       u.Privacy,
+			DefaultStatus: u.DefaultStatus,
+			Timezone:      u.Timezone,
+		},
+		CreatedAt: u.CreatedAt,
+	}
+	if u.Username.Valid {
+		r.Username = &u.Username.String
+	}
+	if u.Email.Valid {
+		r.Email = &u.Email.String
+	}
+	if u.Name.Valid {
+		r.Name = &u.Name.String
+	}
+	if u.Language.Valid {
+		r.Preferences.Language = &u.Language.String
+	}
+	if u.DeletionScheduledFor.Valid {
+		r.DeletionScheduledFor = &u.DeletionScheduledFor.Time
+	}
+	return r
+}
+
+func (h *MeHandler) GetMe(w http.ResponseWriter, r *http.Request) {
+	user, ok := users.FromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+	WriteJSON(w, http.StatusOK, toMeResponse(user))
+}
+
+// PatchMe updates preferences with a JSON merge patch of the preferences object.
+// language may be null to fall back to Open Library's default; the other
+// preferences always have a value.
+func (h *MeHandler) PatchMe(w http.ResponseWriter, r *http.Request) {
+	user, ok := users.FromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+
+	body, ok := readMergePatch(w, r)
+	if !ok {
+		return
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(body, &doc); err != nil || doc == nil {
+		problem.Write(w, r, problem.InvalidBody, "merge patch must be a JSON object")
+		return
+	}
+
+	arg := database.UpdateUserPreferencesParams{
+		UserID:        user.UserID,
+		DefaultSort:   user.DefaultSort,
+		Privacy:       user.Privacy,
+		DefaultStatus: user.DefaultStatus,
+		Timezone:      user.Timezone,
+		Language:      user.Language,
+	}
+	errs := map[string]string{}
+	for field, raw := range doc {
+		if field == "language" && string(raw) == "null" {
+			arg.Language = sql.NullString{}
+			continue
+		}
+		var v string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			errs[field] = "must be a string"
+			continue
+		}
+		switch field {
+		case "default_sort":
+			if _, ok := readlistSorts[v]; !ok {
+				errs[field] = "must be one of " + strings.Join(slices.Sorted(maps.Keys(readlistSorts)), ", ")
+			}
+			arg.DefaultSort = v
+		case "privacy":
+			if v != "private" && v != "public" {
+				errs[field] = "must be private or public"
+			}
+			arg.Privacy = v
+		case "default_status":
+			if !validStatus(v) {
+				errs[field] = "must be one of want_to_read, reading, finished, abandoned"
+			}
+			arg.DefaultStatus = v
+		case "timezone":
+			if _, err := time.LoadLocation(v); err != nil || v == "" || v == "Local" {
+				errs[field] = "must be an IANA time zone name"
+			}
+			arg.Timezone = v
+		case "language":
+			if !languageCode.MatchString(v) {
+				errs[field] = "must be a two-letter ISO 639-1 code"
+			}
+			arg.Language = sql.NullString{String: v, Valid: true}
+		default:
+			errs[field] = "unknown preference"
+		}
+	}
+	if len(errs) > 0 {
+		problem.WriteInvalid(w, r, errs)
+		return
+	}
+
+	updated, err := h.Queries.UpdateUserPreferences(r.Context(), arg)
+	if errors.Is(err, sql.ErrNoRows) {
+		problem.Write(w, r, problem.NotFound, "user not found")
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to update preferences")
+		return
+	}
+	WriteJSON(w, http.StatusOK, toMeResponse(updated))
+}
+
+// ExportMe returns a ZIP archive of everything stored for the caller, as JSON and CSV.
+func (h *MeHandler) ExportMe(w http.ResponseWriter, r *http.Request) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+
+	// Buffered so a failure part-way through still gets an error response.
+	var buf bytes.Buffer
+	if err := users.Export(r.Context(), h.Queries, sub, &buf); err != nil {
+		slog.Error("failed to export account", "error", err)
+		problem.Write(w, r, problem.Internal, "failed to export account")
+		return
+	}
+
+	filename := "booklist-export-" + time.Now().UTC().Format("2006-01-02") + ".zip"
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// DeleteMe deletes the caller's account in two steps. Without a body it returns a
+// confirmation token; repeating the request with {"confirmation_token": ...} within
+// deletionConfirmationTTL schedules the purge of everything stored for the caller
+// once DeletionGrace has passed. Until then, CancelDeletion undoes it.
+func (h *MeHandler) DeleteMe(w http.ResponseWriter, r *http.Request) {
+	user, ok := users.FromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+	if user.DeletionScheduledFor.Valid {
+		problem.Write(w, r, problem.DeletionScheduled, "account deletion is already scheduled")
+		return
+	}
+
+	var input struct {
+		ConfirmationToken string `json:"confirmation_token"`
+	}
+	if !decodeOptionalJSON(w, r, &input) {
+		return
+	}
+
+	if input.ConfirmationToken == "" {
+		h.requestDeletion(w, r, user)
+		return
+	}
+
+	hash := hashToken(input.ConfirmationToken)
+	if !user.DeletionTokenHash.Valid ||
+		subtle.ConstantTimeCompare([]byte(hash), []byte(user.DeletionTokenHash.String)) != 1 ||
+		!user.DeletionTokenExpiresAt.Time.After(time.Now()) {
+		problem.Write(w, r, problem.InvalidConfirmation, "invalid or expired confirmation token")
+		return
+	}
+
+	scheduled, err := h.Queries.ScheduleAccountDeletion(r.Context(), database.ScheduleAccountDeletionParams{
+		UserID:               user.UserID,
+		DeletionScheduledFor: sql.NullTime{Time: time.Now().Add(h.DeletionGrace), Valid: true},
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to schedule account deletion")
+		return
+	}
+	WriteJSON(w, http.StatusAccepted, toMeResponse(scheduled))
+}
+
+// requestDeletion issues the confirmation token for DeleteMe. Only its hash is stored.
+func (h *MeHandler) requestDeletion(w http.ResponseWriter, r *http.Request, user database.User) {
+	token, hash, err := newSecretToken()
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to generate confirmation token")
+		return
+	}
+	expiresAt := time.Now().Add(deletionConfirmationTTL)
+
+	err = h.Queries.SetAccountDeletionToken(r.Context(), database.SetAccountDeletionTokenParams{
+		UserID:                 user.UserID,
+		DeletionTokenHash:      sql.NullString{String: hash, Valid: true},
+		DeletionTokenExpiresAt: sql.NullTime{Time: expiresAt, Valid: true},
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to request account deletion")
+		return
+	}
+	// Only the hash is kept; the plaintext token must not outlive this response.
+	w.Header().Set("Cache-Control", "no-store")
+	WriteJSON(w, http.StatusAccepted, map[string]any{
+		"confirmation_token": token,
+		"expires_at":         expiresAt.UTC(),
+		"grace_period":       h.DeletionGrace.String(),
+	})
+}
+
+// CancelDeletion cancels a scheduled account deletion during its grace period.
+func (h *MeHandler) CancelDeletion(w http.ResponseWriter, r *http.Request) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+
+	n, err := h.Queries.CancelAccountDeletion(r.Context(), sub)
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to cancel account deletion")
+		return
+	}
+	if n == 0 {
+		problem.Write(w, r, problem.NotFound, "no account deletion is scheduled")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,381 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/users"
+)
+
+// fakeMeStore is a test double for MeStore.
+type fakeMeStore struct {
+	updateArg   database.UpdateUserPreferencesParams
+	tokenArg    database.SetAccountDeletionTokenParams
+	scheduleArg database.ScheduleAccountDeletionParams
+	books       []database.Book
+	webhooks    []database.WebhookSubscription
+	cancelled   int64
+}
+
+func (f *fakeMeStore) GetUser(_ context.Context, _ string) (database.User, error) {
+	return testUser(), nil
+}
+
+func (f *fakeMeStore) ExportBooks(_ context.Context, _ string) ([]database.Book, error) {
+	return f.books, nil
+}
+
+func (f *fakeMeStore) ExportComments(_ context.Context, _ string) ([]database.Comment, error) {
+	return nil, nil
+}
+
+func (f *fakeMeStore) ListWebhookSubscriptions(_ context.Context, _ string) ([]database.WebhookSubscription, error) {
+	return f.webhooks, nil
+}
+
+func (f *fakeMeStore) ExportWebhookDeliveries(_ context.Context, _ string) ([]database.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (f *fakeMeStore) ExportReadlistEvents(_ context.Context, _ string) ([]database.ReadlistEvent, error) {
+	return nil, nil
+}
+
+func (f *fakeMeStore) ExportIdempotencyKeys(_ context.Context, _ string) ([]database.IdempotencyKey, error) {
+	return nil, nil
+}
+
+func (f *fakeMeStore) ExportPersonalAccessTokens(_ context.Context, _ string) ([]database.PersonalAccessToken, error) {
+	return nil, nil
+}
+
+func (f *fakeMeStore) ExportHouseholdMemberships(_ context.Context, _ string) ([]database.HouseholdMember, error) {
+	return nil, nil
+}
+
+func (f *fakeMeStore) ExportHouseholdCopies(_ context.Context, _ string) ([]database.HouseholdCopy, error) {
+	return nil, nil
+}
+
+func (f *fakeMeStore) ExportHouseholdInvites(_ context.Context, _ string) ([]database.HouseholdInvite, error) {
+	return nil, nil
+}
+
+func (f *fakeMeStore) ExportLoans(_ context.Context, _ string) ([]database.Loan, error) {
+	return nil, nil
+}
+
+func (f *fakeMeStore) ExportOwnedFormats(_ context.Context, _ string) ([]database.OwnedFormat, error) {
+	return nil, nil
+}
+
+func (f *fakeMeStore) SetAccountDeletionToken(_ context.Context, arg database.SetAccountDeletionTokenParams) error {
+	f.tokenArg = arg
+	return nil
+}
+
+func (f *fakeMeStore) ScheduleAccountDeletion(_ context.Context, arg database.ScheduleAccountDeletionParams) (database.User, error) {
+	f.scheduleArg = arg
+	u := testUser()
+	u.DeletionScheduledFor = arg.DeletionScheduledFor
+	return u, nil
+}
+
+func (f *fakeMeStore) CancelAccountDeletion(_ context.Context, _ string) (int64, error) {
+	return f.cancelled, nil
+}
+
+func (f *fakeMeStore) UpdateUserPreferences(_ context.Context, arg database.UpdateUserPreferencesParams) (database.User, error) {
+	f.updateArg = arg
+	return database.User{
+		UserID:        arg.UserID,
+		DefaultSort:   arg.DefaultSort,
+		Privacy:       arg.Privacy,
+		DefaultStatus: arg.DefaultStatus,
+		Timezone:      arg.Timezone,
+		Language:      arg.Language,
+	}, nil
+}
+
+func testUser() database.User {
+	return database.User{
+		UserID:        testSub,
+		Username:      sql.NullString{String: "alice", Valid: true},
+		DefaultSort:   "added_desc",
+		Privacy:       "private",
+		DefaultStatus: "want_to_read",
+		Timezone:      "UTC",
+		Language:      sql.NullString{String: "en", Valid: true},
+	}
+}
+
+func meRequest(method, body string) *http.Request {
+	return meRequestAs(testUser(), method, body)
+}
+
+func meRequestAs(u database.User, method, body string) *http.Request {
+	r := withSub(httptest.NewRequest(method, "/me", strings.NewReader(body)), u.UserID)
+	return r.WithContext(users.ToContext(r.Context(), u))
+}
+
+func TestGetMe(t *testing.T) {
+	h := &MeHandler{Queries: &fakeMeStore{}}
+
+	w := httptest.NewRecorder()
+	h.GetMe(w, meRequest(http.MethodGet, ""))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	var got MeResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if got.ID != testSub || got.Username == nil || *got.Username != "alice" || got.Preferences.DefaultSort != "added_desc" {
+		t.Errorf("unexpected profile: %+v", got)
+	}
+}
+
+func TestGetMe_MissingUser(t *testing.T) {
+	h := &MeHandler{Queries: &fakeMeStore{}}
+
+	w := httptest.NewRecorder()
+	h.GetMe(w, httptest.NewRequest(http.MethodGet, "/me", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestPatchMe_MergesPreferences(t *testing.T) {
+	store := &fakeMeStore{}
+	h := &MeHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.PatchMe(w, meRequest(http.MethodPatch, `{"privacy":"public","timezone":"Europe/Paris","language":null}`))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	arg := store.updateArg
+	if arg.Privacy != "public" || arg.Timezone != "Europe/Paris" || arg.Language.Valid {
+		t.Errorf("patched fields not applied: %+v", arg)
+	}
+	if arg.DefaultSort != "added_desc" || arg.DefaultStatus != "want_to_read" {
+		t.Errorf("untouched fields changed: %+v", arg)
+	}
+}
+
+func TestPatchMe_Validation(t *testing.T) {
+	cases := []struct {
+		name  string
+		body  string
+		field string
+	}{
+		{"sort", `{"default_sort":"random"}`, "default_sort"},
+		{"privacy", `{"privacy":"friends"}`, "privacy"},
+		{"status", `{"default_status":"done"}`, "default_status"},
+		{"timezone", `{"timezone":"Mars/Olympus"}`, "timezone"},
+		{"language", `{"language":"english"}`, "language"},
+		{"null", `{"privacy":null}`, "privacy"},
+		{"unknown", `{"theme":"dark"}`, "theme"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := &MeHandler{Queries: &fakeMeStore{}}
+			w := httptest.NewRecorder()
+			h.PatchMe(w, meRequest(http.MethodPatch, tc.body))
+
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("status: got %d, want %d", w.Code, http.StatusUnprocessableEntity)
+			}
+			if fields := responseFields(t, w); fields[tc.field] == "" {
+				t.Errorf("expected error for %s, got %v", tc.field, fields)
+			}
+		})
+	}
+}
+
+func TestPatchMe_InvalidJSON(t *testing.T) {
+	h := &MeHandler{Queries: &fakeMeStore{}}
+
+	w := httptest.NewRecorder()
+	h.PatchMe(w, meRequest(http.MethodPatch, `["privacy"]`))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestPatchMe_MediaType(t *testing.T) {
+	for ct, want := range map[string]int{
+		"application/merge-patch+json": http.StatusOK,
+		"application/json":             http.StatusOK,
+		"text/plain":                   http.StatusUnsupportedMediaType,
+	} {
+		h := &MeHandler{Queries: &fakeMeStore{}}
+		r := meRequest(http.MethodPatch, `{"privacy":"public"}`)
+		r.Header.Set("Content-Type", ct)
+		w := httptest.NewRecorder()
+		h.PatchMe(w, r)
+
+		if w.Code != want {
+			t.Errorf("%s: status: got %d, want %d", ct, w.Code, want)
+		}
+	}
+}
+
+func TestExportMe(t *testing.T) {
+	store := &fakeMeStore{
+		books:    []database.Book{{ID: 7, Title: "Dune", UserID: testSub, Status: "reading"}},
+		webhooks: []database.WebhookSubscription{{ID: 1, UserID: testSub, Url: "https://example.com", Secret: "s3cret"}},
+	}
+	h := &MeHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.ExportMe(w, meRequest(http.MethodGet, ""))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/zip" {
+		t.Errorf("content type: got %q", ct)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if err != nil {
+		t.Fatalf("read zip: %v", err)
+	}
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(b)
+	}
+
+	var books []map[string]any
+	if err := json.Unmarshal([]byte(files["books.json"]), &books); err != nil || len(books) != 1 || books[0]["title"] != "Dune" {
+		t.Errorf("books.json: got %s (%v)", files["books.json"], err)
+	}
+	if !strings.HasPrefix(files["books.csv"], "id,title,authors,") || !strings.Contains(files["books.csv"], "7,Dune,") {
+		t.Errorf("books.csv: got %q", files["books.csv"])
+	}
+	if _, ok := files["profile.json"]; !ok {
+		t.Errorf("missing profile.json, got %v", slices.Collect(maps.Keys(files)))
+	}
+	if strings.Contains(files["webhook_subscriptions.json"], "s3cret") {
+		t.Error("webhook secret leaked into export")
+	}
+}
+
+func TestDeleteMe_RequiresConfirmation(t *testing.T) {
+	store := &fakeMeStore{}
+	h := &MeHandler{Queries: store, DeletionGrace: 72 * time.Hour}
+
+	w := httptest.NewRecorder()
+	h.DeleteMe(w, meRequest(http.MethodDelete, ""))
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusAccepted)
+	}
+	if cc := w.Header().Get("Cache-Control"); cc != "no-store" {
+		t.Errorf("Cache-Control: got %q, want no-store", cc)
+	}
+	var got struct {
+		ConfirmationToken string `json:"confirmation_token"`
+	}
+	json.NewDecoder(w.Body).Decode(&got)
+	if got.ConfirmationToken == "" || store.tokenArg.DeletionTokenHash.String != hashToken(got.ConfirmationToken) {
+		t.Fatalf("expected token whose hash is stored, got %+v", store.tokenArg)
+	}
+	if store.scheduleArg.UserID != "" {
+		t.Fatal("deletion scheduled without confirmation")
+	}
+
+	u := testUser()
+	u.DeletionTokenHash = store.tokenArg.DeletionTokenHash
+	u.DeletionTokenExpiresAt = store.tokenArg.DeletionTokenExpiresAt
+	w = httptest.NewRecorder()
+	h.DeleteMe(w, meRequestAs(u, http.MethodDelete, `{"confirmation_token":"`+got.ConfirmationToken+`"}`))
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("confirm status: got %d, want %d", w.Code, http.StatusAccepted)
+	}
+	due := store.scheduleArg.DeletionScheduledFor.Time
+	if d := time.Until(due); d < 71*time.Hour || d > 72*time.Hour {
+		t.Errorf("deletion scheduled for %v, want ~72h from now", due)
+	}
+}
+
+func TestDeleteMe_InvalidConfirmation(t *testing.T) {
+	expired := testUser()
+	expired.DeletionTokenHash = sql.NullString{String: hashToken("tok"), Valid: true}
+	expired.DeletionTokenExpiresAt = sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true}
+
+	cases := []struct {
+		name  string
+		user  database.User
+		token string
+	}{
+		{"never requested", testUser(), "tok"},
+		{"wrong token", func() database.User {
+			u := expired
+			u.DeletionTokenExpiresAt.Time = time.Now().Add(time.Minute)
+			return u
+		}(), "other"},
+		{"expired", expired, "tok"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &fakeMeStore{}
+			h := &MeHandler{Queries: store}
+			w := httptest.NewRecorder()
+			h.DeleteMe(w, meRequestAs(tc.user, http.MethodDelete, `{"confirmation_token":"`+tc.token+`"}`))
+
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Errorf("status: got %d, want %d", w.Code, http.StatusUnprocessableEntity)
+			}
+			if store.scheduleArg.UserID != "" {
+				t.Error("deletion scheduled with an invalid token")
+			}
+		})
+	}
+}
+
+func TestDeleteMe_AlreadyScheduled(t *testing.T) {
+	u := testUser()
+	u.DeletionScheduledFor = sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true}
+	h := &MeHandler{Queries: &fakeMeStore{}}
+
+	w := httptest.NewRecorder()
+	h.DeleteMe(w, meRequestAs(u, http.MethodDelete, ""))
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusConflict)
+	}
+}
+
+func TestCancelDeletion(t *testing.T) {
+	for _, tc := range []struct {
+		cancelled int64
+		want      int
+	}{{1, http.StatusNoContent}, {0, http.StatusNotFound}} {
+		h := &MeHandler{Queries: &fakeMeStore{cancelled: tc.cancelled}}
+		w := httptest.NewRecorder()
+		h.CancelDeletion(w, meRequest(http.MethodDelete, ""))
+
+		if w.Code != tc.want {
+			t.Errorf("status: got %d, want %d", w.Code, tc.want)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/problem"
+	"github.com/go-chi/chi/v5"
+)
+
+// acquisitionSources are how a user can have come by an owned format.
+var acquisitionSources = []string{"purchase", "gift", "trade", "giveaway", "other"}
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// OwnedFormatResponse is one format in which the caller owns a readlist entry.
+type OwnedFormatResponse struct {
+	Format      string    `json:"format"`
+	PurchasedOn *string   `json:"purchased_on"`
+	PriceCents  *int32    `json:"price_cents"`
+	Currency    *string   `json:"currency"`
+	Store       *string   `json:"store"`
+	Source      *string   `json:"source"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func toOwnedFormatResponse(o database.OwnedFormat) OwnedFormatResponse {
+	r := OwnedFormatResponse{Format: o.Format, UpdatedAt: o.UpdatedAt}
+	if o.PurchasedOn.Valid {
+		purchased := o.PurchasedOn.Time.Format(time.DateOnly)
+		r.PurchasedOn = &purchased
+	}
+	if o.PriceCents.Valid {
+		r.PriceCents = &o.PriceCents.Int32
+	}
+	if o.Currency.Valid {
+		r.Currency = &o.Currency.String
+	}
+	if o.Store.Valid {
+		r.Store = &o.Store.String
+	}
+	if o.Source.Valid {
+		r.Source = &o.Source.String
+	}
+	return r
+}
+
+// readlistFilter narrows GET /readlist. Zero fields match everything.
+type readlistFilter struct {
+	status string
+	owned  *bool
+	format string
+	source string
+	store  string
+}
+
+// parseReadlistFilter reads the status, owned, format, source and store query
+// parameters. format, source and store match entries owned in a matching format.
+func parseReadlistFilter(r *http.Request) (readlistFilter, error) {
+	q := r.URL.Query()
+	f := readlistFilter{
+		status: q.Get("status"),
+		format: q.Get("format"),
+		source: q.Get("source"),
+		store:  q.Get("store"),
+	}
+	if f.status != "" && !validStatus(f.status) {
+		return f, errors.New("status must be one of want_to_read, reading, finished, abandoned")
+	}
+	if v := q.Get("owned"); v != "" {
+		owned, err := strconv.ParseBool(v)
+		if err != nil {
+			return f, errors.New("owned must be true or false")
+		}
+		f.owned = &owned
+	}
+	if f.format != "" && !slices.Contains(copyFormats, f.format) {
+		return f, errors.New("format must be one of " + strings.Join(copyFormats, ", "))
+	}
+	if f.source != "" && !slices.Contains(acquisitionSources, f.source) {
+		return f, errors.New("source must be one of " + strings.Join(acquisitionSources, ", "))
+	}
+	return f, nil
+}
+
+func (f readlistFilter) matches(b database.Book, owned []database.OwnedFormat) bool {
+	if f.status != "" && b.Status != f.status {
+		return false
+	}
+	if f.owned != nil && *f.owned != (len(owned) > 0) {
+		return false
+	}
+	if f.format == "" && f.source == "" && f.store == "" {
+		return true
+	}
+	return slices.ContainsFunc(owned, func(o database.OwnedFormat) bool {
+		return (f.format == "" || o.Format == f.format) &&
+			(f.source == "" || o.Source.String == f.source) &&
+			(f.store == "" || strings.EqualFold(o.Store.String, f.store))
+	})
+}
+
+// ListOwnedFormats returns the formats in which the caller owns readlist entry {id}.
+func (h *ReadlistHandler) ListOwnedFormats(w http.ResponseWriter, r *http.Request) {
+	book, ok := h.loadBook(w, r)
+	if !ok {
+		return
+	}
+
+	owned, err := h.Queries.ListOwnedFormatsForBook(r.Context(), database.ListOwnedFormatsForBookParams{
+		BookID: book.ID,
+		UserID: book.UserID,
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to retrieve owned formats")
+		return
+	}
+	out := make([]OwnedFormatResponse, len(owned))
+	for i, o := range owned {
+		out[i] = toOwnedFormatResponse(o)
+	}
+	WriteJSON(w, http.StatusOK, out)
+}
+
+// PutOwnedFormat records that the caller owns readlist entry {id} in {format},
+// replacing any purchase details already recorded for that format.
+func (h *ReadlistHandler) PutOwnedFormat(w http.ResponseWriter, r *http.Request) {
+	format := chi.URLParam(r, "format")
+	if !slices.Contains(copyFormats, format) {
+		problem.Write(w, r, problem.NotFound, "unknown format")
+		return
+	}
+
+	var input struct {
+		PurchasedOn *string `json:"purchased_on"`
+		PriceCents  *int32  `json:"price_cents"`
+		Currency    *string `json:"currency"`
+		Store       *string `json:"store" maxlen:"100"`
+		Source      *string `json:"source"`
+	}
+	if !decodeOptionalJSON(w, r, &input) {
+		return
+	}
+
+	errs := map[string]string{}
+	purchasedOn := parseDate(errs, "purchased_on", input.PurchasedOn)
+	if input.PriceCents != nil && *input.PriceCents < 0 {
+		errs["price_cents"] = "must not be negative"
+	}
+	if input.PriceCents != nil && input.Currency == nil {
+		errs["currency"] = "is required with price_cents"
+	}
+	if input.Currency != nil && !currencyCode.MatchString(*input.Currency) {
+		errs["currency"] = "must be an ISO 4217 code such as USD"
+	}
+	if input.Source != nil && !slices.Contains(acquisitionSources, *input.Source) {
+		errs["source"] = "must be one of " + strings.Join(acquisitionSources, ", ")
+	}
+	if len(errs) > 0 {
+		problem.WriteInvalid(w, r, errs)
+		return
+	}
+
+	book, ok := h.loadBook(w, r)
+	if !ok {
+		return
+	}
+
+	owned, err := h.Queries.UpsertOwnedFormat(r.Context(), database.UpsertOwnedFormatParams{
+		BookID:      book.ID,
+		UserID:      book.UserID,
+		Format:      format,
+		PurchasedOn: purchasedOn,
+		PriceCents:  toNullInt32(input.PriceCents),
+		Currency:    toNullString(input.Currency),
+		Store:       toNullString(input.Store),
+		Source:      toNullString(input.Source),
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to record owned format")
+		return
+	}
+	WriteJSON(w, http.StatusOK, toOwnedFormatResponse(owned))
+}
+
+// DeleteOwnedFormat records that the caller no longer owns readlist entry {id} in {format}.
+func (h *ReadlistHandler) DeleteOwnedFormat(w http.ResponseWriter, r *http.Request) {
+	book, ok := h.loadBook(w, r)
+	if !ok {
+		return
+	}
+
+	n, err := h.Queries.DeleteOwnedFormat(r.Context(), database.DeleteOwnedFormatParams{
+		BookID: book.ID,
+		Format: chi.URLParam(r, "format"),
+		UserID: book.UserID,
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to remove owned format")
+		return
+	}
+	if n == 0 {
+		problem.Write(w, r, problem.NotFound, "format not owned")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadBook resolves {id} to one of the caller's readlist entries.
+func (h *ReadlistHandler) loadBook(w http.ResponseWriter, r *http.Request) (database.Book, bool) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return database.Book{}, false
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidParameter, "invalid id")
+		return database.Book{}, false
+	}
+
+	book, err := h.Queries.GetBookByID(r.Context(), database.GetBookByIDParams{ID: int32(id), UserID: sub})
+	if errors.Is(err, sql.ErrNoRows) {
+		problem.Write(w, r, problem.NotFound, "book not found")
+		return database.Book{}, false
+	}
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to retrieve book")
+		return database.Book{}, false
+	}
+	return book, true
+}
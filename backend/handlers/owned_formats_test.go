@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dcrespo1/book-list-app/pkg/database"
+)
+
+// seedOwnership has two unread entries, one owned as a gifted ebook, and a finished
+// paperback bought at a shop.
+func seedOwnership() *fakeStore {
+	return &fakeStore{
+		books: []database.Book{
+			{ID: 1, UserID: testSub, Title: "Dune", Status: "want_to_read"},
+			{ID: 2, UserID: testSub, Title: "Emma", Status: "want_to_read"},
+			{ID: 3, UserID: testSub, Title: "Ulysses", Status: "finished"},
+		},
+		owned: []database.OwnedFormat{
+			{BookID: 1, UserID: testSub, Format: "ebook", Source: sql.NullString{String: "gift", Valid: true}},
+			{BookID: 3, UserID: testSub, Format: "paperback", Source: sql.NullString{String: "purchase", Valid: true},
+				Store: sql.NullString{String: "Corner Books", Valid: true}},
+		},
+	}
+}
+
+func readlistTitles(t *testing.T, h *ReadlistHandler, target string) []string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.GetReadlist(w, withSub(httptest.NewRequest(http.MethodGet, target, nil), testSub))
+	if w.Code != http.StatusOK {
+		t.Fatalf("%s: status: got %d, want %d", target, w.Code, http.StatusOK)
+	}
+	var got []BookResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	titles := make([]string, len(got))
+	for i, b := range got {
+		titles[i] = b.Title
+	}
+	return titles
+}
+
+func TestGetReadlist_OwnershipFilters(t *testing.T) {
+	h := newHandler(seedOwnership())
+
+	cases := map[string][]string{
+		"/readlist?sort=added_asc&status=want_to_read&owned=true": {"Dune"},
+		"/readlist?sort=added_asc&owned=false":                    {"Emma"},
+		"/readlist?sort=added_asc&format=paperback":               {"Ulysses"},
+		"/readlist?sort=added_asc&source=gift":                    {"Dune"},
+		"/readlist?sort=added_asc&store=corner%20books":           {"Ulysses"},
+		"/readlist?sort=added_asc&status=want_to_read":            {"Dune", "Emma"},
+	}
+	for target, want := range cases {
+		got := readlistTitles(t, h, target)
+		if len(got) != len(want) {
+			t.Errorf("%s: got %v, want %v", target, got, want)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s: got %v, want %v", target, got, want)
+				break
+			}
+		}
+	}
+}
+
+func TestGetReadlist_IncludesOwnedFormats(t *testing.T) {
+	h := newHandler(seedOwnership())
+
+	w := httptest.NewRecorder()
+	h.GetReadlist(w, withSub(httptest.NewRequest(http.MethodGet, "/readlist?sort=added_asc", nil), testSub))
+
+	var got []BookResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if len(got) != 3 || len(got[0].Owned) != 1 || got[0].Owned[0].Format != "ebook" || got[1].Owned != nil {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestGetReadlist_InvalidFilter(t *testing.T) {
+	h := newHandler(seedOwnership())
+
+	for _, target := range []string{"/readlist?status=someday", "/readlist?owned=maybe", "/readlist?format=scroll", "/readlist?source=found"} {
+		w := httptest.NewRecorder()
+		h.GetReadlist(w, withSub(httptest.NewRequest(http.MethodGet, target, nil), testSub))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status: got %d, want %d", target, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestPutOwnedFormat_CreatesThenReplaces(t *testing.T) {
+	store := seedOwnership()
+	h := newHandler(store)
+	params := map[string]string{"id": "2", "format": "hardcover"}
+
+	w := httptest.NewRecorder()
+	h.PutOwnedFormat(w, webhookRequest(http.MethodPut, "/readlist/2/formats/hardcover",
+		`{"purchased_on":"2026-03-01","price_cents":2499,"currency":"EUR","source":"purchase"}`, params))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	var got OwnedFormatResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if got.PurchasedOn == nil || *got.PurchasedOn != "2026-03-01" || got.PriceCents == nil || *got.PriceCents != 2499 {
+		t.Errorf("got %+v", got)
+	}
+
+	w = httptest.NewRecorder()
+	h.PutOwnedFormat(w, webhookRequest(http.MethodPut, "/readlist/2/formats/hardcover", "", params))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	if len(store.owned) != 3 || store.owned[2].PriceCents.Valid {
+		t.Errorf("owned: got %+v, want the hardcover replaced without a price", store.owned)
+	}
+}
+
+func TestPutOwnedFormat_Validation(t *testing.T) {
+	h := newHandler(seedOwnership())
+	params := map[string]string{"id": "2", "format": "hardcover"}
+
+	for name, body := range map[string]string{
+		"price without currency": `{"price_cents":100}`,
+		"bad currency":           `{"price_cents":100,"currency":"euro"}`,
+		"negative price":         `{"price_cents":-1,"currency":"EUR"}`,
+		"bad date":               `{"purchased_on":"March"}`,
+		"bad source":             `{"source":"found"}`,
+	} {
+		w := httptest.NewRecorder()
+		h.PutOwnedFormat(w, webhookRequest(http.MethodPut, "/readlist/2/formats/hardcover", body, params))
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: status: got %d, want %d", name, w.Code, http.StatusUnprocessableEntity)
+		}
+	}
+}
+
+func TestPutOwnedFormat_UnknownFormatOrBook(t *testing.T) {
+	h := newHandler(seedOwnership())
+
+	for _, params := range []map[string]string{
+		{"id": "2", "format": "scroll"},
+		{"id": "9", "format": "ebook"},
+	} {
+		w := httptest.NewRecorder()
+		h.PutOwnedFormat(w, webhookRequest(http.MethodPut, "/readlist/x/formats/x", `{}`, params))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%v: status: got %d, want %d", params, w.Code, http.StatusNotFound)
+		}
+	}
+}
+
+func TestDeleteOwnedFormat(t *testing.T) {
+	store := seedOwnership()
+	h := newHandler(store)
+
+	w := httptest.NewRecorder()
+	h.DeleteOwnedFormat(w, webhookRequest(http.MethodDelete, "/readlist/1/formats/ebook", "", map[string]string{"id": "1", "format": "ebook"}))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusNoContent)
+	}
+
+	w = httptest.NewRecorder()
+	h.DeleteOwnedFormat(w, webhookRequest(http.MethodDelete, "/readlist/1/formats/ebook", "", map[string]string{"id": "1", "format": "ebook"}))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
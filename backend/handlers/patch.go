@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Media types accepted by PATCH /readlist/{id}. Plain application/json is treated
+// as a merge patch.
+const (
+	mediaTypeMergePatch = "application/merge-patch+json"
+	mediaTypeJSONPatch  = "application/json-patch+json"
+	acceptPatch         = mediaTypeMergePatch + ", " + mediaTypeJSONPatch
+)
+
+// optional distinguishes a field that was absent from the patch (Set false) from one
+// that was set, possibly to null (Set true, Value nil).
+type optional[T any] struct {
+	Set   bool
+	Value *T
+}
+
+// readlistPatch is the set of changes a PATCH request makes to a readlist entry,
+// independent of the patch format it arrived in.
+type readlistPatch struct {
+	Status optional[string]
+	Rating optional[int32]
+	Notes  optional[string]
+}
+
+// fieldErrors maps a field name (or JSON Pointer for JSON Patch) to what is wrong with it.
+type fieldErrors map[string]string
+
+// errUnsupportedPatchType is returned for a Content-Type that is not a supported patch format.
+var errUnsupportedPatchType = errors.New("unsupported patch media type")
+
+func validStatus(s string) bool {
+	switch s {
+	case "want_to_read", "reading", "finished", "abandoned":
+		return true
+	}
+	return false
+}
+
+// decodeReadlistPatch parses body according to contentType. A syntax error is
+// returned as err; problems with individual fields are collected in fieldErrors.
+func decodeReadlistPatch(contentType string, body []byte) (readlistPatch, fieldErrors, error) {
+	mediaType := "application/json"
+	if contentType != "" {
+		mt, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return readlistPatch{}, nil, errUnsupportedPatchType
+		}
+		mediaType = mt
+	}
+
+	switch mediaType {
+	case mediaTypeMergePatch, "application/json":
+		return decodeMergePatch(body)
+	case mediaTypeJSONPatch:
+		return decodeJSONPatch(body)
+	default:
+		return readlistPatch{}, nil, errUnsupportedPatchType
+	}
+}
+
+// decodeMergePatch implements RFC 7396 for a readlist entry: absent members are
+// left unchanged and null members are cleared.
+func decodeMergePatch(body []byte) (readlistPatch, fieldErrors, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(body, &doc); err != nil || doc == nil {
+		return readlistPatch{}, nil, errors.New("merge patch must be a JSON object")
+	}
+
+	var p readlistPatch
+	errs := fieldErrors{}
+	for name, raw := range doc {
+		p.apply(name, name, raw, errs)
+	}
+	return p, errs, nil
+}
+
+// decodeJSONPatch implements the add, replace and remove operations of RFC 6902
+// against the top-level /status, /rating and /notes members.
+func decodeJSONPatch(body []byte) (readlistPatch, fieldErrors, error) {
+	var ops []struct {
+		Op    string          `json:"op"`
+		Path  string          `json:"path"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(body, &ops); err != nil {
+		return readlistPatch{}, nil, errors.New("json patch must be an array of operations")
+	}
+
+	var p readlistPatch
+	errs := fieldErrors{}
+	for i, op := range ops {
+		key := fmt.Sprintf("/%d", i)
+		name, ok := strings.CutPrefix(op.Path, "/")
+		if !ok || strings.Contains(name, "/") {
+			errs[key+"/path"] = "must point at a top-level member"
+			continue
+		}
+		switch op.Op {
+		case "add", "replace":
+			if op.Value == nil {
+				errs[key+"/value"] = "is required"
+				continue
+			}
+			p.apply(op.Path, name, op.Value, errs)
+		case "remove":
+			p.apply(op.Path, name, json.RawMessage("null"), errs)
+		default:
+			errs[key+"/op"] = "must be one of: add, replace, remove"
+		}
+	}
+	return p, errs, nil
+}
+
+// apply records one member of a patch, validating it. key is the name reported in
+// errs; name is the member being changed.
+func (p *readlistPatch) apply(key, name string, raw json.RawMessage, errs fieldErrors) {
+	isNull := bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+
+	switch name {
+	case "status":
+		var v string
+		if isNull {
+			errs[key] = "cannot be cleared"
+		} else if json.Unmarshal(raw, &v) != nil || !validStatus(v) {
+			errs[key] = "must be one of: want_to_read, reading, finished, abandoned"
+		} else {
+			p.Status = optional[string]{Set: true, Value: &v}
+		}
+	case "rating":
+		var v int32
+		if isNull {
+			p.Rating = optional[int32]{Set: true}
+		} else if json.Unmarshal(raw, &v) != nil || v < 1 || v > 5 {
+			errs[key] = "must be between 1 and 5"
+		} else {
+			p.Rating = optional[int32]{Set: true, Value: &v}
+		}
+	case "notes":
+		var v string
+		if isNull {
+			p.Notes = optional[string]{Set: true}
+		} else if json.Unmarshal(raw, &v) != nil {
+			errs[key] = "must be a string"
+		} else if utf8.RuneCountInString(v) > maxNotesLength {
+			errs[key] = "must be at most " + strconv.Itoa(maxNotesLength) + " characters"
+		} else {
+			p.Notes = optional[string]{Set: true, Value: &v}
+		}
+	default:
+		errs[key] = "cannot be modified"
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import "testing"
+
+func TestDecodeMergePatch(t *testing.T) {
+	p, errs, err := decodeMergePatch([]byte(`{"status":"reading","rating":null}`))
+	if err != nil || len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v %v", err, errs)
+	}
+	if !p.Status.Set || *p.Status.Value != "reading" {
+		t.Errorf("status: got %+v", p.Status)
+	}
+	if !p.Rating.Set || p.Rating.Value != nil {
+		t.Errorf("rating should be set to null, got %+v", p.Rating)
+	}
+	if p.Notes.Set {
+		t.Errorf("notes should be absent, got %+v", p.Notes)
+	}
+}
+
+func TestDecodeMergePatch_NotAnObject(t *testing.T) {
+	for _, body := range []string{`null`, `[]`, `"reading"`, `not json`} {
+		if _, _, err := decodeMergePatch([]byte(body)); err == nil {
+			t.Errorf("%s: expected error", body)
+		}
+	}
+}
+
+func TestDecodeJSONPatch_Errors(t *testing.T) {
+	_, errs, err := decodeJSONPatch([]byte(`[
+		{"op":"move","path":"/rating"},
+		{"op":"add","path":"/rating"},
+		{"op":"replace","path":"/a/b","value":1},
+		{"op":"remove","path":"/status"}
+	]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, key := range []string{"/0/op", "/1/value", "/2/path", "/status"} {
+		if errs[key] == "" {
+			t.Errorf("expected an error for %q, got %v", key, errs)
+		}
+	}
+}
+
+func TestDecodeReadlistPatch_MediaTypes(t *testing.T) {
+	cases := []struct {
+		contentType string
+		body        string
+		wantErr     bool
+	}{
+		{"", `{"rating":3}`, false},
+		{"application/json; charset=utf-8", `{"rating":3}`, false},
+		{"application/merge-patch+json", `{"rating":3}`, false},
+		{"application/json-patch+json", `[{"op":"add","path":"/rating","value":3}]`, false},
+		{"text/plain", `{"rating":3}`, true},
+	}
+	for _, tc := range cases {
+		p, _, err := decodeReadlistPatch(tc.contentType, []byte(tc.body))
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%q: err = %v, wantErr %v", tc.contentType, err, tc.wantErr)
+			continue
+		}
+		if !tc.wantErr && (!p.Rating.Set || *p.Rating.Value != 3) {
+			t.Errorf("%q: rating not decoded: %+v", tc.contentType, p.Rating)
+		}
+	}
+}
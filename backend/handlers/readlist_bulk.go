@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/events"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/problem"
+)
+
+// Bulk modes. In atomic mode the first failing item rolls back the whole batch; in
+// best-effort mode failing items are reported and the rest are committed.
+const (
+	bulkAtomic     = "atomic"
+	bulkBestEffort = "best_effort"
+
+	maxBulkItems = 100
+)
+
+// errBulkRollback aborts an atomic batch after an item has failed.
+var errBulkRollback = errors.New("bulk operation rolled back")
+
+type bulkOperation struct {
+	Op    string          `json:"op"`
+	IDs   []int32         `json:"ids"`
+	Patch json.RawMessage `json:"patch"`
+	// IfMatch maps entry ids to the version each must currently be at, like an
+	// If-Match header per item. Entries it leaves out are updated unconditionally.
+	IfMatch map[int32]int32 `json:"if_match"`
+
+	patch readlistPatch
+}
+
+// BulkResult is the outcome of one operation on one entry. Status is the HTTP status
+// the equivalent single-entry request would have returned; 424 marks an item that
+// succeeded but was rolled back because another item in an atomic batch failed.
+type BulkResult struct {
+	Operation int    `json:"operation"`
+	ID        int32  `json:"id"`
+	Status    int    `json:"status"`
+	Error     string `json:"error,omitempty"`
+	// Book is the updated entry, rendered by the request's ResponseMapper.
+	Book any `json:"book,omitempty"`
+}
+
+type BulkResponse struct {
+	Mode      string       `json:"mode"`
+	Committed bool         `json:"committed"`
+	Results   []BulkResult `json:"results"`
+}
+
+// BulkReadlist applies a list of update and delete operations, each over a set of
+// ids, in a single transaction. Updates carry a merge patch in the same format as
+// PATCH /readlist/{id}, and either op may carry per-entry versions in if_match.
+// Events are published in the same transaction, so only committed changes have them.
+func (h *ReadlistHandler) BulkReadlist(w http.ResponseWriter, r *http.Request) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+
+	var input struct {
+		Mode       string          `json:"mode"`
+		Operations []bulkOperation `json:"operations"`
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+	if input.Mode == "" {
+		input.Mode = bulkAtomic
+	}
+	if errs := validateBulk(input.Mode, input.Operations); len(errs) > 0 {
+		problem.WriteInvalid(w, r, errs)
+		return
+	}
+
+	var (
+		results []BulkResult
+		pending []events.Event
+	)
+	err := h.Queries.WithinTx(r.Context(), func(tx BookStore) error {
+		for i, op := range input.Operations {
+			for _, id := range op.IDs {
+				res, evs, err := applyBulkItem(r.Context(), tx, sub, i, id, op)
+				if err != nil {
+					return err
+				}
+				results = append(results, res)
+				if res.Error != "" && input.Mode == bulkAtomic {
+					return errBulkRollback
+				}
+				pending = append(pending, evs...)
+			}
+		}
+		for _, ev := range pending {
+			if err := h.publish(r.Context(), tx, sub, ev.Type, ev.Data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errBulkRollback) {
+		WriteJSON(w, http.StatusConflict, BulkResponse{
+			Mode:    input.Mode,
+			Results: rolledBack(results, input.Operations),
+		})
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to apply bulk operations")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, BulkResponse{Mode: input.Mode, Committed: true, Results: results})
+}
+
+func validateBulk(mode string, ops []bulkOperation) fieldErrors {
+	errs := fieldErrors{}
+	if mode != bulkAtomic && mode != bulkBestEffort {
+		errs["mode"] = "must be one of: atomic, best_effort"
+	}
+	if len(ops) == 0 {
+		errs["operations"] = "is required"
+	}
+
+	items := 0
+	for i := range ops {
+		op := &ops[i]
+		key := fmt.Sprintf("operations/%d", i)
+		items += len(op.IDs)
+		if len(op.IDs) == 0 {
+			errs[key+"/ids"] = "is required"
+		}
+		for id := range op.IfMatch {
+			if !slices.Contains(op.IDs, id) {
+				errs[fmt.Sprintf("%s/if_match/%d", key, id)] = "is not one of ids"
+			}
+		}
+		switch op.Op {
+		case "update":
+			if op.Patch == nil {
+				errs[key+"/patch"] = "is required"
+				continue
+			}
+			patch, patchErrs, err := decodeMergePatch(op.Patch)
+			if err != nil {
+				errs[key+"/patch"] = err.Error()
+				continue
+			}
+			for name, msg := range patchErrs {
+				errs[key+"/patch/"+name] = msg
+			}
+			op.patch = patch
+		case "delete":
+		default:
+			errs[key+"/op"] = "must be one of: update, delete"
+		}
+	}
+	if items > maxBulkItems {
+		errs["operations"] = fmt.Sprintf("must cover at most %d entries in total", maxBulkItems)
+	}
+	return errs
+}
+
+// applyBulkItem runs one operation against one entry. A missing entry or a version
+// mismatch is reported in the result; only unexpected database errors are returned
+// as err.
+func applyBulkItem(ctx context.Context, tx BookStore, sub string, opIndex int, id int32, op bulkOperation) (BulkResult, []events.Event, error) {
+	res := BulkResult{Operation: opIndex, ID: id}
+	var ifMatch []int32
+	if v, ok := op.IfMatch[id]; ok {
+		ifMatch = []int32{v}
+	}
+
+	switch op.Op {
+	case "update":
+		updated, err := tx.UpdateBook(ctx, database.UpdateBookParams{
+			ID:        id,
+			UserID:    sub,
+			Status:    toNullString(op.patch.Status.Value),
+			SetRating: op.patch.Rating.Set,
+			Rating:    toNullInt32(op.patch.Rating.Value),
+			SetNotes:  op.patch.Notes.Set,
+			Notes:     toNullString(op.patch.Notes.Value),
+			IfMatch:   ifMatch,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return bulkMiss(ctx, tx, sub, res, ifMatch)
+		}
+		if err != nil {
+			return res, nil, err
+		}
+		updatedBook := bookFromUpdateRow(updated)
+		book := toBookResponse(updatedBook)
+		res.Status, res.Book = http.StatusOK, mapperFrom(ctx).Book(updatedBook, nil)
+		evs := []events.Event{{Type: events.BookUpdated, Data: book}}
+		if updated.Status != updated.PreviousStatus {
+			evs = append(evs, events.Event{Type: events.BookStatusChanged, Data: map[string]any{
+				"book":            book,
+				"previous_status": updated.PreviousStatus,
+			}})
+		}
+		return res, evs, nil
+
+	default: // delete
+		n, err := tx.DeleteBookByID(ctx, database.DeleteBookByIDParams{ID: id, UserID: sub, IfMatch: ifMatch})
+		if err != nil {
+			return res, nil, err
+		}
+		if n == 0 {
+			return bulkMiss(ctx, tx, sub, res, ifMatch)
+		}
+		res.Status = http.StatusNoContent
+		return res, []events.Event{{Type: events.BookDeleted, Data: map[string]any{"id": id}}}, nil
+	}
+}
+
+// bulkMiss reports an item whose update or delete touched no row: 412 when the
+// entry exists but is not at the version in ifMatch, 404 otherwise.
+func bulkMiss(ctx context.Context, tx BookStore, sub string, res BulkResult, ifMatch []int32) (BulkResult, []events.Event, error) {
+	res.Status, res.Error = http.StatusNotFound, "book not found"
+	if len(ifMatch) == 0 {
+		return res, nil, nil
+	}
+	_, err := tx.GetBookByID(ctx, database.GetBookByIDParams{ID: res.ID, UserID: sub})
+	if errors.Is(err, sql.ErrNoRows) {
+		return res, nil, nil
+	}
+	if err != nil {
+		return res, nil, err
+	}
+	res.Status, res.Error = http.StatusPreconditionFailed, errVersionMismatch.Error()
+	return res, nil, nil
+}
+
+// rolledBack completes the results of an aborted atomic batch: items that had
+// succeeded, and items never attempted, are reported as 424 Failed Dependency.
+func rolledBack(results []BulkResult, ops []bulkOperation) []BulkResult {
+	out := make([]BulkResult, 0, len(results))
+	for _, res := range results {
+		if res.Error == "" {
+			res = BulkResult{Operation: res.Operation, ID: res.ID, Status: http.StatusFailedDependency, Error: "rolled back"}
+		}
+		out = append(out, res)
+	}
+
+	seen := 0
+	for i, op := range ops {
+		for _, id := range op.IDs {
+			if seen++; seen > len(results) {
+				out = append(out, BulkResult{Operation: i, ID: id, Status: http.StatusFailedDependency, Error: "not attempted"})
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dcrespo1/book-list-app/events"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+)
+
+func bulkRequest(body string) *http.Request {
+	return withSub(httptest.NewRequest(http.MethodPost, "/readlist/bulk", strings.NewReader(body)), testSub)
+}
+
+func decodeBulk(t *testing.T, w *httptest.ResponseRecorder) BulkResponse {
+	t.Helper()
+	var got BulkResponse
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return got
+}
+
+func TestBulkReadlist_UpdateAndDelete(t *testing.T) {
+	pub := &recordingPublisher{}
+	store := &fakeStore{books: seedBook(), updatedBook: database.Book{ID: 1, UserID: testSub, Status: "finished"}}
+	h := &ReadlistHandler{Queries: store, Events: pub.bind}
+
+	w := httptest.NewRecorder()
+	h.BulkReadlist(w, bulkRequest(`{"operations":[
+		{"op":"update","ids":[1],"patch":{"status":"finished","rating":null}},
+		{"op":"delete","ids":[1]}
+	]}`))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	got := decodeBulk(t, w)
+	if !got.Committed || got.Mode != "atomic" || len(got.Results) != 2 {
+		t.Fatalf("unexpected response: %+v", got)
+	}
+	if got.Results[0].Status != http.StatusOK || got.Results[1].Status != http.StatusNoContent {
+		t.Errorf("unexpected results: %+v", got.Results)
+	}
+	if !store.updateArg.SetRating || store.updateArg.Rating.Valid {
+		t.Errorf("expected rating to be cleared, got %+v", store.updateArg)
+	}
+	if len(pub.events) != 3 || pub.events[2].Type != events.BookDeleted {
+		t.Errorf("unexpected events: %+v", pub.events)
+	}
+}
+
+func TestBulkReadlist_AtomicRollsBackOnFailure(t *testing.T) {
+	pub := &recordingPublisher{}
+	h := &ReadlistHandler{Queries: &fakeStore{books: seedBook()}, Events: pub.bind}
+
+	w := httptest.NewRecorder()
+	h.BulkReadlist(w, bulkRequest(`{"mode":"atomic","operations":[{"op":"delete","ids":[1,99,1]}]}`))
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusConflict)
+	}
+	got := decodeBulk(t, w)
+	if got.Committed {
+		t.Error("expected batch not to be committed")
+	}
+	want := []int{http.StatusFailedDependency, http.StatusNotFound, http.StatusFailedDependency}
+	if len(got.Results) != len(want) {
+		t.Fatalf("results: got %+v", got.Results)
+	}
+	for i, status := range want {
+		if got.Results[i].Status != status {
+			t.Errorf("result %d status: got %d, want %d", i, got.Results[i].Status, status)
+		}
+	}
+	if len(pub.events) != 0 {
+		t.Errorf("expected no events for a rolled back batch, got %+v", pub.events)
+	}
+}
+
+func TestBulkReadlist_BestEffortReportsFailures(t *testing.T) {
+	pub := &recordingPublisher{}
+	h := &ReadlistHandler{Queries: &fakeStore{books: seedBook()}, Events: pub.bind}
+
+	w := httptest.NewRecorder()
+	h.BulkReadlist(w, bulkRequest(`{"mode":"best_effort","operations":[{"op":"delete","ids":[99,1]}]}`))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	got := decodeBulk(t, w)
+	if !got.Committed || got.Results[0].Status != http.StatusNotFound || got.Results[1].Status != http.StatusNoContent {
+		t.Errorf("unexpected response: %+v", got)
+	}
+	if len(pub.events) != 1 {
+		t.Errorf("expected one event for the committed delete, got %+v", pub.events)
+	}
+}
+
+func TestBulkReadlist_IfMatch(t *testing.T) {
+	store := &fakeStore{books: seedBook(), updatedBook: database.Book{ID: 1, UserID: testSub, Status: "finished", Version: 4}}
+	h := newHandler(store)
+
+	w := httptest.NewRecorder()
+	h.BulkReadlist(w, bulkRequest(`{"mode":"best_effort","operations":[
+		{"op":"update","ids":[1],"patch":{"status":"finished"},"if_match":{"1":3}},
+		{"op":"delete","ids":[1,99],"if_match":{"1":2,"99":1}}
+	]}`))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	got := decodeBulk(t, w)
+	want := []int{http.StatusOK, http.StatusPreconditionFailed, http.StatusNotFound}
+	if len(got.Results) != len(want) {
+		t.Fatalf("results: got %+v", got.Results)
+	}
+	for i, status := range want {
+		if got.Results[i].Status != status {
+			t.Errorf("result %d status: got %d, want %d", i, got.Results[i].Status, status)
+		}
+	}
+	if !slices.Equal(store.updateArg.IfMatch, []int32{3}) {
+		t.Errorf("update IfMatch: got %v, want [3]", store.updateArg.IfMatch)
+	}
+
+	w = httptest.NewRecorder()
+	h.BulkReadlist(w, bulkRequest(`{"operations":[{"op":"update","ids":[1],"patch":{"status":"reading"}}]}`))
+	if w.Code != http.StatusOK || store.updateArg.IfMatch != nil {
+		t.Errorf("without if_match: got %d, IfMatch %v, want an unconditional update", w.Code, store.updateArg.IfMatch)
+	}
+}
+
+func TestBulkReadlist_Validation(t *testing.T) {
+	cases := []struct {
+		name  string
+		body  string
+		field string
+	}{
+		{"bad mode", `{"mode":"yolo","operations":[{"op":"delete","ids":[1]}]}`, "mode"},
+		{"no operations", `{"operations":[]}`, "operations"},
+		{"unknown op", `{"operations":[{"op":"archive","ids":[1]}]}`, "operations/0/op"},
+		{"no ids", `{"operations":[{"op":"delete"}]}`, "operations/0/ids"},
+		{"missing patch", `{"operations":[{"op":"update","ids":[1]}]}`, "operations/0/patch"},
+		{"bad status", `{"operations":[{"op":"update","ids":[1],"patch":{"status":"lost"}}]}`, "operations/0/patch/status"},
+		{"if_match outside ids", `{"operations":[{"op":"delete","ids":[1],"if_match":{"2":1}}]}`, "operations/0/if_match/2"},
+		{"too many", `{"operations":[{"op":"delete","ids":[` + strings.Repeat("1,", maxBulkItems) + `1]}]}`, "operations"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHandler(&fakeStore{})
+			w := httptest.NewRecorder()
+			h.BulkReadlist(w, bulkRequest(tc.body))
+
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("status: got %d, want %d", w.Code, http.StatusUnprocessableEntity)
+			}
+			if fields := responseFields(t, w); fields[tc.field] == "" {
+				t.Errorf("expected error for %q, got %v", tc.field, fields)
+			}
+		})
+	}
+}
+
+func TestBulkReadlist_DBError(t *testing.T) {
+	h := newHandler(&fakeStore{books: seedBook(), deleteErr: errors.New("db down")})
+
+	w := httptest.NewRecorder()
+	h.BulkReadlist(w, bulkRequest(`{"mode":"best_effort","operations":[{"op":"delete","ids":[1]}]}`))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
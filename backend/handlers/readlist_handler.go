@@ -1,15 +1,24 @@
 package handlers
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/events"
 	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/problem"
+	"github.com/dcrespo1/book-list-app/stream"
+	"github.com/dcrespo1/book-list-app/users"
+	"github.com/dcrespo1/book-list-app/webhooks"
 	"github.com/go-chi/chi/v5"
 	"github.com/lib/pq"
 )
@@ -20,8 +29,46 @@ type BookStore interface {
 	GetAllBooks(ctx context.Context, userID string) ([]database.Book, error)
 	GetBookByWorkID(ctx context.Context, arg database.GetBookByWorkIDParams) (database.Book, error)
 	GetBookByID(ctx context.Context, arg database.GetBookByIDParams) (database.Book, error)
-	UpdateBook(ctx context.Context, arg database.UpdateBookParams) (database.Book, error)
+	UpdateBook(ctx context.Context, arg database.UpdateBookParams) (database.UpdateBookRow, error)
 	DeleteBookByID(ctx context.Context, arg database.DeleteBookByIDParams) (int64, error)
+	ListTrashedBooks(ctx context.Context, userID string) ([]database.Book, error)
+	RestoreBook(ctx context.Context, arg database.RestoreBookParams) (database.Book, error)
+	ListOwnedFormats(ctx context.Context, userID string) ([]database.OwnedFormat, error)
+	ListOwnedFormatsForBook(ctx context.Context, arg database.ListOwnedFormatsForBookParams) ([]database.OwnedFormat, error)
+	UpsertOwnedFormat(ctx context.Context, arg database.UpsertOwnedFormatParams) (database.OwnedFormat, error)
+	DeleteOwnedFormat(ctx context.Context, arg database.DeleteOwnedFormatParams) (int64, error)
+
+	// The event outbox: webhook deliveries and stream events are written through the
+	// same store, and so in the same transaction, as the change that raised them.
+	webhooks.Enqueuer
+	stream.Appender
+}
+
+// TxBookStore is a BookStore that can also group calls into one transaction. fn
+// receives a BookStore bound to the transaction, which is committed if fn returns
+// nil and rolled back otherwise.
+type TxBookStore interface {
+	BookStore
+	WithinTx(ctx context.Context, fn func(BookStore) error) error
+}
+
+// DBBookStore implements TxBookStore over the generated queries and their database.
+type DBBookStore struct {
+	*database.Queries
+	DB *sql.DB
+}
+
+func (s *DBBookStore) WithinTx(ctx context.Context, fn func(BookStore) error) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(s.Queries.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func toNullString(s *string) sql.NullString {
@@ -31,76 +78,337 @@ func toNullString(s *string) sql.NullString {
 	return sql.NullString{String: *s, Valid: true}
 }
 
+func toNullInt32(n *int32) sql.NullInt32 {
+	if n == nil {
+		return sql.NullInt32{Valid: false}
+	}
+	return sql.NullInt32{Int32: *n, Valid: true}
+}
+
+// readlistSorts are the orders GET /readlist can return entries in, selected by the
+// sort query parameter or the user's default_sort preference.
+var readlistSorts = map[string]func(a, b database.Book) int{
+	"added_desc": func(a, b database.Book) int { return cmp.Compare(b.ID, a.ID) },
+	"added_asc":  func(a, b database.Book) int { return cmp.Compare(a.ID, b.ID) },
+	"title": func(a, b database.Book) int {
+		return cmp.Or(strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title)), cmp.Compare(a.ID, b.ID))
+	},
+	"author": func(a, b database.Book) int {
+		return cmp.Or(strings.Compare(strings.ToLower(a.Authors), strings.ToLower(b.Authors)), cmp.Compare(a.ID, b.ID))
+	},
+	"updated_desc": func(a, b database.Book) int {
+		return cmp.Or(b.UpdatedAt.Compare(a.UpdatedAt), cmp.Compare(b.ID, a.ID))
+	},
+}
+
 type ReadlistHandler struct {
-	Queries BookStore
+	Queries TxBookStore
+	// Events returns the publisher for changes made in a transaction, given the
+	// BookStore bound to it. Every change publishes through it before committing, so
+	// a change and its events are committed together or not at all. Optional.
+	Events func(tx BookStore) events.Publisher
+}
+
+// publish records an event for a change made through tx. A failure is returned so
+// that the transaction, and the change with it, is rolled back.
+func (h *ReadlistHandler) publish(ctx context.Context, tx BookStore, sub, eventType string, data any) error {
+	if h.Events == nil {
+		return nil
+	}
+	err := h.Events(tx).Publish(ctx, events.Event{
+		Type:       eventType,
+		UserID:     sub,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// newEntry is a work to add to the readlist.
+type newEntry struct {
+	Title       string  `json:"title" maxlen:"500"`
+	Authors     string  `json:"authors" maxlen:"1000"`
+	Subjects    *string `json:"subjects" maxlen:"2000"`
+	Description *string `json:"description" maxlen:"10000"`
+	CoverArtURL *string `json:"cover_art_url" maxlen:"2000"`
+	WorkID      string  `json:"work_id" maxlen:"50"`
+}
+
+// validate reports the required fields of e that are empty.
+func (e newEntry) validate() fieldErrors {
+	errs := fieldErrors{}
+	for field, v := range map[string]string{"title": e.Title, "authors": e.Authors, "work_id": e.WorkID} {
+		if v == "" {
+			errs[field] = "is required"
+		}
+	}
+	return errs
+}
+
+// Errors returned by the readlist operations shared between the REST and GraphQL
+// APIs. A missing entry is reported as sql.ErrNoRows.
+var (
+	errBookExists      = errors.New("book already in readlist")
+	errVersionMismatch = errors.New("book has been modified")
+	errInvalidSort     = errors.New("invalid sort")
+)
+
+// addEntry inserts e with the caller's default status and publishes book.added.
+func (h *ReadlistHandler) addEntry(ctx context.Context, sub string, e newEntry) (database.Book, error) {
+	prefs, _ := users.FromContext(ctx)
+	var book database.Book
+	err := h.Queries.WithinTx(ctx, func(tx BookStore) error {
+		id, err := tx.AddBook(ctx, database.AddBookParams{
+			UserID:      sub,
+			Title:       e.Title,
+			Authors:     e.Authors,
+			Subjects:    toNullString(e.Subjects),
+			Description: toNullString(e.Description),
+			CoverArtUrl: toNullString(e.CoverArtURL),
+			WorkID:      e.WorkID,
+			Status:      prefs.DefaultStatus,
+		})
+		if err != nil {
+			return err
+		}
+
+		book = database.Book{
+			ID:          id,
+			UserID:      sub,
+			Title:       e.Title,
+			Authors:     e.Authors,
+			Subjects:    toNullString(e.Subjects),
+			Description: toNullString(e.Description),
+			CoverArtUrl: toNullString(e.CoverArtURL),
+			WorkID:      e.WorkID,
+			Status:      prefs.DefaultStatus,
+			Version:     1,
+			UpdatedAt:   time.Now().UTC(),
+		}
+		return h.publish(ctx, tx, sub, events.BookAdded, toBookResponse(book))
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return database.Book{}, errBookExists
+		}
+		return database.Book{}, err
+	}
+	return book, nil
+}
+
+// updateEntry applies p to entry id and publishes book.updated, plus
+// book.status_changed when the status moved. ifMatch, when not empty, lists the
+// versions the entry may be at.
+func (h *ReadlistHandler) updateEntry(ctx context.Context, sub string, id int32, p readlistPatch, ifMatch []int32) (database.Book, error) {
+	var book database.Book
+	err := h.Queries.WithinTx(ctx, func(tx BookStore) error {
+		updated, err := tx.UpdateBook(ctx, database.UpdateBookParams{
+			ID:        id,
+			UserID:    sub,
+			Status:    toNullString(p.Status.Value),
+			SetRating: p.Rating.Set,
+			Rating:    toNullInt32(p.Rating.Value),
+			SetNotes:  p.Notes.Set,
+			Notes:     toNullString(p.Notes.Value),
+			IfMatch:   ifMatch,
+		})
+		if err != nil {
+			return err
+		}
+
+		book = bookFromUpdateRow(updated)
+		resp := toBookResponse(book)
+		if err := h.publish(ctx, tx, sub, events.BookUpdated, resp); err != nil {
+			return err
+		}
+		if updated.Status != updated.PreviousStatus {
+			return h.publish(ctx, tx, sub, events.BookStatusChanged, map[string]any{
+				"book":            resp,
+				"previous_status": updated.PreviousStatus,
+			})
+		}
+		return nil
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.Book{}, h.missOrConflict(ctx, sub, id)
+	}
+	if err != nil {
+		return database.Book{}, err
+	}
+	return book, nil
+}
+
+// deleteEntry moves entry id to the trash and publishes book.deleted. ifMatch is as
+// for updateEntry.
+func (h *ReadlistHandler) deleteEntry(ctx context.Context, sub string, id int32, ifMatch []int32) error {
+	err := h.Queries.WithinTx(ctx, func(tx BookStore) error {
+		n, err := tx.DeleteBookByID(ctx, database.DeleteBookByIDParams{
+			ID:      id,
+			UserID:  sub,
+			IfMatch: ifMatch,
+		})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return sql.ErrNoRows
+		}
+		return h.publish(ctx, tx, sub, events.BookDeleted, map[string]any{"id": id})
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return h.missOrConflict(ctx, sub, id)
+	}
+	return err
+}
+
+// restoreEntry puts entry id back on the readlist from the trash and publishes
+// book.added. It fails with errBookExists if the same work has been added again
+// since it was deleted.
+func (h *ReadlistHandler) restoreEntry(ctx context.Context, sub string, id int32) (database.Book, error) {
+	var book database.Book
+	err := h.Queries.WithinTx(ctx, func(tx BookStore) error {
+		var err error
+		book, err = tx.RestoreBook(ctx, database.RestoreBookParams{ID: id, UserID: sub})
+		if err != nil {
+			return err
+		}
+		return h.publish(ctx, tx, sub, events.BookAdded, toBookResponse(book))
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return database.Book{}, errBookExists
+		}
+		return database.Book{}, err
+	}
+	return book, nil
+}
+
+// entryProblem is the problem matching an error from addEntry, updateEntry,
+// deleteEntry or restoreEntry, with detail describing unexpected failures.
+func entryProblem(err error, detail string) (problem.Type, string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return problem.NotFound, "book not found"
+	case errors.Is(err, errVersionMismatch):
+		return problem.VersionMismatch, err.Error()
+	case errors.Is(err, errBookExists):
+		return problem.BookExists, err.Error()
+	default:
+		return problem.Internal, detail
+	}
+}
+
+// writeEntryError answers with the problem matching err; see entryProblem.
+func writeEntryError(w http.ResponseWriter, r *http.Request, err error, detail string) {
+	typ, msg := entryProblem(err, detail)
+	problem.Write(w, r, typ, msg)
 }
 
 func (h *ReadlistHandler) AddToReadlist(w http.ResponseWriter, r *http.Request) {
 	sub, ok := appauth.SubFromContext(r.Context())
 	if !ok {
-		WriteError(w, http.StatusInternalServerError, "missing user context")
+		problem.Write(w, r, problem.Internal, "missing user context")
 		return
 	}
 
-	var input struct {
-		Title       string  `json:"title"`
-		Authors     string  `json:"authors"`
-		Subjects    *string `json:"subjects"`
-		Description *string `json:"description"`
-		CoverArtURL *string `json:"cover_art_url"`
-		WorkID      string  `json:"work_id"`
+	var input newEntry
+	if !decodeJSON(w, r, &input) {
+		return
 	}
-
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid request body")
+	if errs := input.validate(); len(errs) > 0 {
+		problem.WriteInvalid(w, r, errs)
 		return
 	}
 
-	if input.Title == "" || input.Authors == "" || input.WorkID == "" {
-		WriteError(w, http.StatusUnprocessableEntity, "title, authors, and work_id are required")
+	book, err := h.addEntry(r.Context(), sub, input)
+	if err != nil {
+		writeEntryError(w, r, err, "failed to add book")
 		return
 	}
 
-	id, err := h.Queries.AddBook(r.Context(), database.AddBookParams{
-		UserID:      sub,
-		Title:       input.Title,
-		Authors:     input.Authors,
-		Subjects:    toNullString(input.Subjects),
-		Description: toNullString(input.Description),
-		CoverArtUrl: toNullString(input.CoverArtURL),
-		WorkID:      input.WorkID,
-	})
+	WriteJSON(w, http.StatusCreated, map[string]any{"id": book.ID})
+}
+
+// listedEntry is a readlist entry with the formats the caller owns it in.
+type listedEntry struct {
+	book  database.Book
+	owned []database.OwnedFormat
+}
+
+// listEntries returns the caller's readlist in the order sortBy names, or their
+// default_sort when it is empty, keeping the entries filter matches. An unknown
+// sort fails with errInvalidSort.
+func (h *ReadlistHandler) listEntries(ctx context.Context, sub, sortBy string, filter readlistFilter) ([]listedEntry, error) {
+	prefs, _ := users.FromContext(ctx)
+	sortBy = cmp.Or(sortBy, prefs.DefaultSort)
+	compare, ok := readlistSorts[sortBy]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errInvalidSort, sortBy)
+	}
+
+	books, err := h.Queries.GetAllBooks(ctx, sub)
 	if err != nil {
-		var pqErr *pq.Error
-		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
-			WriteError(w, http.StatusConflict, "book already in readlist")
-			return
+		return nil, err
+	}
+	owned, err := h.Queries.ListOwnedFormats(ctx, sub)
+	if err != nil {
+		return nil, err
+	}
+	ownedByBook := map[int32][]database.OwnedFormat{}
+	for _, o := range owned {
+		ownedByBook[o.BookID] = append(ownedByBook[o.BookID], o)
+	}
+
+	slices.SortStableFunc(books, compare)
+	entries := []listedEntry{}
+	for _, b := range books {
+		if filter.matches(b, ownedByBook[b.ID]) {
+			entries = append(entries, listedEntry{book: b, owned: ownedByBook[b.ID]})
 		}
-		WriteError(w, http.StatusInternalServerError, "failed to add book")
-		return
 	}
+	return entries, nil
+}
 
-	WriteJSON(w, http.StatusCreated, map[string]any{"id": id})
+// listProblem is the problem matching an error from listEntries.
+func listProblem(err error) (problem.Type, string) {
+	if errors.Is(err, errInvalidSort) {
+		return problem.InvalidParameter, err.Error()
+	}
+	return problem.Internal, "failed to retrieve readlist"
 }
 
+// GetReadlist returns the caller's readlist in the order named by the sort query
+// parameter, or by their default_sort preference, with the formats each entry is
+// owned in. status, owned, format, source and store narrow the list, so
+// ?status=want_to_read&owned=true answers which unread books are already on the shelf.
 func (h *ReadlistHandler) GetReadlist(w http.ResponseWriter, r *http.Request) {
 	sub, ok := appauth.SubFromContext(r.Context())
 	if !ok {
-		WriteError(w, http.StatusInternalServerError, "missing user context")
+		problem.Write(w, r, problem.Internal, "missing user context")
 		return
 	}
 
-	books, err := h.Queries.GetAllBooks(r.Context(), sub)
+	filter, err := parseReadlistFilter(r)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, "failed to retrieve readlist")
+		problem.Write(w, r, problem.InvalidParameter, err.Error())
 		return
 	}
-	if books == nil {
-		books = []database.Book{}
+	entries, err := h.listEntries(r.Context(), sub, r.URL.Query().Get("sort"), filter)
+	if err != nil {
+		typ, detail := listProblem(err)
+		problem.Write(w, r, typ, detail)
+		return
 	}
-	out := make([]BookResponse, len(books))
-	for i, b := range books {
-		out[i] = toBookResponse(b)
+
+	mapper := mapperFrom(r.Context())
+	out := make([]any, len(entries))
+	for i, e := range entries {
+		out[i] = mapper.Book(e.book, e.owned)
 	}
 	WriteJSON(w, http.StatusOK, out)
 }
@@ -108,7 +416,7 @@ func (h *ReadlistHandler) GetReadlist(w http.ResponseWriter, r *http.Request) {
 func (h *ReadlistHandler) GetByWorkID(w http.ResponseWriter, r *http.Request) {
 	sub, ok := appauth.SubFromContext(r.Context())
 	if !ok {
-		WriteError(w, http.StatusInternalServerError, "missing user context")
+		problem.Write(w, r, problem.Internal, "missing user context")
 		return
 	}
 
@@ -117,118 +425,180 @@ func (h *ReadlistHandler) GetByWorkID(w http.ResponseWriter, r *http.Request) {
 		UserID: sub,
 	})
 	if errors.Is(err, sql.ErrNoRows) {
-		WriteError(w, http.StatusNotFound, "book not found")
+		problem.Write(w, r, problem.NotFound, "book not found")
 		return
 	}
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, "failed to retrieve book")
+		problem.Write(w, r, problem.Internal, "failed to retrieve book")
 		return
 	}
 
-	WriteJSON(w, http.StatusOK, toBookResponse(book))
+	etag := bookETag(book.Version)
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	WriteJSON(w, http.StatusOK, mapperFrom(r.Context()).Book(book, nil))
 }
 
+// PatchReadlist applies a partial update in a single statement. The body is a JSON
+// Merge Patch (RFC 7396), where null clears rating or notes, or a JSON Patch
+// (RFC 6902) when sent as application/json-patch+json. If-Match, when sent, must
+// carry the entry's current ETag or the update is rejected with 412.
 func (h *ReadlistHandler) PatchReadlist(w http.ResponseWriter, r *http.Request) {
 	sub, ok := appauth.SubFromContext(r.Context())
 	if !ok {
-		WriteError(w, http.StatusInternalServerError, "missing user context")
+		problem.Write(w, r, problem.Internal, "missing user context")
 		return
 	}
 
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid id")
+		problem.Write(w, r, problem.InvalidParameter, "invalid id")
 		return
 	}
 
-	// Fetch current state so unset patch fields preserve their existing values.
-	current, err := h.Queries.GetBookByID(r.Context(), database.GetBookByIDParams{
-		ID:     int32(id),
-		UserID: sub,
-	})
-	if errors.Is(err, sql.ErrNoRows) {
-		WriteError(w, http.StatusNotFound, "book not found")
+	ifMatch, ok := parseIfMatch(r.Header.Get("If-Match"))
+	if !ok {
+		problem.Write(w, r, problem.VersionMismatch, "book has been modified")
 		return
 	}
-	if err != nil {
-		WriteError(w, http.StatusInternalServerError, "failed to retrieve book")
+
+	body, ok := readBody(w, r)
+	if !ok {
 		return
 	}
-
-	var input struct {
-		Status *string `json:"status"`
-		Rating *int32  `json:"rating"`
-		Notes  *string `json:"notes"`
+	patch, fieldErrs, err := decodeReadlistPatch(r.Header.Get("Content-Type"), body)
+	if errors.Is(err, errUnsupportedPatchType) {
+		w.Header().Set("Accept-Patch", acceptPatch)
+		problem.Write(w, r, problem.UnsupportedMediaType, "content type must be "+acceptPatch+" or application/json")
+		return
 	}
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid request body")
+	if err != nil {
+		problem.Write(w, r, problem.InvalidBody, "invalid request body: "+err.Error())
+		return
+	}
+	if len(fieldErrs) > 0 {
+		problem.WriteInvalid(w, r, fieldErrs)
 		return
 	}
 
-	if input.Status != nil {
-		switch *input.Status {
-		case "want_to_read", "reading", "finished", "abandoned":
-		default:
-			WriteError(w, http.StatusUnprocessableEntity, "status must be one of: want_to_read, reading, finished, abandoned")
-			return
-		}
+	book, err := h.updateEntry(r.Context(), sub, int32(id), patch, ifMatch)
+	if err != nil {
+		writeEntryError(w, r, err, "failed to update book")
+		return
 	}
-	if input.Rating != nil && (*input.Rating < 1 || *input.Rating > 5) {
-		WriteError(w, http.StatusUnprocessableEntity, "rating must be between 1 and 5")
+
+	w.Header().Set("ETag", bookETag(book.Version))
+	WriteJSON(w, http.StatusOK, mapperFrom(r.Context()).Book(book, nil))
+}
+
+// DeleteFromReadlist moves an entry to the trash. It stays restorable until the
+// purge job removes it after the retention window.
+func (h *ReadlistHandler) DeleteFromReadlist(w http.ResponseWriter, r *http.Request) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
 		return
 	}
 
-	params := database.UpdateBookParams{
-		ID:     int32(id),
-		UserID: sub,
-		Status: current.Status,
-		Rating: current.Rating,
-		Notes:  current.Notes,
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidParameter, "invalid id")
+		return
 	}
-	if input.Status != nil {
-		params.Status = *input.Status
+
+	ifMatch, ok := parseIfMatch(r.Header.Get("If-Match"))
+	if !ok {
+		problem.Write(w, r, problem.VersionMismatch, "book has been modified")
+		return
 	}
-	if input.Rating != nil {
-		params.Rating = sql.NullInt32{Int32: *input.Rating, Valid: true}
+
+	if err := h.deleteEntry(r.Context(), sub, int32(id), ifMatch); err != nil {
+		writeEntryError(w, r, err, "failed to delete book")
+		return
 	}
-	if input.Notes != nil {
-		params.Notes = sql.NullString{String: *input.Notes, Valid: true}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListTrash returns the caller's deleted entries, most recently deleted first.
+func (h *ReadlistHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
 	}
 
-	updated, err := h.Queries.UpdateBook(r.Context(), params)
+	books, err := h.Queries.ListTrashedBooks(r.Context(), sub)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, "failed to update book")
+		problem.Write(w, r, problem.Internal, "failed to retrieve trash")
 		return
 	}
-
-	WriteJSON(w, http.StatusOK, toBookResponse(updated))
+	mapper := mapperFrom(r.Context())
+	out := make([]any, len(books))
+	for i, b := range books {
+		out[i] = mapper.Book(b, nil)
+	}
+	WriteJSON(w, http.StatusOK, out)
 }
 
-func (h *ReadlistHandler) DeleteFromReadlist(w http.ResponseWriter, r *http.Request) {
+// RestoreFromTrash puts a deleted entry back on the readlist. It fails with 409 if
+// the same work has been added again since it was deleted.
+func (h *ReadlistHandler) RestoreFromTrash(w http.ResponseWriter, r *http.Request) {
 	sub, ok := appauth.SubFromContext(r.Context())
 	if !ok {
-		WriteError(w, http.StatusInternalServerError, "missing user context")
+		problem.Write(w, r, problem.Internal, "missing user context")
 		return
 	}
 
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid id")
+		problem.Write(w, r, problem.InvalidParameter, "invalid id")
 		return
 	}
 
-	n, err := h.Queries.DeleteBookByID(r.Context(), database.DeleteBookByIDParams{
-		ID:     int32(id),
-		UserID: sub,
-	})
-	if err != nil {
-		WriteError(w, http.StatusInternalServerError, "failed to delete book")
+	book, err := h.restoreEntry(r.Context(), sub, int32(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		problem.Write(w, r, problem.NotFound, "book not found in trash")
 		return
 	}
-	if n == 0 {
-		WriteError(w, http.StatusNotFound, "book not found")
+	if err != nil {
+		writeEntryError(w, r, err, "failed to restore book")
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	w.Header().Set("ETag", bookETag(book.Version))
+	WriteJSON(w, http.StatusOK, mapperFrom(r.Context()).Book(book, nil))
+}
+
+// missOrConflict explains why a conditional write touched no rows: either the entry
+// does not exist (sql.ErrNoRows) or it exists at a version If-Match did not name
+// (errVersionMismatch).
+func (h *ReadlistHandler) missOrConflict(ctx context.Context, sub string, id int32) error {
+	_, err := h.Queries.GetBookByID(ctx, database.GetBookByIDParams{ID: id, UserID: sub})
+	if err != nil {
+		return err
+	}
+	return errVersionMismatch
+}
+
+func bookFromUpdateRow(u database.UpdateBookRow) database.Book {
+	return database.Book{
+		ID:          u.ID,
+		Title:       u.Title,
+		Authors:     u.Authors,
+		Subjects:    u.Subjects,
+		Description: u.Description,
+		CoverArtUrl: u.CoverArtUrl,
+		WorkID:      u.WorkID,
+		UserID:      u.UserID,
+		Status:      u.Status,
+		Rating:      u.Rating,
+		Notes:       u.Notes,
+		Version:     u.Version,
+		UpdatedAt:   u.UpdatedAt,
+		DeletedAt:   u.DeletedAt,
+	}
 }
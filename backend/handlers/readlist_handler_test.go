@@ -9,10 +9,17 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/events"
 	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/users"
 	"github.com/go-chi/chi/v5"
 	"github.com/lib/pq"
 )
@@ -21,16 +28,22 @@ import (
 type fakeStore struct {
 	books       []database.Book
 	addedID     int32
+	addArg      database.AddBookParams
 	addErr      error
-	getAllErr    error
+	getAllErr   error
 	getOneErr   error
 	getIDErr    error
 	updateErr   error
 	deleteErr   error
 	updatedBook database.Book
+	updateArg   database.UpdateBookParams
+	trashed     []database.Book
+	restoreErr  error
+	owned       []database.OwnedFormat
 }
 
-func (f *fakeStore) AddBook(_ context.Context, _ database.AddBookParams) (int32, error) {
+func (f *fakeStore) AddBook(_ context.Context, arg database.AddBookParams) (int32, error) {
+	f.addArg = arg
 	return f.addedID, f.addErr
 }
 
@@ -62,8 +75,41 @@ func (f *fakeStore) GetBookByID(_ context.Context, arg database.GetBookByIDParam
 	return database.Book{}, sql.ErrNoRows
 }
 
-func (f *fakeStore) UpdateBook(_ context.Context, _ database.UpdateBookParams) (database.Book, error) {
-	return f.updatedBook, f.updateErr
+func (f *fakeStore) UpdateBook(_ context.Context, arg database.UpdateBookParams) (database.UpdateBookRow, error) {
+	f.updateArg = arg
+	if f.updateErr != nil {
+		return database.UpdateBookRow{}, f.updateErr
+	}
+	for _, b := range f.books {
+		if b.ID == arg.ID && b.UserID == arg.UserID {
+			if !versionMatches(b.Version, arg.IfMatch) {
+				return database.UpdateBookRow{}, sql.ErrNoRows
+			}
+			u := f.updatedBook
+			return database.UpdateBookRow{
+				ID:             u.ID,
+				UserID:         u.UserID,
+				Status:         u.Status,
+				Rating:         u.Rating,
+				Notes:          u.Notes,
+				Version:        u.Version,
+				PreviousStatus: b.Status,
+			}, nil
+		}
+	}
+	return database.UpdateBookRow{}, sql.ErrNoRows
+}
+
+func versionMatches(version int32, ifMatch []int32) bool {
+	if len(ifMatch) == 0 {
+		return true
+	}
+	for _, v := range ifMatch {
+		if v == version {
+			return true
+		}
+	}
+	return false
 }
 
 func (f *fakeStore) DeleteBookByID(_ context.Context, arg database.DeleteBookByIDParams) (int64, error) {
@@ -71,14 +117,104 @@ func (f *fakeStore) DeleteBookByID(_ context.Context, arg database.DeleteBookByI
 		return 0, f.deleteErr
 	}
 	for _, b := range f.books {
+		if b.ID == arg.ID && b.UserID == arg.UserID && versionMatches(b.Version, arg.IfMatch) {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (f *fakeStore) ListTrashedBooks(_ context.Context, userID string) ([]database.Book, error) {
+	var out []database.Book
+	for _, b := range f.trashed {
+		if b.UserID == userID {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStore) RestoreBook(_ context.Context, arg database.RestoreBookParams) (database.Book, error) {
+	if f.restoreErr != nil {
+		return database.Book{}, f.restoreErr
+	}
+	for _, b := range f.trashed {
 		if b.ID == arg.ID && b.UserID == arg.UserID {
+			b.DeletedAt = sql.NullTime{}
+			b.Version++
+			return b, nil
+		}
+	}
+	return database.Book{}, sql.ErrNoRows
+}
+
+func (f *fakeStore) ListOwnedFormats(_ context.Context, userID string) ([]database.OwnedFormat, error) {
+	var out []database.OwnedFormat
+	for _, o := range f.owned {
+		if o.UserID == userID {
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStore) ListOwnedFormatsForBook(_ context.Context, arg database.ListOwnedFormatsForBookParams) ([]database.OwnedFormat, error) {
+	var out []database.OwnedFormat
+	for _, o := range f.owned {
+		if o.BookID == arg.BookID && o.UserID == arg.UserID {
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStore) UpsertOwnedFormat(_ context.Context, arg database.UpsertOwnedFormatParams) (database.OwnedFormat, error) {
+	o := database.OwnedFormat{
+		BookID:      arg.BookID,
+		UserID:      arg.UserID,
+		Format:      arg.Format,
+		PurchasedOn: arg.PurchasedOn,
+		PriceCents:  arg.PriceCents,
+		Currency:    arg.Currency,
+		Store:       arg.Store,
+		Source:      arg.Source,
+		UpdatedAt:   time.Now(),
+	}
+	for i, existing := range f.owned {
+		if existing.BookID == arg.BookID && existing.Format == arg.Format {
+			f.owned[i] = o
+			return o, nil
+		}
+	}
+	f.owned = append(f.owned, o)
+	return o, nil
+}
+
+func (f *fakeStore) DeleteOwnedFormat(_ context.Context, arg database.DeleteOwnedFormatParams) (int64, error) {
+	for i, o := range f.owned {
+		if o.BookID == arg.BookID && o.Format == arg.Format && o.UserID == arg.UserID {
+			f.owned = append(f.owned[:i], f.owned[i+1:]...)
 			return 1, nil
 		}
 	}
 	return 0, nil
 }
 
-func newHandler(store BookStore) *ReadlistHandler {
+// The outbox writes are recorded by the publisher the handler is given instead.
+func (f *fakeStore) EnqueueWebhookDeliveries(_ context.Context, _ database.EnqueueWebhookDeliveriesParams) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeStore) InsertReadlistEvent(_ context.Context, _ database.InsertReadlistEventParams) (int64, error) {
+	return 0, nil
+}
+
+// WithinTx runs fn against the fake itself; rollback is not modelled.
+func (f *fakeStore) WithinTx(_ context.Context, fn func(BookStore) error) error {
+	return fn(f)
+}
+
+func newHandler(store TxBookStore) *ReadlistHandler {
 	return &ReadlistHandler{Queries: store}
 }
 
@@ -116,6 +252,25 @@ func TestAddToReadlist_Success(t *testing.T) {
 	}
 }
 
+func TestAddToReadlist_DefaultStatusPreference(t *testing.T) {
+	store := &fakeStore{addedID: 42}
+	h := newHandler(store)
+
+	body := `{"title":"Dune","authors":"Frank Herbert","work_id":"OL12345W"}`
+	r := withSub(httptest.NewRequest(http.MethodPost, "/readlist", bytes.NewBufferString(body)), testSub)
+	h.AddToReadlist(httptest.NewRecorder(), r)
+	if store.addArg.Status != "want_to_read" {
+		t.Errorf("status without preference: got %q, want want_to_read", store.addArg.Status)
+	}
+
+	r = withSub(httptest.NewRequest(http.MethodPost, "/readlist", bytes.NewBufferString(body)), testSub)
+	r = r.WithContext(users.ToContext(r.Context(), database.User{DefaultStatus: "reading"}))
+	h.AddToReadlist(httptest.NewRecorder(), r)
+	if store.addArg.Status != "reading" {
+		t.Errorf("status with preference: got %q, want reading", store.addArg.Status)
+	}
+}
+
 func TestAddToReadlist_InvalidJSON(t *testing.T) {
 	h := newHandler(&fakeStore{})
 
@@ -200,6 +355,57 @@ func TestGetReadlist_ReturnsSavedBooks(t *testing.T) {
 	}
 }
 
+func TestGetReadlist_Sort(t *testing.T) {
+	books := []database.Book{
+		{ID: 3, Title: "dune", Authors: "Frank Herbert", UserID: testSub},
+		{ID: 2, Title: "Anathem", Authors: "Neal Stephenson", UserID: testSub},
+		{ID: 1, Title: "Contact", Authors: "Carl Sagan", UserID: testSub},
+	}
+	cases := []struct {
+		name   string
+		target string
+		prefs  *database.User
+		want   []int32
+	}{
+		{"default", "/readlist", nil, []int32{3, 2, 1}},
+		{"query", "/readlist?sort=title", nil, []int32{2, 1, 3}},
+		{"preference", "/readlist", &database.User{DefaultSort: "author"}, []int32{1, 3, 2}},
+		{"query beats preference", "/readlist?sort=added_asc", &database.User{DefaultSort: "author"}, []int32{1, 2, 3}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHandler(&fakeStore{books: slices.Clone(books)})
+			r := withSub(httptest.NewRequest(http.MethodGet, tc.target, nil), testSub)
+			if tc.prefs != nil {
+				r = r.WithContext(users.ToContext(r.Context(), *tc.prefs))
+			}
+			w := httptest.NewRecorder()
+			h.GetReadlist(w, r)
+
+			var got []BookResponse
+			json.NewDecoder(w.Body).Decode(&got)
+			ids := make([]int32, len(got))
+			for i, b := range got {
+				ids[i] = b.ID
+			}
+			if !slices.Equal(ids, tc.want) {
+				t.Errorf("order: got %v, want %v", ids, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetReadlist_InvalidSort(t *testing.T) {
+	h := newHandler(&fakeStore{})
+
+	w := httptest.NewRecorder()
+	h.GetReadlist(w, withSub(httptest.NewRequest(http.MethodGet, "/readlist?sort=random", nil), testSub))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
 // Verifies that an empty readlist returns [] (JSON array), not null.
 func TestGetReadlist_EmptyReturnsArray(t *testing.T) {
 	h := newHandler(&fakeStore{})
@@ -280,7 +486,7 @@ func TestGetByWorkID_DBError(t *testing.T) {
 
 func seedBook() []database.Book {
 	return []database.Book{
-		{ID: 1, Title: "Dune", Authors: "Frank Herbert", WorkID: "OL12345W", UserID: testSub, Status: "want_to_read"},
+		{ID: 1, Title: "Dune", Authors: "Frank Herbert", WorkID: "OL12345W", UserID: testSub, Status: "want_to_read", Version: 3},
 	}
 }
 
@@ -429,3 +635,358 @@ func TestDeleteFromReadlist_DBError(t *testing.T) {
 		t.Errorf("status: got %d, want %d", w.Code, http.StatusInternalServerError)
 	}
 }
+
+func TestPatchReadlist_NullClearsField(t *testing.T) {
+	store := &fakeStore{books: seedBook(), updatedBook: database.Book{ID: 1, UserID: testSub}}
+	h := newHandler(store)
+
+	w := httptest.NewRecorder()
+	r := patchRequest("1", `{"rating":null,"notes":null}`)
+	r.Header.Set("Content-Type", "application/merge-patch+json")
+	h.PatchReadlist(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	arg := store.updateArg
+	if !arg.SetRating || arg.Rating.Valid || !arg.SetNotes || arg.Notes.Valid {
+		t.Errorf("expected rating and notes to be cleared, got %+v", arg)
+	}
+	if arg.Status.Valid {
+		t.Errorf("status should be left unchanged, got %+v", arg.Status)
+	}
+}
+
+func TestPatchReadlist_AbsentFieldsUntouched(t *testing.T) {
+	store := &fakeStore{books: seedBook(), updatedBook: database.Book{ID: 1, UserID: testSub}}
+	h := newHandler(store)
+
+	w := httptest.NewRecorder()
+	h.PatchReadlist(w, patchRequest("1", `{"status":"finished"}`))
+
+	if store.updateArg.SetRating || store.updateArg.SetNotes {
+		t.Errorf("absent fields must not be written, got %+v", store.updateArg)
+	}
+}
+
+func TestPatchReadlist_JSONPatch(t *testing.T) {
+	store := &fakeStore{books: seedBook(), updatedBook: database.Book{ID: 1, UserID: testSub}}
+	h := newHandler(store)
+
+	w := httptest.NewRecorder()
+	r := patchRequest("1", `[{"op":"replace","path":"/rating","value":4},{"op":"remove","path":"/notes"}]`)
+	r.Header.Set("Content-Type", "application/json-patch+json")
+	h.PatchReadlist(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	arg := store.updateArg
+	if !arg.SetRating || arg.Rating.Int32 != 4 || !arg.SetNotes || arg.Notes.Valid {
+		t.Errorf("unexpected update params: %+v", arg)
+	}
+}
+
+func TestPatchReadlist_ReportsEachInvalidField(t *testing.T) {
+	h := newHandler(&fakeStore{books: seedBook()})
+
+	w := httptest.NewRecorder()
+	h.PatchReadlist(w, patchRequest("1", `{"status":null,"rating":9,"title":"x"}`))
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+	fields := responseFields(t, w)
+	for _, f := range []string{"status", "rating", "title"} {
+		if fields[f] == "" {
+			t.Errorf("expected an error for %q, got %v", f, fields)
+		}
+	}
+}
+
+func TestPatchReadlist_UnsupportedContentType(t *testing.T) {
+	h := newHandler(&fakeStore{books: seedBook()})
+
+	w := httptest.NewRecorder()
+	r := patchRequest("1", `status=reading`)
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	h.PatchReadlist(w, r)
+
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusUnsupportedMediaType)
+	}
+	if w.Header().Get("Accept-Patch") == "" {
+		t.Error("expected Accept-Patch header on 415")
+	}
+}
+
+// --- ETags ---
+
+func TestGetByWorkID_SetsETag(t *testing.T) {
+	h := newHandler(&fakeStore{books: seedBook()})
+
+	w := httptest.NewRecorder()
+	r := withSub(withChiParam(httptest.NewRequest(http.MethodGet, "/readlist/OL12345W", nil), "workID", "OL12345W"), testSub)
+	h.GetByWorkID(w, r)
+
+	if got := w.Header().Get("ETag"); got != `"3"` {
+		t.Errorf("ETag: got %q, want %q", got, `"3"`)
+	}
+}
+
+func TestGetByWorkID_IfNoneMatch(t *testing.T) {
+	h := newHandler(&fakeStore{books: seedBook()})
+
+	w := httptest.NewRecorder()
+	r := withSub(withChiParam(httptest.NewRequest(http.MethodGet, "/readlist/OL12345W", nil), "workID", "OL12345W"), testSub)
+	r.Header.Set("If-None-Match", `W/"3"`)
+	h.GetByWorkID(w, r)
+
+	if w.Code != http.StatusNotModified {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusNotModified)
+	}
+}
+
+func TestPatchReadlist_IfMatch(t *testing.T) {
+	cases := []struct {
+		name    string
+		ifMatch string
+		want    int
+	}{
+		{"current version", `"3"`, http.StatusOK},
+		{"one of several", `"1", "3"`, http.StatusOK},
+		{"wildcard", `*`, http.StatusOK},
+		{"stale version", `"2"`, http.StatusPreconditionFailed},
+		{"weak tag", `W/"3"`, http.StatusPreconditionFailed},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			updated := database.Book{ID: 1, Status: "reading", UserID: testSub, Version: 4}
+			h := newHandler(&fakeStore{books: seedBook(), updatedBook: updated})
+
+			w := httptest.NewRecorder()
+			r := patchRequest("1", `{"status":"reading"}`)
+			r.Header.Set("If-Match", tc.ifMatch)
+			h.PatchReadlist(w, r)
+
+			if w.Code != tc.want {
+				t.Fatalf("status: got %d, want %d", w.Code, tc.want)
+			}
+			if tc.want == http.StatusOK && w.Header().Get("ETag") != `"4"` {
+				t.Errorf("ETag: got %q, want %q", w.Header().Get("ETag"), `"4"`)
+			}
+		})
+	}
+}
+
+func TestDeleteFromReadlist_IfMatch(t *testing.T) {
+	cases := []struct {
+		name    string
+		ifMatch string
+		want    int
+	}{
+		{"current version", `"3"`, http.StatusNoContent},
+		{"stale version", `"2"`, http.StatusPreconditionFailed},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHandler(&fakeStore{books: seedBook()})
+
+			w := httptest.NewRecorder()
+			r := withSub(withChiParam(httptest.NewRequest(http.MethodDelete, "/readlist/1", nil), "id", "1"), testSub)
+			r.Header.Set("If-Match", tc.ifMatch)
+			h.DeleteFromReadlist(w, r)
+
+			if w.Code != tc.want {
+				t.Errorf("status: got %d, want %d", w.Code, tc.want)
+			}
+		})
+	}
+}
+
+// Callers leave IfMatch nil when there is no precondition, and pq.Array sends a
+// nil slice as NULL, where cardinality() is NULL rather than 0. fakeStore treats
+// nil as "any version", so pin the queries to the same reading.
+func TestIfMatchQueries_AcceptNull(t *testing.T) {
+	const guard = "COALESCE(cardinality(sqlc.arg(if_match)::int[]), 0) = 0"
+	for _, name := range []string{"update_book.sql", "delete_book_by_id.sql"} {
+		query, err := os.ReadFile(filepath.Join("..", "db", "queries", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(query), guard) {
+			t.Errorf("%s: if_match predicate is not NULL-safe, want %q", name, guard)
+		}
+	}
+}
+
+// --- Events ---
+
+// recordingPublisher is a test double for events.Publisher. It fails every
+// Publish with err when set.
+type recordingPublisher struct {
+	events []events.Event
+	err    error
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, e events.Event) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.events = append(p.events, e)
+	return nil
+}
+
+// bind is a ReadlistHandler.Events that publishes every transaction's events to p.
+func (p *recordingPublisher) bind(BookStore) events.Publisher {
+	return p
+}
+
+func TestAddToReadlist_PublishFailureFailsTheChange(t *testing.T) {
+	pub := &recordingPublisher{err: errors.New("db down")}
+	h := &ReadlistHandler{Queries: &fakeStore{addedID: 42}, Events: pub.bind}
+
+	body := `{"title":"Dune","authors":"Frank Herbert","work_id":"OL12345W"}`
+	w := httptest.NewRecorder()
+	h.AddToReadlist(w, withSub(httptest.NewRequest(http.MethodPost, "/readlist", bytes.NewBufferString(body)), testSub))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestAddToReadlist_PublishesEvent(t *testing.T) {
+	pub := &recordingPublisher{}
+	h := &ReadlistHandler{Queries: &fakeStore{addedID: 42}, Events: pub.bind}
+
+	body := `{"title":"Dune","authors":"Frank Herbert","work_id":"OL12345W"}`
+	w := httptest.NewRecorder()
+	h.AddToReadlist(w, withSub(httptest.NewRequest(http.MethodPost, "/readlist", bytes.NewBufferString(body)), testSub))
+
+	if len(pub.events) != 1 || pub.events[0].Type != events.BookAdded || pub.events[0].UserID != testSub {
+		t.Fatalf("unexpected events: %+v", pub.events)
+	}
+}
+
+func TestPatchReadlist_PublishesStatusChange(t *testing.T) {
+	pub := &recordingPublisher{}
+	updated := database.Book{ID: 1, Status: "reading", UserID: testSub}
+	h := &ReadlistHandler{Queries: &fakeStore{books: seedBook(), updatedBook: updated}, Events: pub.bind}
+
+	w := httptest.NewRecorder()
+	h.PatchReadlist(w, patchRequest("1", `{"status":"reading"}`))
+
+	if len(pub.events) != 2 || pub.events[0].Type != events.BookUpdated || pub.events[1].Type != events.BookStatusChanged {
+		t.Fatalf("unexpected events: %+v", pub.events)
+	}
+}
+
+func TestPatchReadlist_NoStatusChangeEventForNotes(t *testing.T) {
+	pub := &recordingPublisher{}
+	updated := database.Book{ID: 1, Status: "want_to_read", UserID: testSub}
+	h := &ReadlistHandler{Queries: &fakeStore{books: seedBook(), updatedBook: updated}, Events: pub.bind}
+
+	w := httptest.NewRecorder()
+	h.PatchReadlist(w, patchRequest("1", `{"notes":"hmm"}`))
+
+	if len(pub.events) != 1 || pub.events[0].Type != events.BookUpdated {
+		t.Fatalf("unexpected events: %+v", pub.events)
+	}
+}
+
+func TestDeleteFromReadlist_PublishesEvent(t *testing.T) {
+	pub := &recordingPublisher{}
+	h := &ReadlistHandler{Queries: &fakeStore{books: seedBook()}, Events: pub.bind}
+
+	w := httptest.NewRecorder()
+	r := withSub(withChiParam(httptest.NewRequest(http.MethodDelete, "/readlist/1", nil), "id", "1"), testSub)
+	h.DeleteFromReadlist(w, r)
+
+	if len(pub.events) != 1 || pub.events[0].Type != events.BookDeleted {
+		t.Fatalf("unexpected events: %+v", pub.events)
+	}
+}
+
+func seedTrash() []database.Book {
+	return []database.Book{
+		{ID: 7, Title: "Dune", WorkID: "OL1W", UserID: testSub, Status: "reading", Version: 4,
+			DeletedAt: sql.NullTime{Time: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), Valid: true}},
+		{ID: 8, Title: "Emma", WorkID: "OL2W", UserID: "someone-else", Status: "finished", Version: 2,
+			DeletedAt: sql.NullTime{Time: time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), Valid: true}},
+	}
+}
+
+func TestListTrash(t *testing.T) {
+	h := newHandler(&fakeStore{trashed: seedTrash()})
+
+	w := httptest.NewRecorder()
+	h.ListTrash(w, withSub(httptest.NewRequest(http.MethodGet, "/trash", nil), testSub))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	var got []BookResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if len(got) != 1 || got[0].ID != 7 || got[0].DeletedAt == nil {
+		t.Errorf("expected only the caller's trashed book with deleted_at, got %+v", got)
+	}
+}
+
+func TestListTrash_EmptyIsArray(t *testing.T) {
+	h := newHandler(&fakeStore{})
+
+	w := httptest.NewRecorder()
+	h.ListTrash(w, withSub(httptest.NewRequest(http.MethodGet, "/trash", nil), testSub))
+
+	if body := strings.TrimSpace(w.Body.String()); body != "[]" {
+		t.Errorf("body: got %s, want []", body)
+	}
+}
+
+func TestRestoreFromTrash_Success(t *testing.T) {
+	pub := &recordingPublisher{}
+	h := &ReadlistHandler{Queries: &fakeStore{trashed: seedTrash()}, Events: pub.bind}
+
+	w := httptest.NewRecorder()
+	r := withSub(withChiParam(httptest.NewRequest(http.MethodPost, "/trash/7/restore", nil), "id", "7"), testSub)
+	h.RestoreFromTrash(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	if etag := w.Header().Get("ETag"); etag != `"5"` {
+		t.Errorf("etag: got %s, want \"5\"", etag)
+	}
+	var got BookResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if got.ID != 7 || got.DeletedAt != nil {
+		t.Errorf("unexpected restored book: %+v", got)
+	}
+	if len(pub.events) != 1 || pub.events[0].Type != events.BookAdded {
+		t.Errorf("unexpected events: %+v", pub.events)
+	}
+}
+
+func TestRestoreFromTrash_OtherUsersBookNotFound(t *testing.T) {
+	h := newHandler(&fakeStore{trashed: seedTrash()})
+
+	w := httptest.NewRecorder()
+	r := withSub(withChiParam(httptest.NewRequest(http.MethodPost, "/trash/8/restore", nil), "id", "8"), testSub)
+	h.RestoreFromTrash(w, r)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestRestoreFromTrash_WorkReAdded(t *testing.T) {
+	h := newHandler(&fakeStore{trashed: seedTrash(), restoreErr: &pq.Error{Code: "23505"}})
+
+	w := httptest.NewRecorder()
+	r := withSub(withChiParam(httptest.NewRequest(http.MethodPost, "/trash/7/restore", nil), "id", "7"), testSub)
+	h.RestoreFromTrash(w, r)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusConflict)
+	}
+}
@@ -1,27 +1,39 @@
 package handlers
 
-import "github.com/dcrespo1/book-list-app/pkg/database"
+import (
+	"time"
+
+	"github.com/dcrespo1/book-list-app/pkg/database"
+)
 
 type BookResponse struct {
-	ID          int32   `json:"id"`
-	Title       string  `json:"title"`
-	Authors     string  `json:"authors"`
-	Subjects    *string `json:"subjects"`
-	Description *string `json:"description"`
-	CoverArtURL *string `json:"cover_art_url"`
-	WorkID      string  `json:"work_id"`
-	Status      string  `json:"status"`
-	Rating      *int32  `json:"rating"`
-	Notes       *string `json:"notes"`
+	ID          int32     `json:"id"`
+	Title       string    `json:"title"`
+	Authors     string    `json:"authors"`
+	Subjects    *string   `json:"subjects"`
+	Description *string   `json:"description"`
+	CoverArtURL *string   `json:"cover_art_url"`
+	WorkID      string    `json:"work_id"`
+	Status      string    `json:"status"`
+	Rating      *int32    `json:"rating"`
+	Notes       *string   `json:"notes"`
+	Version     int32     `json:"version"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// DeletedAt is only set on entries in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Owned lists the formats the entry is owned in. Only GET /readlist sets it.
+	Owned []OwnedFormatResponse `json:"owned,omitempty"`
 }
 
 func toBookResponse(b database.Book) BookResponse {
 	r := BookResponse{
-		ID:      b.ID,
-		Title:   b.Title,
-		Authors: b.Authors,
-		WorkID:  b.WorkID,
-		Status:  b.Status,
+		ID:        b.ID,
+		Title:     b.Title,
+		Authors:   b.Authors,
+		WorkID:    b.WorkID,
+		Status:    b.Status,
+		Version:   b.Version,
+		UpdatedAt: b.UpdatedAt,
 	}
 	if b.Subjects.Valid {
 		r.Subjects = &b.Subjects.String
@@ -38,5 +50,51 @@ func toBookResponse(b database.Book) BookResponse {
 	if b.Notes.Valid {
 		r.Notes = &b.Notes.String
 	}
+	if b.DeletedAt.Valid {
+		r.DeletedAt = &b.DeletedAt.Time
+	}
+	return r
+}
+
+type CommentResponse struct {
+	ID       int32  `json:"id"`
+	WorkID   string `json:"work_id"`
+	AuthorID string `json:"author_id"`
+	// AuthorName is only set for authors whose privacy preference is public.
+	AuthorName *string   `json:"author_name"`
+	ParentID   *int32    `json:"parent_id"`
+	Body       *string   `json:"body"`
+	Spoiler    bool      `json:"spoiler"`
+	Chapter    *int32    `json:"chapter"`
+	Page       *int32    `json:"page"`
+	Deleted    bool      `json:"deleted"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// toCommentResponse keeps deleted comments in the thread as placeholders so
+// their replies still have a parent, but drops the body.
+func toCommentResponse(c database.Comment) CommentResponse {
+	r := CommentResponse{
+		ID:        c.ID,
+		WorkID:    c.WorkID,
+		AuthorID:  c.UserID,
+		Spoiler:   c.Spoiler,
+		Deleted:   c.DeletedAt.Valid,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+	if !r.Deleted {
+		r.Body = &c.Body
+	}
+	if c.ParentID.Valid {
+		r.ParentID = &c.ParentID.Int32
+	}
+	if c.Chapter.Valid {
+		r.Chapter = &c.Chapter.Int32
+	}
+	if c.Page.Valid {
+		r.Page = &c.Page.Int32
+	}
 	return r
 }
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Routes is the REST API's route table. main.go mounts it once per API version;
+// the contract tests mount the same table over fake stores, so they exercise the
+// routes and middleware order that are actually served.
+type Routes struct {
+	Books      *BookHandler
+	Readlist   *ReadlistHandler
+	Events     *EventStreamHandler
+	Loans      *LoanHandler
+	Comments   *CommentHandler
+	Webhooks   *WebhookHandler
+	Me         *MeHandler
+	Tokens     *TokenHandler
+	Households *HouseholdHandler
+
+	// Middleware for the groups below. A nil middleware is skipped.
+	Middleware RouteMiddleware
+}
+
+// RouteMiddleware holds the middleware Routes applies. Each group authenticates,
+// rate limits, validates, loads the caller's user row and then, where a replay is
+// safe, records responses for Idempotency-Key retries, in that order.
+type RouteMiddleware struct {
+	// OptionalAuth identifies callers of the public routes when it can.
+	OptionalAuth func(http.Handler) http.Handler
+	// TokenAuth accepts Keycloak and personal access tokens; TokenScopes then limits
+	// personal access tokens to their scopes. Used for the readlist routes.
+	TokenAuth   func(http.Handler) http.Handler
+	TokenScopes func(http.Handler) http.Handler
+	// Auth accepts Keycloak tokens only. Used for everything else that is protected.
+	Auth func(http.Handler) http.Handler
+
+	ProxyRateLimit func(http.Handler) http.Handler
+	APIRateLimit   func(http.Handler) http.Handler
+	// Validate checks requests and responses against the OpenAPI spec. It runs after
+	// authentication, so a request without credentials gets 401 rather than a 400 or
+	// 422 about its parameters.
+	Validate   func(http.Handler) http.Handler
+	LoadUser   func(http.Handler) http.Handler
+	Idempotent func(http.Handler) http.Handler
+}
+
+// use adds the non-nil middlewares to r.
+func use(r chi.Router, mws ...func(http.Handler) http.Handler) {
+	for _, mw := range mws {
+		if mw != nil {
+			r.Use(mw)
+		}
+	}
+}
+
+// API registers the routes shared by every API version on r.
+func (rt *Routes) API(r chi.Router) {
+	mw := rt.Middleware
+	// readlistAuth and keycloakAuth are the middleware of a protected group, up to
+	// loading the caller's user row.
+	readlistAuth := []func(http.Handler) http.Handler{mw.TokenAuth, mw.APIRateLimit, mw.TokenScopes, mw.Validate, mw.LoadUser}
+	keycloakAuth := []func(http.Handler) http.Handler{mw.Auth, mw.APIRateLimit, mw.Validate, mw.LoadUser}
+
+	// Public — read-only Open Library proxies, no auth needed; signed-in callers get
+	// results in their preferred language
+	r.Group(func(r chi.Router) {
+		use(r, mw.OptionalAuth, mw.ProxyRateLimit, mw.Validate, mw.LoadUser)
+		r.Get("/search", rt.Books.Search)
+		r.Get("/details", rt.Books.Details)
+	})
+
+	// Protected — all readlist routes require a valid Keycloak or personal access token
+	r.Route("/readlist", func(r chi.Router) {
+		use(r, readlistAuth...)
+		use(r, mw.Idempotent)
+		r.Get("/", rt.Readlist.GetReadlist)
+		r.Post("/", rt.Readlist.AddToReadlist)
+		r.Post("/bulk", rt.Readlist.BulkReadlist)
+		r.Get("/events", rt.Events.StreamReadlistEvents)
+		r.Get("/{workID}", rt.Readlist.GetByWorkID)
+		r.Patch("/{id}", rt.Readlist.PatchReadlist)
+		r.Delete("/{id}", rt.Readlist.DeleteFromReadlist)
+		r.Post("/{id}/loans", rt.Loans.LendBook)
+		r.Get("/{id}/formats", rt.Readlist.ListOwnedFormats)
+		r.Put("/{id}/formats/{format}", rt.Readlist.PutOwnedFormat)
+		r.Delete("/{id}/formats/{format}", rt.Readlist.DeleteOwnedFormat)
+	})
+
+	// Protected — copies the caller has lent out
+	r.Route("/loans", func(r chi.Router) {
+		use(r, readlistAuth...)
+		use(r, mw.Idempotent)
+		r.Get("/", rt.Loans.ListLoans)
+		r.Post("/{id}/return", rt.Loans.ReturnLoan)
+	})
+
+	// Protected — deleted readlist entries, restorable until purged
+	r.Route("/trash", func(r chi.Router) {
+		use(r, readlistAuth...)
+		use(r, mw.Idempotent)
+		r.Get("/", rt.Readlist.ListTrash)
+		r.Post("/{id}/restore", rt.Readlist.RestoreFromTrash)
+	})
+
+	// Protected — public discussion threads, shared by everyone reading a work
+	r.Route("/works/{workID}/comments", func(r chi.Router) {
+		use(r, keycloakAuth...)
+		use(r, mw.Idempotent)
+		r.Get("/", rt.Comments.ListComments)
+		r.Post("/", rt.Comments.AddComment)
+	})
+	r.Route("/comments", func(r chi.Router) {
+		use(r, keycloakAuth...)
+		use(r, mw.Idempotent)
+		r.Patch("/{id}", rt.Comments.PatchComment)
+		r.Delete("/{id}", rt.Comments.DeleteComment)
+	})
+
+	// Protected — outbound webhook subscriptions and their delivery logs
+	r.Route("/webhooks", func(r chi.Router) {
+		use(r, keycloakAuth...)
+		use(r, mw.Idempotent)
+		r.Get("/", rt.Webhooks.ListWebhooks)
+		r.Post("/", rt.Webhooks.CreateWebhook)
+		r.Delete("/{id}", rt.Webhooks.DeleteWebhook)
+		r.Get("/{id}/deliveries", rt.Webhooks.ListDeliveries)
+		r.Post("/{id}/deliveries/{deliveryID}/redeliver", rt.Webhooks.Redeliver)
+	})
+
+	// Protected — the caller's profile, preferences, data export and account deletion
+	r.Route("/me", func(r chi.Router) {
+		use(r, keycloakAuth...)
+		use(r, mw.Idempotent)
+		r.Get("/", rt.Me.GetMe)
+		r.Patch("/", rt.Me.PatchMe)
+		r.Delete("/", rt.Me.DeleteMe)
+		r.Get("/export", rt.Me.ExportMe)
+		r.Delete("/deletion", rt.Me.CancelDeletion)
+	})
+
+	// Protected — personal access tokens for scripts; the token is shown once on
+	// creation, so creation is not idempotent: a stored replay would keep it
+	r.Route("/tokens", func(r chi.Router) {
+		use(r, keycloakAuth...)
+		r.Get("/", rt.Tokens.ListTokens)
+		r.Post("/", rt.Tokens.CreateToken)
+		r.Delete("/{id}", rt.Tokens.RevokeToken)
+	})
+
+	// Protected — households sharing a library of owned copies; members only
+	r.Route("/households", func(r chi.Router) {
+		use(r, keycloakAuth...)
+		// Invites return their token in plaintext, so they are not stored for replay
+		r.Post("/{id}/invites", rt.Households.CreateInvite)
+		r.Group(func(r chi.Router) {
+			use(r, mw.Idempotent)
+			r.Get("/", rt.Households.ListHouseholds)
+			r.Post("/", rt.Households.CreateHousehold)
+			r.Post("/join", rt.Households.JoinHousehold)
+			r.Get("/{id}", rt.Households.GetHousehold)
+			r.Delete("/{id}", rt.Households.DeleteHousehold)
+			r.Patch("/{id}/members/{userID}", rt.Households.UpdateMember)
+			r.Delete("/{id}/members/{userID}", rt.Households.RemoveMember)
+			r.Get("/{id}/copies", rt.Households.ListCopies)
+			r.Post("/{id}/copies", rt.Households.AddCopy)
+			r.Patch("/{id}/copies/{copyID}", rt.Households.PatchCopy)
+			r.Delete("/{id}/copies/{copyID}", rt.Households.DeleteCopy)
+		})
+	})
+}
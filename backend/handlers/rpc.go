@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"connectrpc.com/connect"
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	booklistv1 "github.com/dcrespo1/book-list-app/pkg/proto/booklist/v1"
+	"github.com/dcrespo1/book-list-app/pkg/proto/booklist/v1/booklistv1connect"
+	"github.com/dcrespo1/book-list-app/problem"
+	"github.com/dcrespo1/book-list-app/users"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ReadlistServer serves booklist.v1.ReadlistService, the readlist and trash routes
+// for internal services with typed clients. It shares the store, validation and
+// events of the REST handlers, and errors carry the problem code REST answers the
+// same failure with.
+type ReadlistServer struct {
+	Readlist *ReadlistHandler
+}
+
+var _ booklistv1connect.ReadlistServiceHandler = (*ReadlistServer)(nil)
+
+// BookServer serves booklist.v1.BookService, the Open Library proxies.
+type BookServer struct {
+	Books *BookHandler
+}
+
+var _ booklistv1connect.BookServiceHandler = (*BookServer)(nil)
+
+func (s *ReadlistServer) ListReadlist(ctx context.Context, req *connect.Request[booklistv1.ListReadlistRequest]) (*connect.Response[booklistv1.ListReadlistResponse], error) {
+	sub, err := rpcCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sortBy := ""
+	if req.Msg.Sort != booklistv1.ReadlistSort_READLIST_SORT_UNSPECIFIED {
+		sortBy = enumValue(req.Msg.Sort, "READLIST_SORT_")
+	}
+	var filter readlistFilter
+	if req.Msg.Status != booklistv1.Status_STATUS_UNSPECIFIED {
+		if filter.status = enumValue(req.Msg.Status, "STATUS_"); !validStatus(filter.status) {
+			return nil, problem.ConnectError(ctx, problem.InvalidParameter, "invalid status: "+filter.status)
+		}
+	}
+
+	entries, err := s.Readlist.listEntries(ctx, sub, sortBy, filter)
+	if err != nil {
+		typ, detail := listProblem(err)
+		return nil, problem.ConnectError(ctx, typ, detail)
+	}
+	resp := &booklistv1.ListReadlistResponse{}
+	for _, e := range entries {
+		entry := toBookResponse(e.book)
+		for _, o := range e.owned {
+			entry.Owned = append(entry.Owned, toOwnedFormatResponse(o))
+		}
+		resp.Entries = append(resp.Entries, toReadlistEntry(entry))
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ReadlistServer) GetReadlistEntry(ctx context.Context, req *connect.Request[booklistv1.GetReadlistEntryRequest]) (*connect.Response[booklistv1.GetReadlistEntryResponse], error) {
+	sub, err := rpcCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := s.Readlist.Queries.GetBookByWorkID(ctx, database.GetBookByWorkIDParams{
+		WorkID: req.Msg.WorkId,
+		UserID: sub,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, problem.ConnectError(ctx, problem.NotFound, "book not found")
+	}
+	if err != nil {
+		return nil, problem.ConnectError(ctx, problem.Internal, "failed to retrieve book")
+	}
+	return connect.NewResponse(&booklistv1.GetReadlistEntryResponse{
+		Entry: toReadlistEntry(toBookResponse(book)),
+	}), nil
+}
+
+func (s *ReadlistServer) AddToReadlist(ctx context.Context, req *connect.Request[booklistv1.AddToReadlistRequest]) (*connect.Response[booklistv1.AddToReadlistResponse], error) {
+	sub, err := rpcCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	in := newEntry{
+		Title:       req.Msg.Title,
+		Authors:     req.Msg.Authors,
+		Subjects:    req.Msg.Subjects,
+		Description: req.Msg.Description,
+		CoverArtURL: req.Msg.CoverArtUrl,
+		WorkID:      req.Msg.WorkId,
+	}
+	// The JSON field names of newEntry are also the proto field names.
+	errs := in.validate()
+	checkLengths(reflect.ValueOf(in), "", errs)
+	if len(errs) > 0 {
+		return nil, problem.ConnectInvalid(ctx, errs)
+	}
+
+	book, err := s.Readlist.addEntry(ctx, sub, in)
+	if err != nil {
+		return nil, entryConnectError(ctx, err, "failed to add book")
+	}
+	return connect.NewResponse(&booklistv1.AddToReadlistResponse{
+		Entry: toReadlistEntry(toBookResponse(book)),
+	}), nil
+}
+
+func (s *ReadlistServer) UpdateReadlistEntry(ctx context.Context, req *connect.Request[booklistv1.UpdateReadlistEntryRequest]) (*connect.Response[booklistv1.UpdateReadlistEntryResponse], error) {
+	sub, err := rpcCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m := req.Msg
+	var patch readlistPatch
+	errs := fieldErrors{}
+	if m.Status != booklistv1.Status_STATUS_UNSPECIFIED {
+		v := enumValue(m.Status, "STATUS_")
+		if !validStatus(v) {
+			errs["status"] = "must be one of: want_to_read, reading, finished, abandoned"
+		}
+		patch.Status = optional[string]{Set: true, Value: &v}
+	}
+	switch {
+	case m.Rating != nil && m.ClearRating:
+		errs["rating"] = "cannot be set and cleared at once"
+	case m.Rating != nil && (*m.Rating < 1 || *m.Rating > 5):
+		errs["rating"] = "must be between 1 and 5"
+	case m.Rating != nil || m.ClearRating:
+		patch.Rating = optional[int32]{Set: true, Value: m.Rating}
+	}
+	switch {
+	case m.Notes != nil && m.ClearNotes:
+		errs["notes"] = "cannot be set and cleared at once"
+	case m.Notes != nil && utf8.RuneCountInString(*m.Notes) > maxNotesLength:
+		errs["notes"] = "must be at most " + strconv.Itoa(maxNotesLength) + " characters"
+	case m.Notes != nil || m.ClearNotes:
+		patch.Notes = optional[string]{Set: true, Value: m.Notes}
+	}
+	if len(errs) > 0 {
+		return nil, problem.ConnectInvalid(ctx, errs)
+	}
+
+	book, err := s.Readlist.updateEntry(ctx, sub, m.Id, patch, versions(m.IfMatch))
+	if err != nil {
+		return nil, entryConnectError(ctx, err, "failed to update book")
+	}
+	return connect.NewResponse(&booklistv1.UpdateReadlistEntryResponse{
+		Entry: toReadlistEntry(toBookResponse(book)),
+	}), nil
+}
+
+func (s *ReadlistServer) DeleteReadlistEntry(ctx context.Context, req *connect.Request[booklistv1.DeleteReadlistEntryRequest]) (*connect.Response[booklistv1.DeleteReadlistEntryResponse], error) {
+	sub, err := rpcCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Readlist.deleteEntry(ctx, sub, req.Msg.Id, versions(req.Msg.IfMatch)); err != nil {
+		return nil, entryConnectError(ctx, err, "failed to delete book")
+	}
+	return connect.NewResponse(&booklistv1.DeleteReadlistEntryResponse{}), nil
+}
+
+func (s *ReadlistServer) ListTrash(ctx context.Context, _ *connect.Request[booklistv1.ListTrashRequest]) (*connect.Response[booklistv1.ListTrashResponse], error) {
+	sub, err := rpcCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	books, err := s.Readlist.Queries.ListTrashedBooks(ctx, sub)
+	if err != nil {
+		return nil, problem.ConnectError(ctx, problem.Internal, "failed to retrieve trash")
+	}
+	resp := &booklistv1.ListTrashResponse{}
+	for _, b := range books {
+		resp.Entries = append(resp.Entries, toReadlistEntry(toBookResponse(b)))
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ReadlistServer) RestoreFromTrash(ctx context.Context, req *connect.Request[booklistv1.RestoreFromTrashRequest]) (*connect.Response[booklistv1.RestoreFromTrashResponse], error) {
+	sub, err := rpcCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := s.Readlist.restoreEntry(ctx, sub, req.Msg.Id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, problem.ConnectError(ctx, problem.NotFound, "book not found in trash")
+	}
+	if err != nil {
+		return nil, entryConnectError(ctx, err, "failed to restore book")
+	}
+	return connect.NewResponse(&booklistv1.RestoreFromTrashResponse{
+		Entry: toReadlistEntry(toBookResponse(book)),
+	}), nil
+}
+
+func (s *BookServer) SearchBooks(ctx context.Context, req *connect.Request[booklistv1.SearchBooksRequest]) (*connect.Response[booklistv1.SearchBooksResponse], error) {
+	if req.Msg.Query == "" {
+		return nil, problem.ConnectError(ctx, problem.InvalidParameter, "query must not be empty")
+	}
+	lang := req.Msg.Lang
+	if lang == "" {
+		prefs, _ := users.FromContext(ctx)
+		lang = prefs.Language.String
+	}
+	if lang != "" && !languageCode.MatchString(lang) {
+		return nil, problem.ConnectError(ctx, problem.InvalidParameter, "lang must be a two-letter ISO 639-1 code")
+	}
+
+	books, err := s.Books.SearchBooks(req.Msg.Query, lang)
+	if err != nil {
+		return nil, problem.ConnectError(ctx, problem.Internal, "failed to search books")
+	}
+	resp := &booklistv1.SearchBooksResponse{}
+	for _, b := range books {
+		resp.Results = append(resp.Results, &booklistv1.SearchResult{
+			WorkId:           b.WorkID,
+			Title:            b.Title,
+			Authors:          b.Authors,
+			FirstPublishYear: int32(b.PublishYear),
+		})
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *BookServer) GetBookDetails(ctx context.Context, req *connect.Request[booklistv1.GetBookDetailsRequest]) (*connect.Response[booklistv1.GetBookDetailsResponse], error) {
+	if !workIDPattern.MatchString(req.Msg.WorkId) {
+		return nil, problem.ConnectError(ctx, problem.InvalidParameter, "work_id must be an Open Library work ID such as OL45804W")
+	}
+
+	details, err := s.Books.GetBookDetails(req.Msg.WorkId)
+	if err != nil {
+		return nil, problem.ConnectError(ctx, problem.Internal, "failed to fetch book details")
+	}
+	resp := &booklistv1.GetBookDetailsResponse{
+		Title:       details.Title,
+		Description: details.Description,
+		Subjects:    details.Subjects,
+		CoverArtUrl: details.CoverArtURL,
+	}
+	for _, l := range details.Links {
+		resp.Links = append(resp.Links, &booklistv1.Link{Title: l.Title, Url: l.URL})
+	}
+	return connect.NewResponse(resp), nil
+}
+
+// rpcCaller returns the caller's subject, which auth.AuthInterceptor put in ctx.
+func rpcCaller(ctx context.Context) (string, error) {
+	sub, ok := appauth.SubFromContext(ctx)
+	if !ok {
+		return "", problem.ConnectError(ctx, problem.Internal, "missing user context")
+	}
+	return sub, nil
+}
+
+// entryConnectError converts an error from the shared readlist operations, using
+// detail for unexpected failures as writeEntryError does.
+func entryConnectError(ctx context.Context, err error, detail string) *connect.Error {
+	typ, msg := entryProblem(err, detail)
+	return problem.ConnectError(ctx, typ, msg)
+}
+
+// enumValue is the REST API's lower-case string for a proto enum value, which is
+// the value's name without its prefix. Values unknown to this build come out as
+// their number, which no REST value matches.
+func enumValue(e interface{ String() string }, prefix string) string {
+	return strings.ToLower(strings.TrimPrefix(e.String(), prefix))
+}
+
+func toReadlistEntry(b BookResponse) *booklistv1.ReadlistEntry {
+	e := &booklistv1.ReadlistEntry{
+		Id:          b.ID,
+		WorkId:      b.WorkID,
+		Title:       b.Title,
+		Authors:     b.Authors,
+		Subjects:    b.Subjects,
+		Description: b.Description,
+		CoverArtUrl: b.CoverArtURL,
+		Status:      booklistv1.Status(booklistv1.Status_value["STATUS_"+strings.ToUpper(b.Status)]),
+		Rating:      b.Rating,
+		Notes:       b.Notes,
+		Version:     b.Version,
+		UpdatedAt:   timestamppb.New(b.UpdatedAt),
+	}
+	if b.DeletedAt != nil {
+		e.DeletedAt = timestamppb.New(*b.DeletedAt)
+	}
+	for _, o := range b.Owned {
+		e.Owned = append(e.Owned, &booklistv1.OwnedFormat{
+			Format:      o.Format,
+			PurchasedOn: o.PurchasedOn,
+			PriceCents:  o.PriceCents,
+			Currency:    o.Currency,
+			Store:       o.Store,
+			Source:      o.Source,
+			UpdatedAt:   timestamppb.New(o.UpdatedAt),
+		})
+	}
+	return e
+}
@@ -0,0 +1,414 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	booklistv1 "github.com/dcrespo1/book-list-app/pkg/proto/booklist/v1"
+	"github.com/dcrespo1/book-list-app/pkg/proto/booklist/v1/booklistv1connect"
+	"github.com/dcrespo1/book-list-app/users"
+	"github.com/go-chi/chi/v5"
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// pipeListener is a net.Listener whose connections are in-memory pipes, so the RPC
+// tests run the real HTTP/1.1 and HTTP/2 stacks without opening sockets.
+type pipeListener struct {
+	conns  chan net.Conn
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newPipeListener() *pipeListener {
+	return &pipeListener{conns: make(chan net.Conn), closed: make(chan struct{})}
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *pipeListener) Close() error {
+	l.once.Do(func() { close(l.closed) })
+	return nil
+}
+
+func (l *pipeListener) Addr() net.Addr { return pipeAddr{} }
+
+func (l *pipeListener) dial(ctx context.Context, _, _ string) (net.Conn, error) {
+	server, client := net.Pipe()
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string { return "pipe" }
+func (pipeAddr) String() string  { return "pipe" }
+
+// rpcBaseURL is the URL clients dial; every connection ends up on the pipe.
+const rpcBaseURL = "http://booklist.test"
+
+// tokenPrincipals is a TokenVerifier that knows a fixed set of raw tokens.
+type tokenPrincipals map[string]*appauth.Principal
+
+func (m tokenPrincipals) Verify(_ context.Context, raw string) (*appauth.Principal, error) {
+	if p, ok := m[raw]; ok {
+		return p, nil
+	}
+	return nil, errors.New("unknown token")
+}
+
+var rpcTokens = tokenPrincipals{
+	"jwt":        {Subject: testSub},
+	"read-token": {Subject: testSub, PersonalToken: true, Scopes: []string{appauth.ScopeReadlistRead}},
+}
+
+// fakeUsers is a users.Store holding a single user row.
+type fakeUsers struct {
+	user database.User
+}
+
+func (f *fakeUsers) GetUser(_ context.Context, userID string) (database.User, error) {
+	if f.user.UserID != userID {
+		return database.User{}, sql.ErrNoRows
+	}
+	return f.user, nil
+}
+
+func (f *fakeUsers) UpsertUser(_ context.Context, arg database.UpsertUserParams) (database.User, error) {
+	f.user = database.User{UserID: arg.UserID, DefaultSort: "added_desc", DefaultStatus: "want_to_read"}
+	return f.user, nil
+}
+
+// rpcServer serves both services the way cmd/main.go mounts them, over a pipe.
+type rpcServer struct {
+	listener *pipeListener
+	ol       *fakeOpenLibrary
+}
+
+func newRPCServer(t *testing.T, store TxBookStore, accounts users.Store) *rpcServer {
+	t.Helper()
+	gql, ol := newGraphQLHandler(t, store)
+
+	auth := appauth.AuthInterceptor(rpcTokens)
+	loadUser := users.Interceptor(accounts)
+	r := chi.NewRouter()
+	r.Use(chimw.RequestID)
+	r.Mount(booklistv1connect.NewReadlistServiceHandler(&ReadlistServer{Readlist: gql.Readlist}, connect.WithReadMaxBytes(MaxBodyBytes), connect.WithInterceptors(
+		auth,
+		appauth.PersonalTokenScopesInterceptor(appauth.ScopeReadlistRead, appauth.ScopeReadlistWrite),
+		loadUser,
+	)))
+	r.Mount(booklistv1connect.NewBookServiceHandler(&BookServer{Books: gql.Books}, connect.WithReadMaxBytes(MaxBodyBytes), connect.WithInterceptors(auth, loadUser)))
+
+	srv := &http.Server{Handler: r, Protocols: new(http.Protocols)}
+	srv.Protocols.SetHTTP1(true)
+	srv.Protocols.SetUnencryptedHTTP2(true)
+	l := newPipeListener()
+	go srv.Serve(l)
+	t.Cleanup(func() { srv.Close() })
+	return &rpcServer{listener: l, ol: ol}
+}
+
+// httpClient dials s over HTTP/2 without TLS (h2c) when h2c is set, else HTTP/1.1.
+func (s *rpcServer) httpClient(t *testing.T, h2c bool) *http.Client {
+	tr := &http.Transport{DialContext: s.listener.dial, Protocols: new(http.Protocols)}
+	if h2c {
+		tr.Protocols.SetUnencryptedHTTP2(true)
+	} else {
+		tr.Protocols.SetHTTP1(true)
+	}
+	t.Cleanup(tr.CloseIdleConnections)
+	return &http.Client{Transport: tr}
+}
+
+// readlistClient calls ReadlistService with the Connect protocol, sending token as a
+// Bearer token unless it is empty.
+func (s *rpcServer) readlistClient(t *testing.T, token string, opts ...connect.ClientOption) booklistv1connect.ReadlistServiceClient {
+	return booklistv1connect.NewReadlistServiceClient(s.httpClient(t, false), rpcBaseURL, append(opts, bearer(token))...)
+}
+
+// bearer sends token as a Bearer token unless it is empty.
+func bearer(token string) connect.ClientOption {
+	return connect.WithInterceptors(connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if token != "" {
+				req.Header().Set("Authorization", "Bearer "+token)
+			}
+			return next(ctx, req)
+		}
+	}))
+}
+
+// errorReason returns the code of err and the reason of its ErrorInfo detail.
+func errorReason(t *testing.T, err error) (connect.Code, string) {
+	t.Helper()
+	var cerr *connect.Error
+	if !errors.As(err, &cerr) {
+		t.Fatalf("got %v, want a *connect.Error", err)
+	}
+	for _, d := range cerr.Details() {
+		if v, err := d.Value(); err == nil {
+			if info, ok := v.(*errdetails.ErrorInfo); ok {
+				return cerr.Code(), info.Reason
+			}
+		}
+	}
+	t.Fatalf("%v: no ErrorInfo detail", err)
+	return 0, ""
+}
+
+func TestRPC_Protocols(t *testing.T) {
+	store := &fakeStore{
+		books: threeBooks(),
+		owned: []database.OwnedFormat{{BookID: 2, UserID: testSub, Format: "paperback", UpdatedAt: time.Now()}},
+	}
+	s := newRPCServer(t, store, &fakeUsers{})
+
+	cases := []struct {
+		name string
+		h2c  bool
+		opts []connect.ClientOption
+	}{
+		{"connect", false, nil},
+		{"connect json", false, []connect.ClientOption{connect.WithProtoJSON()}},
+		{"grpc", true, []connect.ClientOption{connect.WithGRPC()}},
+		{"grpc-web", false, []connect.ClientOption{connect.WithGRPCWeb()}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := booklistv1connect.NewReadlistServiceClient(s.httpClient(t, tc.h2c), rpcBaseURL, append(tc.opts, bearer("jwt"))...)
+			resp, err := client.ListReadlist(context.Background(), connect.NewRequest(&booklistv1.ListReadlistRequest{
+				Sort:   booklistv1.ReadlistSort_READLIST_SORT_TITLE,
+				Status: booklistv1.Status_STATUS_WANT_TO_READ,
+			}))
+			if err != nil {
+				t.Fatalf("ListReadlist: %v", err)
+			}
+			var titles []string
+			for _, e := range resp.Msg.Entries {
+				titles = append(titles, e.Title)
+			}
+			if want := []string{"Dune Messiah", "Lost"}; !slices.Equal(titles, want) {
+				t.Errorf("titles: got %v, want %v", titles, want)
+			}
+			if e := resp.Msg.Entries[0]; e.Status != booklistv1.Status_STATUS_WANT_TO_READ || len(e.Owned) != 1 || e.Owned[0].Format != "paperback" {
+				t.Errorf("entry: got %v", e)
+			}
+		})
+	}
+}
+
+func TestRPC_Authentication(t *testing.T) {
+	s := newRPCServer(t, &fakeStore{books: seedBook()}, &fakeUsers{})
+	cases := []struct {
+		token      string
+		wantCode   connect.Code
+		wantReason string
+	}{
+		{"", connect.CodeUnauthenticated, "unauthenticated"},
+		{"forged", connect.CodeUnauthenticated, "invalid_token"},
+	}
+	for _, tc := range cases {
+		_, err := s.readlistClient(t, tc.token).ListTrash(context.Background(), connect.NewRequest(&booklistv1.ListTrashRequest{}))
+		if code, reason := errorReason(t, err); code != tc.wantCode || reason != tc.wantReason {
+			t.Errorf("token %q: got %v %s, want %v %s", tc.token, code, reason, tc.wantCode, tc.wantReason)
+		}
+	}
+}
+
+func TestRPC_PersonalTokenScopes(t *testing.T) {
+	s := newRPCServer(t, &fakeStore{books: seedBook()}, &fakeUsers{})
+	client := s.readlistClient(t, "read-token")
+
+	if _, err := client.GetReadlistEntry(context.Background(), connect.NewRequest(&booklistv1.GetReadlistEntryRequest{WorkId: "OL12345W"})); err != nil {
+		t.Errorf("read with read scope: %v", err)
+	}
+	_, err := client.DeleteReadlistEntry(context.Background(), connect.NewRequest(&booklistv1.DeleteReadlistEntryRequest{Id: 1}))
+	if code, reason := errorReason(t, err); code != connect.CodePermissionDenied || reason != "insufficient_scope" {
+		t.Errorf("write with read scope: got %v %s, want permission_denied insufficient_scope", code, reason)
+	}
+}
+
+func TestRPC_AddToReadlist(t *testing.T) {
+	store := &fakeStore{addedID: 42}
+	accounts := &fakeUsers{user: database.User{UserID: testSub, DefaultSort: "added_desc", DefaultStatus: "reading"}}
+	client := newRPCServer(t, store, accounts).readlistClient(t, "jwt", connect.WithGRPCWeb())
+
+	resp, err := client.AddToReadlist(context.Background(), connect.NewRequest(&booklistv1.AddToReadlistRequest{
+		WorkId: "OL1W", Title: "Dune", Authors: "Frank Herbert",
+	}))
+	if err != nil {
+		t.Fatalf("AddToReadlist: %v", err)
+	}
+	if e := resp.Msg.Entry; e.Id != 42 || e.Version != 1 || e.Status != booklistv1.Status_STATUS_READING {
+		t.Errorf("entry: got %v, want id 42 at version 1 with the default status preference", e)
+	}
+	if store.addArg.UserID != testSub || store.addArg.Status != "reading" {
+		t.Errorf("store got %+v", store.addArg)
+	}
+
+	_, err = client.AddToReadlist(context.Background(), connect.NewRequest(&booklistv1.AddToReadlistRequest{Title: "Dune"}))
+	var cerr *connect.Error
+	if !errors.As(err, &cerr) || cerr.Code() != connect.CodeInvalidArgument {
+		t.Fatalf("missing fields: got %v, want invalid_argument", err)
+	}
+	var fields []string
+	for _, d := range cerr.Details() {
+		if v, _ := d.Value(); v != nil {
+			if bad, ok := v.(*errdetails.BadRequest); ok {
+				for _, f := range bad.FieldViolations {
+					fields = append(fields, f.Field)
+				}
+			}
+		}
+	}
+	if want := []string{"authors", "work_id"}; !slices.Equal(fields, want) {
+		t.Errorf("field violations: got %v, want %v", fields, want)
+	}
+
+	_, err = client.AddToReadlist(context.Background(), connect.NewRequest(&booklistv1.AddToReadlistRequest{
+		WorkId: "OL1W", Title: strings.Repeat("x", MaxBodyBytes), Authors: "Frank Herbert",
+	}))
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Errorf("oversized message: got %v, want resource_exhausted", err)
+	}
+
+	store.addErr = &pq.Error{Code: "23505"}
+	_, err = client.AddToReadlist(context.Background(), connect.NewRequest(&booklistv1.AddToReadlistRequest{
+		WorkId: "OL1W", Title: "Dune", Authors: "Frank Herbert",
+	}))
+	if code, reason := errorReason(t, err); code != connect.CodeAlreadyExists || reason != "book_exists" {
+		t.Errorf("duplicate: got %v %s, want already_exists book_exists", code, reason)
+	}
+}
+
+func TestRPC_UpdateReadlistEntry(t *testing.T) {
+	store := &fakeStore{books: seedBook(), updatedBook: database.Book{ID: 1, UserID: testSub, Status: "finished", Version: 4}}
+	client := newRPCServer(t, store, &fakeUsers{}).readlistClient(t, "jwt")
+
+	ifMatch := int32(3)
+	resp, err := client.UpdateReadlistEntry(context.Background(), connect.NewRequest(&booklistv1.UpdateReadlistEntryRequest{
+		Id: 1, Status: booklistv1.Status_STATUS_FINISHED, ClearRating: true, IfMatch: &ifMatch,
+	}))
+	if err != nil {
+		t.Fatalf("UpdateReadlistEntry: %v", err)
+	}
+	if e := resp.Msg.Entry; e.Status != booklistv1.Status_STATUS_FINISHED || e.Version != 4 {
+		t.Errorf("entry: got %v", e)
+	}
+	arg := store.updateArg
+	if arg.Status.String != "finished" || !arg.SetRating || arg.Rating.Valid || arg.SetNotes || !slices.Equal(arg.IfMatch, []int32{3}) {
+		t.Errorf("store got %+v", arg)
+	}
+
+	if _, err := client.UpdateReadlistEntry(context.Background(), connect.NewRequest(&booklistv1.UpdateReadlistEntryRequest{
+		Id: 1, Status: booklistv1.Status_STATUS_FINISHED,
+	})); err != nil || store.updateArg.IfMatch != nil {
+		t.Errorf("without if_match: got %v, IfMatch %v, want an unconditional update", err, store.updateArg.IfMatch)
+	}
+
+	stale, tooHigh, four := int32(7), int32(6), int32(4)
+	cases := []struct {
+		name       string
+		req        *booklistv1.UpdateReadlistEntryRequest
+		wantCode   connect.Code
+		wantReason string
+	}{
+		{"stale", &booklistv1.UpdateReadlistEntryRequest{Id: 1, Status: booklistv1.Status_STATUS_READING, IfMatch: &stale}, connect.CodeAborted, "version_mismatch"},
+		{"missing", &booklistv1.UpdateReadlistEntryRequest{Id: 9, Status: booklistv1.Status_STATUS_READING}, connect.CodeNotFound, "not_found"},
+		{"rating out of range", &booklistv1.UpdateReadlistEntryRequest{Id: 1, Rating: &tooHigh}, connect.CodeInvalidArgument, "validation_failed"},
+		{"rating set and cleared", &booklistv1.UpdateReadlistEntryRequest{Id: 1, Rating: &four, ClearRating: true}, connect.CodeInvalidArgument, "validation_failed"},
+		{"unknown status", &booklistv1.UpdateReadlistEntryRequest{Id: 1, Status: booklistv1.Status(99)}, connect.CodeInvalidArgument, "validation_failed"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := client.UpdateReadlistEntry(context.Background(), connect.NewRequest(tc.req))
+			if code, reason := errorReason(t, err); code != tc.wantCode || reason != tc.wantReason {
+				t.Errorf("got %v %s, want %v %s", code, reason, tc.wantCode, tc.wantReason)
+			}
+		})
+	}
+}
+
+func TestRPC_DeleteAndRestore(t *testing.T) {
+	deleted := seedBook()[0]
+	deleted.ID = 5
+	deleted.DeletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	store := &fakeStore{books: seedBook(), trashed: []database.Book{deleted}}
+	s := newRPCServer(t, store, &fakeUsers{})
+	client := booklistv1connect.NewReadlistServiceClient(s.httpClient(t, true), rpcBaseURL, connect.WithGRPC(), bearer("jwt"))
+
+	if _, err := client.DeleteReadlistEntry(context.Background(), connect.NewRequest(&booklistv1.DeleteReadlistEntryRequest{Id: 1})); err != nil {
+		t.Fatalf("DeleteReadlistEntry: %v", err)
+	}
+	_, err := client.DeleteReadlistEntry(context.Background(), connect.NewRequest(&booklistv1.DeleteReadlistEntryRequest{Id: 9}))
+	if code, reason := errorReason(t, err); code != connect.CodeNotFound || reason != "not_found" {
+		t.Errorf("missing entry: got %v %s, want not_found", code, reason)
+	}
+
+	trash, err := client.ListTrash(context.Background(), connect.NewRequest(&booklistv1.ListTrashRequest{}))
+	if err != nil || len(trash.Msg.Entries) != 1 || trash.Msg.Entries[0].DeletedAt == nil {
+		t.Fatalf("ListTrash: got %v, %v", trash, err)
+	}
+	restored, err := client.RestoreFromTrash(context.Background(), connect.NewRequest(&booklistv1.RestoreFromTrashRequest{Id: 5}))
+	if err != nil || restored.Msg.Entry.DeletedAt != nil {
+		t.Errorf("RestoreFromTrash: got %v, %v", restored, err)
+	}
+
+	store.restoreErr = &pq.Error{Code: "23505"}
+	_, err = client.RestoreFromTrash(context.Background(), connect.NewRequest(&booklistv1.RestoreFromTrashRequest{Id: 5}))
+	if code, reason := errorReason(t, err); code != connect.CodeAlreadyExists || reason != "book_exists" {
+		t.Errorf("work added again: got %v %s, want already_exists book_exists", code, reason)
+	}
+}
+
+func TestRPC_BookService(t *testing.T) {
+	s := newRPCServer(t, &fakeStore{}, &fakeUsers{})
+	client := booklistv1connect.NewBookServiceClient(s.httpClient(t, false), rpcBaseURL, bearer("jwt"))
+
+	resp, err := client.SearchBooks(context.Background(), connect.NewRequest(&booklistv1.SearchBooksRequest{Query: "dune"}))
+	if err != nil {
+		t.Fatalf("SearchBooks: %v", err)
+	}
+	if r := resp.Msg.Results; len(r) != 3 || r[0].WorkId != "OL1W" || r[0].FirstPublishYear != 1965 || !slices.Equal(r[0].Authors, []string{"Frank Herbert"}) {
+		t.Errorf("results: got %v", r)
+	}
+
+	details, err := client.GetBookDetails(context.Background(), connect.NewRequest(&booklistv1.GetBookDetailsRequest{WorkId: "OL1W"}))
+	if err != nil || details.Msg.Title != "Dune" || details.Msg.Description != "Spice." {
+		t.Errorf("GetBookDetails: got %v, %v", details, err)
+	}
+
+	_, err = client.GetBookDetails(context.Background(), connect.NewRequest(&booklistv1.GetBookDetailsRequest{WorkId: "../admin"}))
+	if code, reason := errorReason(t, err); code != connect.CodeInvalidArgument || reason != "invalid_parameter" {
+		t.Errorf("bad work ID: got %v %s, want invalid_argument invalid_parameter", code, reason)
+	}
+	if n := s.ol.count("/works/"); n != 1 {
+		t.Errorf("Open Library work requests: got %d, want 1", n)
+	}
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/problem"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxTokenNameLength bounds the label users give their personal access tokens.
+const maxTokenNameLength = 100
+
+// tokenPrefixLength is how much of a token is kept in clear text so users can tell
+// their tokens apart: the "blpat_" marker plus four characters of the secret.
+const tokenPrefixLength = len(appauth.PersonalTokenPrefix) + 4
+
+// personalTokenScopes are the scopes a personal access token may be granted.
+var personalTokenScopes = []string{appauth.ScopeReadlistRead, appauth.ScopeReadlistWrite}
+
+// TokenStore is the persistence interface for personal access tokens.
+// *database.Queries satisfies it.
+type TokenStore interface {
+	CreatePersonalAccessToken(ctx context.Context, arg database.CreatePersonalAccessTokenParams) (database.PersonalAccessToken, error)
+	ListPersonalAccessTokens(ctx context.Context, userID string) ([]database.PersonalAccessToken, error)
+	RevokePersonalAccessToken(ctx context.Context, arg database.RevokePersonalAccessTokenParams) (int64, error)
+}
+
+type TokenHandler struct {
+	Queries TokenStore
+}
+
+// TokenResponse never includes the token itself; it is only returned once, on creation.
+type TokenResponse struct {
+	ID         int32      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	Token      string     `json:"token,omitempty"`
+}
+
+func toTokenResponse(t database.PersonalAccessToken) TokenResponse {
+	r := TokenResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		Prefix:    t.Prefix,
+		Scopes:    t.Scopes,
+		CreatedAt: t.CreatedAt,
+	}
+	if t.ExpiresAt.Valid {
+		r.ExpiresAt = &t.ExpiresAt.Time
+	}
+	if t.LastUsedAt.Valid {
+		r.LastUsedAt = &t.LastUsedAt.Time
+	}
+	return r
+}
+
+func (h *TokenHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+
+	var input struct {
+		Name      string     `json:"name"`
+		Scopes    []string   `json:"scopes"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	errs := map[string]string{}
+	if input.Name == "" {
+		errs["name"] = "is required"
+	} else if len(input.Name) > maxTokenNameLength {
+		errs["name"] = "must be at most " + strconv.Itoa(maxTokenNameLength) + " characters"
+	}
+	if len(input.Scopes) == 0 {
+		errs["scopes"] = "is required"
+	}
+	for _, s := range input.Scopes {
+		if !slices.Contains(personalTokenScopes, s) {
+			errs["scopes"] = "unknown scope: " + s
+			break
+		}
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		errs["expires_at"] = "must be in the future"
+	}
+	if len(errs) > 0 {
+		problem.WriteInvalid(w, r, errs)
+		return
+	}
+
+	token, hash, err := appauth.NewPersonalToken()
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to generate token")
+		return
+	}
+	var expiresAt sql.NullTime
+	if input.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *input.ExpiresAt, Valid: true}
+	}
+	slices.Sort(input.Scopes)
+
+	created, err := h.Queries.CreatePersonalAccessToken(r.Context(), database.CreatePersonalAccessTokenParams{
+		UserID:    sub,
+		Name:      input.Name,
+		TokenHash: hash,
+		Prefix:    token[:tokenPrefixLength],
+		Scopes:    slices.Compact(input.Scopes),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to create token")
+		return
+	}
+
+	resp := toTokenResponse(created)
+	resp.Token = token
+	// The plaintext token must not outlive this response, in caches or in stored
+	// idempotent replays.
+	w.Header().Set("Cache-Control", "no-store")
+	WriteJSON(w, http.StatusCreated, resp)
+}
+
+func (h *TokenHandler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+
+	tokens, err := h.Queries.ListPersonalAccessTokens(r.Context(), sub)
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to retrieve tokens")
+		return
+	}
+	out := make([]TokenResponse, len(tokens))
+	for i, t := range tokens {
+		out[i] = toTokenResponse(t)
+	}
+	WriteJSON(w, http.StatusOK, out)
+}
+
+// RevokeToken revokes one of the caller's tokens; it stops working immediately.
+func (h *TokenHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidParameter, "invalid id")
+		return
+	}
+
+	n, err := h.Queries.RevokePersonalAccessToken(r.Context(), database.RevokePersonalAccessTokenParams{
+		ID:     int32(id),
+		UserID: sub,
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to revoke token")
+		return
+	}
+	if n == 0 {
+		problem.Write(w, r, problem.NotFound, "token not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+)
+
+// fakeTokenStore is a test double for TokenStore.
+type fakeTokenStore struct {
+	tokens  []database.PersonalAccessToken
+	created database.CreatePersonalAccessTokenParams
+}
+
+func (f *fakeTokenStore) CreatePersonalAccessToken(_ context.Context, arg database.CreatePersonalAccessTokenParams) (database.PersonalAccessToken, error) {
+	f.created = arg
+	return database.PersonalAccessToken{
+		ID: 1, UserID: arg.UserID, Name: arg.Name, TokenHash: arg.TokenHash,
+		Prefix: arg.Prefix, Scopes: arg.Scopes, ExpiresAt: arg.ExpiresAt,
+	}, nil
+}
+
+func (f *fakeTokenStore) ListPersonalAccessTokens(_ context.Context, userID string) ([]database.PersonalAccessToken, error) {
+	var out []database.PersonalAccessToken
+	for _, t := range f.tokens {
+		if t.UserID == userID {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeTokenStore) RevokePersonalAccessToken(_ context.Context, arg database.RevokePersonalAccessTokenParams) (int64, error) {
+	for _, t := range f.tokens {
+		if t.ID == arg.ID && t.UserID == arg.UserID {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func seedTokens() *fakeTokenStore {
+	return &fakeTokenStore{tokens: []database.PersonalAccessToken{
+		{ID: 1, UserID: testSub, Name: "cron", TokenHash: "h4sh", Prefix: "blpat_abcd", Scopes: []string{appauth.ScopeReadlistRead}},
+		{ID: 2, UserID: "someone-else", Name: "theirs", TokenHash: "other", Prefix: "blpat_efgh"},
+	}}
+}
+
+func TestCreateToken_ReturnsTokenOnce(t *testing.T) {
+	store := &fakeTokenStore{}
+	h := &TokenHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.CreateToken(w, webhookRequest(http.MethodPost, "/tokens",
+		`{"name":"cron","scopes":["readlist:write","readlist:read","readlist:read"]}`, nil))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusCreated)
+	}
+	var got TokenResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if !strings.HasPrefix(got.Token, appauth.PersonalTokenPrefix) || !strings.HasPrefix(got.Token, got.Prefix) {
+		t.Errorf("unexpected token %q with prefix %q", got.Token, got.Prefix)
+	}
+	if store.created.TokenHash != appauth.HashPersonalToken(got.Token) {
+		t.Error("expected only the token hash to be stored")
+	}
+	if w.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("Cache-Control: got %q, want no-store", w.Header().Get("Cache-Control"))
+	}
+	if len(store.created.Scopes) != 2 || store.created.UserID != testSub {
+		t.Errorf("unexpected params: %+v", store.created)
+	}
+}
+
+func TestCreateToken_Validation(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+	}{
+		{"no name", `{"scopes":["readlist:read"]}`},
+		{"long name", `{"name":"` + strings.Repeat("x", 101) + `","scopes":["readlist:read"]}`},
+		{"no scopes", `{"name":"cron"}`},
+		{"unknown scope", `{"name":"cron","scopes":["admin"]}`},
+		{"expired", `{"name":"cron","scopes":["readlist:read"],"expires_at":"2000-01-01T00:00:00Z"}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := &TokenHandler{Queries: &fakeTokenStore{}}
+			w := httptest.NewRecorder()
+			h.CreateToken(w, webhookRequest(http.MethodPost, "/tokens", tc.body, nil))
+
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Errorf("status: got %d, want %d", w.Code, http.StatusUnprocessableEntity)
+			}
+		})
+	}
+}
+
+func TestListTokens_HidesHashes(t *testing.T) {
+	h := &TokenHandler{Queries: seedTokens()}
+
+	w := httptest.NewRecorder()
+	h.ListTokens(w, webhookRequest(http.MethodGet, "/tokens", "", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	if strings.Contains(w.Body.String(), "h4sh") {
+		t.Error("token hash leaked into list response")
+	}
+	var got []TokenResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if len(got) != 1 || got[0].ID != 1 {
+		t.Errorf("expected only the caller's token, got %+v", got)
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	cases := []struct {
+		id   string
+		want int
+	}{
+		{"1", http.StatusNoContent},
+		{"2", http.StatusNotFound},
+		{"abc", http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.id, func(t *testing.T) {
+			h := &TokenHandler{Queries: seedTokens()}
+			w := httptest.NewRecorder()
+			h.RevokeToken(w, webhookRequest(http.MethodDelete, "/tokens/"+tc.id, "", map[string]string{"id": tc.id}))
+
+			if w.Code != tc.want {
+				t.Errorf("status: got %d, want %d", w.Code, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dcrespo1/book-list-app/pkg/database"
+)
+
+// ResponseMapper renders readlist entries in one API version's wire format. Every
+// version shares the same handlers, which load and change entries identically and
+// map them only when writing the response, so a new version that reshapes an entry
+// needs just a new mapper.
+//
+// Event and webhook payloads are not versioned; they always use BookResponse.
+type ResponseMapper interface {
+	// Book renders an entry with the formats it is owned in, which may be nil.
+	Book(b database.Book, owned []database.OwnedFormat) any
+}
+
+// V1 renders entries as BookResponse, the /v1 wire format.
+var V1 ResponseMapper = v1Mapper{}
+
+type v1Mapper struct{}
+
+func (v1Mapper) Book(b database.Book, owned []database.OwnedFormat) any {
+	resp := toBookResponse(b)
+	for _, o := range owned {
+		resp.Owned = append(resp.Owned, toOwnedFormatResponse(o))
+	}
+	return resp
+}
+
+type mapperKey struct{}
+
+// WithMapper returns middleware that renders the responses of the routes it wraps
+// with m. Mount it once per API version.
+func WithMapper(m ResponseMapper) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), mapperKey{}, m)))
+		})
+	}
+}
+
+// mapperFrom returns the mapper for the request's API version, or V1 outside any
+// versioned route.
+func mapperFrom(ctx context.Context) ResponseMapper {
+	if m, ok := ctx.Value(mapperKey{}).(ResponseMapper); ok {
+		return m
+	}
+	return V1
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/go-chi/chi/v5"
+)
+
+// splitAuthorsMapper is what a later version might do: authors as an array.
+type splitAuthorsMapper struct{}
+
+func (splitAuthorsMapper) Book(b database.Book, _ []database.OwnedFormat) any {
+	return map[string]any{"id": b.ID, "authors": strings.Split(b.Authors, ", ")}
+}
+
+func TestWithMapper_SharesHandlersAcrossVersions(t *testing.T) {
+	h := newHandler(&fakeStore{books: []database.Book{
+		{ID: 1, WorkID: "OL1W", UserID: testSub, Authors: "Terry Pratchett, Neil Gaiman", Status: "reading", Version: 1},
+	}})
+	r := chi.NewRouter()
+	r.Route("/v1", func(r chi.Router) {
+		r.Use(WithMapper(V1))
+		r.Get("/readlist/{workID}", h.GetByWorkID)
+	})
+	r.Route("/v2", func(r chi.Router) {
+		r.Use(WithMapper(splitAuthorsMapper{}))
+		r.Get("/readlist/{workID}", h.GetByWorkID)
+	})
+
+	get := func(target string, dst any) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, withSub(httptest.NewRequest(http.MethodGet, target, nil), testSub))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status: got %d, want %d", target, w.Code, http.StatusOK)
+		}
+		if w.Header().Get("ETag") != `"1"` {
+			t.Errorf("%s: ETag: got %q", target, w.Header().Get("ETag"))
+		}
+		json.NewDecoder(w.Body).Decode(dst)
+	}
+
+	var v1 BookResponse
+	get("/v1/readlist/OL1W", &v1)
+	if v1.Authors != "Terry Pratchett, Neil Gaiman" {
+		t.Errorf("v1 authors: got %q", v1.Authors)
+	}
+	var v2 struct {
+		Authors []string `json:"authors"`
+	}
+	get("/v2/readlist/OL1W", &v2)
+	if len(v2.Authors) != 2 {
+		t.Errorf("v2 authors: got %q", v2.Authors)
+	}
+}
@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/events"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/problem"
+	"github.com/dcrespo1/book-list-app/webhooks"
+	"github.com/go-chi/chi/v5"
+)
+
+// WebhookStore is the persistence interface for webhook subscriptions and their
+// delivery log. *database.Queries satisfies it.
+type WebhookStore interface {
+	CreateWebhookSubscription(ctx context.Context, arg database.CreateWebhookSubscriptionParams) (database.WebhookSubscription, error)
+	ListWebhookSubscriptions(ctx context.Context, userID string) ([]database.WebhookSubscription, error)
+	GetWebhookSubscription(ctx context.Context, arg database.GetWebhookSubscriptionParams) (database.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, arg database.DeleteWebhookSubscriptionParams) (int64, error)
+	ListWebhookDeliveries(ctx context.Context, arg database.ListWebhookDeliveriesParams) ([]database.WebhookDelivery, error)
+	RedeliverWebhookDelivery(ctx context.Context, arg database.RedeliverWebhookDeliveryParams) (database.WebhookDelivery, error)
+}
+
+type WebhookHandler struct {
+	Queries WebhookStore
+}
+
+// WebhookResponse never includes the secret; it is only returned once, on creation.
+type WebhookResponse struct {
+	ID         int32     `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+	Secret     string    `json:"secret,omitempty"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             int32           `json:"id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int32           `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastStatusCode *int32          `json:"last_status_code"`
+	LastError      *string         `json:"last_error"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+}
+
+func toWebhookResponse(s database.WebhookSubscription) WebhookResponse {
+	return WebhookResponse{
+		ID:         s.ID,
+		URL:        s.Url,
+		EventTypes: s.EventTypes,
+		CreatedAt:  s.CreatedAt,
+	}
+}
+
+func toWebhookDeliveryResponse(d database.WebhookDelivery) WebhookDeliveryResponse {
+	r := WebhookDeliveryResponse{
+		ID:            d.ID,
+		EventType:     d.EventType,
+		Payload:       d.Payload,
+		Status:        d.Status,
+		Attempts:      d.Attempts,
+		NextAttemptAt: d.NextAttemptAt,
+		CreatedAt:     d.CreatedAt,
+	}
+	if d.LastStatusCode.Valid {
+		r.LastStatusCode = &d.LastStatusCode.Int32
+	}
+	if d.LastError.Valid {
+		r.LastError = &d.LastError.String
+	}
+	if d.DeliveredAt.Valid {
+		r.DeliveredAt = &d.DeliveredAt.Time
+	}
+	return r
+}
+
+// publicHost rejects URL hosts that are plainly internal: local names and IP
+// literals outside public address space. Names are checked again, once resolved,
+// on every delivery; see webhooks.NewClient.
+func publicHost(host string) bool {
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return webhooks.PublicAddr(ip)
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	return host != "localhost" && !strings.HasSuffix(host, ".localhost")
+}
+
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+
+	var input struct {
+		URL        string   `json:"url"`
+		Secret     string   `json:"secret"`
+		EventTypes []string `json:"event_types"`
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	errs := fieldErrors{}
+	u, err := url.Parse(input.URL)
+	switch {
+	case err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "":
+		errs["url"] = "must be an absolute http or https URL"
+	case !publicHost(u.Hostname()):
+		errs["url"] = "must not point at a local or private address"
+	}
+	if len(input.EventTypes) == 0 {
+		errs["event_types"] = "is required"
+	}
+	for i, t := range input.EventTypes {
+		if !events.Valid(t) {
+			errs["event_types/"+strconv.Itoa(i)] = "unknown event type: " + t
+		}
+	}
+	if len(errs) > 0 {
+		problem.WriteInvalid(w, r, errs)
+		return
+	}
+	if input.Secret == "" {
+		// The dispatcher signs with the secret itself, so it is stored, not its hash.
+		input.Secret, _, err = newSecretToken()
+		if err != nil {
+			problem.Write(w, r, problem.Internal, "failed to generate secret")
+			return
+		}
+	}
+
+	created, err := h.Queries.CreateWebhookSubscription(r.Context(), database.CreateWebhookSubscriptionParams{
+		UserID:     sub,
+		Url:        input.URL,
+		Secret:     input.Secret,
+		EventTypes: input.EventTypes,
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to create webhook")
+		return
+	}
+
+	resp := toWebhookResponse(created)
+	resp.Secret = created.Secret
+	WriteJSON(w, http.StatusCreated, resp)
+}
+
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+
+	subs, err := h.Queries.ListWebhookSubscriptions(r.Context(), sub)
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to retrieve webhooks")
+		return
+	}
+	out := make([]WebhookResponse, len(subs))
+	for i, s := range subs {
+		out[i] = toWebhookResponse(s)
+	}
+	WriteJSON(w, http.StatusOK, out)
+}
+
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidParameter, "invalid id")
+		return
+	}
+
+	n, err := h.Queries.DeleteWebhookSubscription(r.Context(), database.DeleteWebhookSubscriptionParams{
+		ID:     int32(id),
+		UserID: sub,
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to delete webhook")
+		return
+	}
+	if n == 0 {
+		problem.Write(w, r, problem.NotFound, "webhook not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries returns the delivery log for one of the caller's webhooks, newest first.
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	subscription, ok := h.loadSubscription(w, r)
+	if !ok {
+		return
+	}
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidParameter, err.Error())
+		return
+	}
+
+	deliveries, err := h.Queries.ListWebhookDeliveries(r.Context(), database.ListWebhookDeliveriesParams{
+		SubscriptionID: subscription.ID,
+		Limit:          limit,
+		Offset:         offset,
+	})
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to retrieve deliveries")
+		return
+	}
+	out := make([]WebhookDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		out[i] = toWebhookDeliveryResponse(d)
+	}
+	WriteJSON(w, http.StatusOK, out)
+}
+
+// Redeliver queues a new attempt carrying the same payload as an earlier delivery.
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	subscription, ok := h.loadSubscription(w, r)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := strconv.ParseInt(chi.URLParam(r, "deliveryID"), 10, 32)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidParameter, "invalid delivery id")
+		return
+	}
+
+	queued, err := h.Queries.RedeliverWebhookDelivery(r.Context(), database.RedeliverWebhookDeliveryParams{
+		ID:             int32(deliveryID),
+		SubscriptionID: subscription.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		problem.Write(w, r, problem.NotFound, "delivery not found")
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to queue redelivery")
+		return
+	}
+
+	WriteJSON(w, http.StatusAccepted, toWebhookDeliveryResponse(queued))
+}
+
+// loadSubscription resolves the {id} URL parameter to one of the caller's webhooks,
+// writing the appropriate error response and returning ok=false otherwise.
+func (h *WebhookHandler) loadSubscription(w http.ResponseWriter, r *http.Request) (database.WebhookSubscription, bool) {
+	sub, ok := appauth.SubFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.Internal, "missing user context")
+		return database.WebhookSubscription{}, false
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidParameter, "invalid id")
+		return database.WebhookSubscription{}, false
+	}
+
+	subscription, err := h.Queries.GetWebhookSubscription(r.Context(), database.GetWebhookSubscriptionParams{
+		ID:     int32(id),
+		UserID: sub,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		problem.Write(w, r, problem.NotFound, "webhook not found")
+		return database.WebhookSubscription{}, false
+	}
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to retrieve webhook")
+		return database.WebhookSubscription{}, false
+	}
+	return subscription, true
+}
@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dcrespo1/book-list-app/events"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/go-chi/chi/v5"
+)
+
+// fakeWebhookStore is a test double for WebhookStore.
+type fakeWebhookStore struct {
+	subs       []database.WebhookSubscription
+	deliveries []database.WebhookDelivery
+	created    database.CreateWebhookSubscriptionParams
+	createErr  error
+}
+
+func (f *fakeWebhookStore) CreateWebhookSubscription(_ context.Context, arg database.CreateWebhookSubscriptionParams) (database.WebhookSubscription, error) {
+	f.created = arg
+	if f.createErr != nil {
+		return database.WebhookSubscription{}, f.createErr
+	}
+	return database.WebhookSubscription{ID: 1, UserID: arg.UserID, Url: arg.Url, Secret: arg.Secret, EventTypes: arg.EventTypes}, nil
+}
+
+func (f *fakeWebhookStore) ListWebhookSubscriptions(_ context.Context, userID string) ([]database.WebhookSubscription, error) {
+	var out []database.WebhookSubscription
+	for _, s := range f.subs {
+		if s.UserID == userID {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeWebhookStore) GetWebhookSubscription(_ context.Context, arg database.GetWebhookSubscriptionParams) (database.WebhookSubscription, error) {
+	for _, s := range f.subs {
+		if s.ID == arg.ID && s.UserID == arg.UserID {
+			return s, nil
+		}
+	}
+	return database.WebhookSubscription{}, sql.ErrNoRows
+}
+
+func (f *fakeWebhookStore) DeleteWebhookSubscription(_ context.Context, arg database.DeleteWebhookSubscriptionParams) (int64, error) {
+	if _, err := f.GetWebhookSubscription(context.Background(), database.GetWebhookSubscriptionParams(arg)); err != nil {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+func (f *fakeWebhookStore) ListWebhookDeliveries(_ context.Context, arg database.ListWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	var out []database.WebhookDelivery
+	for _, d := range f.deliveries {
+		if d.SubscriptionID == arg.SubscriptionID {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeWebhookStore) RedeliverWebhookDelivery(_ context.Context, arg database.RedeliverWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	for _, d := range f.deliveries {
+		if d.ID == arg.ID && d.SubscriptionID == arg.SubscriptionID {
+			d.ID = 99
+			d.Status = "pending"
+			return d, nil
+		}
+	}
+	return database.WebhookDelivery{}, sql.ErrNoRows
+}
+
+func seedWebhooks() *fakeWebhookStore {
+	return &fakeWebhookStore{
+		subs: []database.WebhookSubscription{
+			{ID: 1, UserID: testSub, Url: "https://hooks.example.com/a", Secret: "s3cret", EventTypes: []string{events.BookAdded}},
+			{ID: 2, UserID: "someone-else", Url: "https://hooks.example.com/b", Secret: "other", EventTypes: []string{events.BookAdded}},
+		},
+		deliveries: []database.WebhookDelivery{
+			{ID: 10, SubscriptionID: 1, EventType: events.BookAdded, Payload: json.RawMessage(`{}`), Status: "failed"},
+		},
+	}
+}
+
+func webhookRequest(method, target, body string, params map[string]string) *http.Request {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	for k, v := range params {
+		rctx.URLParams.Add(k, v)
+	}
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+	return withSub(r, testSub)
+}
+
+func TestCreateWebhook_GeneratesSecret(t *testing.T) {
+	store := &fakeWebhookStore{}
+	h := &WebhookHandler{Queries: store}
+
+	w := httptest.NewRecorder()
+	h.CreateWebhook(w, webhookRequest(http.MethodPost, "/webhooks",
+		`{"url":"https://hooks.example.com/x","event_types":["book.added","book.deleted"]}`, nil))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusCreated)
+	}
+	var got WebhookResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if len(got.Secret) != 64 || got.Secret != store.created.Secret {
+		t.Errorf("expected generated 32-byte hex secret to be returned once, got %q", got.Secret)
+	}
+	if store.created.UserID != testSub {
+		t.Errorf("user id: got %q, want %q", store.created.UserID, testSub)
+	}
+}
+
+func TestCreateWebhook_Validation(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+	}{
+		{"relative url", `{"url":"/hook","event_types":["book.added"]}`},
+		{"bad scheme", `{"url":"ftp://example.com","event_types":["book.added"]}`},
+		{"no events", `{"url":"https://example.com"}`},
+		{"unknown event", `{"url":"https://example.com","event_types":["book.exploded"]}`},
+		{"loopback", `{"url":"http://127.0.0.1:8080/hook","event_types":["book.added"]}`},
+		{"metadata endpoint", `{"url":"http://169.254.169.254/latest/meta-data","event_types":["book.added"]}`},
+		{"private ipv6", `{"url":"http://[fd00::1]/hook","event_types":["book.added"]}`},
+		{"localhost", `{"url":"http://LocalHost./hook","event_types":["book.added"]}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := &WebhookHandler{Queries: &fakeWebhookStore{}}
+			w := httptest.NewRecorder()
+			h.CreateWebhook(w, webhookRequest(http.MethodPost, "/webhooks", tc.body, nil))
+
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Errorf("status: got %d, want %d", w.Code, http.StatusUnprocessableEntity)
+			}
+		})
+	}
+}
+
+func TestCreateWebhook_DBError(t *testing.T) {
+	h := &WebhookHandler{Queries: &fakeWebhookStore{createErr: errors.New("db down")}}
+
+	w := httptest.NewRecorder()
+	h.CreateWebhook(w, webhookRequest(http.MethodPost, "/webhooks",
+		`{"url":"https://hooks.example.com/x","event_types":["book.added"]}`, nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestListWebhooks_HidesSecrets(t *testing.T) {
+	h := &WebhookHandler{Queries: seedWebhooks()}
+
+	w := httptest.NewRecorder()
+	h.ListWebhooks(w, webhookRequest(http.MethodGet, "/webhooks", "", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	if strings.Contains(w.Body.String(), "s3cret") {
+		t.Error("secret leaked into list response")
+	}
+	var got []WebhookResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if len(got) != 1 || got[0].ID != 1 {
+		t.Errorf("expected only the caller's webhook, got %+v", got)
+	}
+}
+
+func TestDeleteWebhook_OtherUsersWebhookNotFound(t *testing.T) {
+	h := &WebhookHandler{Queries: seedWebhooks()}
+
+	w := httptest.NewRecorder()
+	h.DeleteWebhook(w, webhookRequest(http.MethodDelete, "/webhooks/2", "", map[string]string{"id": "2"}))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestListDeliveries(t *testing.T) {
+	h := &WebhookHandler{Queries: seedWebhooks()}
+
+	w := httptest.NewRecorder()
+	h.ListDeliveries(w, webhookRequest(http.MethodGet, "/webhooks/1/deliveries", "", map[string]string{"id": "1"}))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	var got []WebhookDeliveryResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if len(got) != 1 || got[0].ID != 10 || got[0].Status != "failed" {
+		t.Errorf("unexpected deliveries: %+v", got)
+	}
+}
+
+func TestListDeliveries_OtherUsersWebhook(t *testing.T) {
+	h := &WebhookHandler{Queries: seedWebhooks()}
+
+	w := httptest.NewRecorder()
+	h.ListDeliveries(w, webhookRequest(http.MethodGet, "/webhooks/2/deliveries", "", map[string]string{"id": "2"}))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestRedeliver(t *testing.T) {
+	h := &WebhookHandler{Queries: seedWebhooks()}
+
+	w := httptest.NewRecorder()
+	h.Redeliver(w, webhookRequest(http.MethodPost, "/webhooks/1/deliveries/10/redeliver", "",
+		map[string]string{"id": "1", "deliveryID": "10"}))
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusAccepted)
+	}
+	var got WebhookDeliveryResponse
+	json.NewDecoder(w.Body).Decode(&got)
+	if got.ID != 99 || got.Status != "pending" {
+		t.Errorf("unexpected redelivery: %+v", got)
+	}
+}
+
+func TestRedeliver_UnknownDelivery(t *testing.T) {
+	h := &WebhookHandler{Queries: seedWebhooks()}
+
+	w := httptest.NewRecorder()
+	h.Redeliver(w, webhookRequest(http.MethodPost, "/webhooks/1/deliveries/55/redeliver", "",
+		map[string]string{"id": "1", "deliveryID": "55"}))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
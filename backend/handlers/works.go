@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// workIDPattern matches an Open Library work identifier such as OL45804W.
+var workIDPattern = regexp.MustCompile(`^OL[0-9]+W$`)
+
+// maxWorksPerSearch caps how many works GetWorks asks the search index for in one
+// request, keeping the query string well within URL length limits.
+const maxWorksPerSearch = 50
+
+// Work is an Open Library work as its search index describes it.
+type Work struct {
+	ID               string
+	Title            string
+	Authors          []Author
+	FirstPublishYear int
+	Subjects         []string
+	CoverID          int
+	EditionCount     int
+}
+
+type Author struct {
+	ID   string
+	Name string
+}
+
+// Edition is one published edition of a work.
+type Edition struct {
+	ID          string
+	Title       string
+	Publishers  []string
+	PublishDate string
+	ISBN13      []string
+	// Languages are MARC language codes, such as eng.
+	Languages []string
+	Pages     int
+}
+
+// GetWorks looks up to maxWorksPerSearch works with a single search request, so the
+// metadata for a page of readlist entries costs one upstream call. Works Open
+// Library does not know, and IDs that are not work IDs, are missing from the result.
+func (h *BookHandler) GetWorks(ctx context.Context, ids []string) (map[string]Work, error) {
+	if len(ids) > maxWorksPerSearch {
+		return nil, fmt.Errorf("at most %d works can be fetched at once", maxWorksPerSearch)
+	}
+	var keys []string
+	for _, id := range ids {
+		if workIDPattern.MatchString(id) {
+			keys = append(keys, `"/works/`+id+`"`)
+		}
+	}
+	works := map[string]Work{}
+	if len(keys) == 0 {
+		return works, nil
+	}
+
+	params := url.Values{
+		"q":      {"key:(" + strings.Join(keys, " OR ") + ")"},
+		"fields": {"key,title,author_name,author_key,first_publish_year,subject,cover_i,edition_count"},
+		"limit":  {strconv.Itoa(len(keys))},
+	}
+	var result struct {
+		Docs []struct {
+			Key              string   `json:"key"`
+			Title            string   `json:"title"`
+			AuthorName       []string `json:"author_name"`
+			AuthorKey        []string `json:"author_key"`
+			FirstPublishYear int      `json:"first_publish_year"`
+			Subject          []string `json:"subject"`
+			CoverID          int      `json:"cover_i"`
+			EditionCount     int      `json:"edition_count"`
+		} `json:"docs"`
+	}
+	if err := h.getJSON(ctx, "/search.json?"+params.Encode(), &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch works: %w", err)
+	}
+
+	for _, doc := range result.Docs {
+		id := strings.TrimPrefix(doc.Key, "/works/")
+		w := Work{
+			ID:               id,
+			Title:            doc.Title,
+			FirstPublishYear: doc.FirstPublishYear,
+			Subjects:         doc.Subject,
+			CoverID:          doc.CoverID,
+			EditionCount:     doc.EditionCount,
+		}
+		for i, name := range doc.AuthorName {
+			a := Author{Name: name}
+			if i < len(doc.AuthorKey) {
+				a.ID = doc.AuthorKey[i]
+			}
+			w.Authors = append(w.Authors, a)
+		}
+		works[id] = w
+	}
+	return works, nil
+}
+
+// GetEditions returns up to limit editions of a work. Open Library has no endpoint
+// for the editions of several works at once.
+func (h *BookHandler) GetEditions(ctx context.Context, workID string, limit int) ([]Edition, error) {
+	var result struct {
+		Entries []struct {
+			Key         string   `json:"key"`
+			Title       string   `json:"title"`
+			Publishers  []string `json:"publishers"`
+			PublishDate string   `json:"publish_date"`
+			ISBN13      []string `json:"isbn_13"`
+			Languages   []struct {
+				Key string `json:"key"`
+			} `json:"languages"`
+			Pages int `json:"number_of_pages"`
+		} `json:"entries"`
+	}
+	path := fmt.Sprintf("/works/%s/editions.json?limit=%d", url.PathEscape(workID), limit)
+	if err := h.getJSON(ctx, path, &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch editions: %w", err)
+	}
+
+	editions := make([]Edition, 0, len(result.Entries))
+	for _, e := range result.Entries {
+		ed := Edition{
+			ID:          strings.TrimPrefix(e.Key, "/books/"),
+			Title:       e.Title,
+			Publishers:  e.Publishers,
+			PublishDate: e.PublishDate,
+			ISBN13:      e.ISBN13,
+			Pages:       e.Pages,
+		}
+		for _, l := range e.Languages {
+			ed.Languages = append(ed.Languages, strings.TrimPrefix(l.Key, "/languages/"))
+		}
+		editions = append(editions, ed)
+	}
+	return editions, nil
+}
+
+// getJSON fetches path from Open Library and decodes the JSON response into dst.
+func (h *BookHandler) getJSON(ctx context.Context, path string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.openLibraryURL()+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response code: %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return nil
+}
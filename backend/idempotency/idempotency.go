@@ -0,0 +1,207 @@
+// Package idempotency lets clients safely retry POST requests. A request carrying an
+// Idempotency-Key header is handled once per user and key; retries with the same key
+// and body receive the stored response instead of running the handler again.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/problem"
+)
+
+const (
+	// Header is the request header carrying the client's key.
+	Header = "Idempotency-Key"
+	// ReplayedHeader is set to "true" on responses served from the store.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+)
+
+// Store is the persistence interface for idempotency keys. *database.Queries satisfies it.
+type Store interface {
+	ClaimIdempotencyKey(ctx context.Context, arg database.ClaimIdempotencyKeyParams) (int64, error)
+	GetIdempotencyKey(ctx context.Context, arg database.GetIdempotencyKeyParams) (database.IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, arg database.CompleteIdempotencyKeyParams) error
+	ReleaseIdempotencyKey(ctx context.Context, arg database.ReleaseIdempotencyKeyParams) error
+}
+
+// Middleware applies Idempotency-Key handling to POST requests from authenticated
+// users; it must run after auth.AuthMiddleware. Stored responses are kept for ttl.
+// Server errors are not stored, so the client can retry them with the same key.
+// Neither are responses marked Cache-Control: no-store, which handlers use for
+// bodies carrying secrets such as freshly issued tokens; a retry runs the handler
+// again.
+func Middleware(store Store, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(Header)
+			sub, ok := appauth.SubFromContext(r.Context())
+			if r.Method != http.MethodPost || key == "" || !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxKeyLength {
+				problem.Write(w, r, problem.InvalidParameter, "Idempotency-Key must be at most 255 characters")
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				problem.Write(w, r, problem.InvalidBody, "invalid request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			hash := requestHash(r, body)
+
+			n, err := store.ClaimIdempotencyKey(r.Context(), database.ClaimIdempotencyKeyParams{
+				UserID:      sub,
+				Key:         key,
+				RequestHash: hash,
+				ExpiresAt:   time.Now().Add(ttl),
+			})
+			if err != nil {
+				problem.Write(w, r, problem.Internal, "failed to record idempotency key")
+				return
+			}
+			if n == 0 {
+				replay(w, r, store, sub, key, hash)
+				return
+			}
+
+			// A panicking handler must not leave the key in flight until it expires:
+			// release it so the client can retry, then let the panic carry on up.
+			completed := false
+			defer func() {
+				if completed {
+					return
+				}
+				ctx := context.WithoutCancel(r.Context())
+				if err := store.ReleaseIdempotencyKey(ctx, database.ReleaseIdempotencyKeyParams{UserID: sub, Key: key}); err != nil {
+					slog.Error("failed to release idempotency key", "error", err)
+				}
+			}()
+
+			rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			completed = true
+
+			// The response has been sent; use a context the client cannot cancel.
+			ctx := context.WithoutCancel(r.Context())
+			if rec.status >= 500 || noStore(rec.header) {
+				err = store.ReleaseIdempotencyKey(ctx, database.ReleaseIdempotencyKeyParams{UserID: sub, Key: key})
+			} else {
+				headers, _ := json.Marshal(rec.header)
+				err = store.CompleteIdempotencyKey(ctx, database.CompleteIdempotencyKeyParams{
+					StatusCode:      sql.NullInt32{Int32: int32(rec.status), Valid: true},
+					ResponseHeaders: headers,
+					ResponseBody:    rec.body.Bytes(),
+					UserID:          sub,
+					Key:             key,
+				})
+			}
+			if err != nil {
+				slog.Error("failed to store idempotent response", "error", err)
+			}
+		})
+	}
+}
+
+// replay answers a request whose key is already held: with the stored response, or
+// with an error if the key was used for a different request or is still in flight.
+func replay(w http.ResponseWriter, r *http.Request, store Store, sub, key, hash string) {
+	stored, err := store.GetIdempotencyKey(r.Context(), database.GetIdempotencyKeyParams{UserID: sub, Key: key})
+	if errors.Is(err, sql.ErrNoRows) {
+		// Released by a failed first attempt between our claim and this read.
+		w.Header().Set("Retry-After", "1")
+		problem.Write(w, r, problem.IdempotencyInProgress, "request with this Idempotency-Key is still in progress")
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, problem.Internal, "failed to read idempotency key")
+		return
+	}
+	if stored.RequestHash != hash {
+		problem.Write(w, r, problem.IdempotencyKeyReused, "Idempotency-Key has already been used for a different request")
+		return
+	}
+	if !stored.StatusCode.Valid {
+		w.Header().Set("Retry-After", "1")
+		problem.Write(w, r, problem.IdempotencyInProgress, "request with this Idempotency-Key is still in progress")
+		return
+	}
+
+	var headers http.Header
+	json.Unmarshal(stored.ResponseHeaders, &headers)
+	for k, v := range headers {
+		w.Header()[k] = v
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(int(stored.StatusCode.Int32))
+	w.Write(stored.ResponseBody)
+}
+
+// noStore reports whether a response forbids keeping a copy of it.
+func noStore(header http.Header) bool {
+	for _, v := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// requestHash identifies a request by method, path and body, so a key reused for a
+// different route or payload is detected.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.Path+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recorder passes a response through while keeping a copy for the store.
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	header      http.Header
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	if rec.wroteHeader {
+		return
+	}
+	rec.wroteHeader = true
+	rec.status = status
+	rec.header = rec.ResponseWriter.Header().Clone()
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	if !rec.wroteHeader {
+		rec.WriteHeader(http.StatusOK)
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+func (rec *recorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
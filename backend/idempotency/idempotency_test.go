@@ -0,0 +1,239 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+)
+
+const testSub = "user-sub-abc123"
+
+// fakeStore is an in-memory Store.
+type fakeStore struct {
+	keys map[string]database.IdempotencyKey
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{keys: map[string]database.IdempotencyKey{}}
+}
+
+func (f *fakeStore) ClaimIdempotencyKey(_ context.Context, arg database.ClaimIdempotencyKeyParams) (int64, error) {
+	id := arg.UserID + "/" + arg.Key
+	if k, ok := f.keys[id]; ok && k.ExpiresAt.After(time.Now()) {
+		return 0, nil
+	}
+	f.keys[id] = database.IdempotencyKey{UserID: arg.UserID, Key: arg.Key, RequestHash: arg.RequestHash, ExpiresAt: arg.ExpiresAt}
+	return 1, nil
+}
+
+func (f *fakeStore) GetIdempotencyKey(_ context.Context, arg database.GetIdempotencyKeyParams) (database.IdempotencyKey, error) {
+	k, ok := f.keys[arg.UserID+"/"+arg.Key]
+	if !ok {
+		return database.IdempotencyKey{}, sql.ErrNoRows
+	}
+	return k, nil
+}
+
+func (f *fakeStore) CompleteIdempotencyKey(_ context.Context, arg database.CompleteIdempotencyKeyParams) error {
+	id := arg.UserID + "/" + arg.Key
+	k := f.keys[id]
+	k.StatusCode, k.ResponseHeaders, k.ResponseBody = arg.StatusCode, arg.ResponseHeaders, arg.ResponseBody
+	f.keys[id] = k
+	return nil
+}
+
+func (f *fakeStore) ReleaseIdempotencyKey(_ context.Context, arg database.ReleaseIdempotencyKeyParams) error {
+	delete(f.keys, arg.UserID+"/"+arg.Key)
+	return nil
+}
+
+// countingHandler creates a resource per call and reports how many it has created.
+type countingHandler struct {
+	calls  int
+	status int
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.calls++
+	io.ReadAll(r.Body)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/readlist/42")
+	status := h.status
+	if status == 0 {
+		status = http.StatusCreated
+	}
+	w.WriteHeader(status)
+	w.Write([]byte(`{"id":42}`))
+}
+
+func send(h http.Handler, method, key, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "/readlist", strings.NewReader(body))
+	if key != "" {
+		r.Header.Set(Header, key)
+	}
+	r = r.WithContext(appauth.SubToContext(r.Context(), testSub))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestMiddleware_ReplaysStoredResponse(t *testing.T) {
+	next := &countingHandler{}
+	h := Middleware(newFakeStore(), time.Hour)(next)
+
+	first := send(h, http.MethodPost, "k1", `{"work_id":"OL1W"}`)
+	second := send(h, http.MethodPost, "k1", `{"work_id":"OL1W"}`)
+
+	if next.calls != 1 {
+		t.Errorf("handler calls: got %d, want 1", next.calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Errorf("replay: got %d %q, want %d %q", second.Code, second.Body.String(), first.Code, first.Body.String())
+	}
+	if second.Header().Get("Location") != "/readlist/42" || second.Header().Get(ReplayedHeader) != "true" {
+		t.Errorf("unexpected replay headers: %v", second.Header())
+	}
+	if first.Header().Get(ReplayedHeader) != "" {
+		t.Error("first response must not be marked as replayed")
+	}
+}
+
+func TestMiddleware_DifferentBodyRejected(t *testing.T) {
+	next := &countingHandler{}
+	h := Middleware(newFakeStore(), time.Hour)(next)
+
+	send(h, http.MethodPost, "k1", `{"work_id":"OL1W"}`)
+	w := send(h, http.MethodPost, "k1", `{"work_id":"OL2W"}`)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+	if next.calls != 1 {
+		t.Errorf("handler calls: got %d, want 1", next.calls)
+	}
+}
+
+func TestMiddleware_InFlightConflict(t *testing.T) {
+	store := newFakeStore()
+	store.ClaimIdempotencyKey(context.Background(), database.ClaimIdempotencyKeyParams{
+		UserID:      testSub,
+		Key:         "k1",
+		RequestHash: requestHash(httptest.NewRequest(http.MethodPost, "/readlist", nil), []byte(`{}`)),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	h := Middleware(store, time.Hour)(&countingHandler{})
+
+	w := send(h, http.MethodPost, "k1", `{}`)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusConflict)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header")
+	}
+}
+
+func TestMiddleware_ServerErrorReleasesKey(t *testing.T) {
+	next := &countingHandler{status: http.StatusInternalServerError}
+	h := Middleware(newFakeStore(), time.Hour)(next)
+
+	send(h, http.MethodPost, "k1", `{}`)
+	next.status = 0
+	w := send(h, http.MethodPost, "k1", `{}`)
+
+	if w.Code != http.StatusCreated || next.calls != 2 {
+		t.Errorf("expected retry to run the handler again, got %d after %d calls", w.Code, next.calls)
+	}
+}
+
+func TestMiddleware_NoStoreResponseNotRecorded(t *testing.T) {
+	store := newFakeStore()
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "private, no-store")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"token":"secret"}`))
+	})
+	h := Middleware(store, time.Hour)(next)
+
+	w := send(h, http.MethodPost, "k1", `{}`)
+
+	if w.Code != http.StatusCreated || w.Body.String() != `{"token":"secret"}` {
+		t.Errorf("response: got %d %q", w.Code, w.Body.String())
+	}
+	if len(store.keys) != 0 {
+		t.Errorf("expected the key to be released, got %+v", store.keys)
+	}
+}
+
+func TestMiddleware_PanicReleasesKey(t *testing.T) {
+	store := newFakeStore()
+	h := Middleware(store, time.Hour)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("handler bug")
+	}))
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the panic to propagate")
+			}
+		}()
+		send(h, http.MethodPost, "k1", `{}`)
+	}()
+
+	if len(store.keys) != 0 {
+		t.Errorf("expected the key to be released, got %+v", store.keys)
+	}
+}
+
+func TestMiddleware_ExpiredKeyIsReused(t *testing.T) {
+	next := &countingHandler{}
+	h := Middleware(newFakeStore(), -time.Second)(next)
+
+	send(h, http.MethodPost, "k1", `{}`)
+	send(h, http.MethodPost, "k1", `{"different":true}`)
+
+	if next.calls != 2 {
+		t.Errorf("handler calls: got %d, want 2", next.calls)
+	}
+}
+
+func TestMiddleware_PassThrough(t *testing.T) {
+	cases := []struct {
+		name, method, key string
+	}{
+		{"no key", http.MethodPost, ""},
+		{"not a post", http.MethodPatch, "k1"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			next := &countingHandler{}
+			store := newFakeStore()
+			h := Middleware(store, time.Hour)(next)
+
+			send(h, tc.method, tc.key, `{}`)
+			send(h, tc.method, tc.key, `{}`)
+
+			if next.calls != 2 || len(store.keys) != 0 {
+				t.Errorf("expected no idempotency handling, got %d calls and %d keys", next.calls, len(store.keys))
+			}
+		})
+	}
+}
+
+func TestMiddleware_KeyTooLong(t *testing.T) {
+	h := Middleware(newFakeStore(), time.Hour)(&countingHandler{})
+
+	w := send(h, http.MethodPost, strings.Repeat("k", maxKeyLength+1), `{}`)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
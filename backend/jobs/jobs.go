@@ -0,0 +1,233 @@
+// Package jobs holds periodic maintenance tasks started from main. Each task
+// runs once immediately and then on a fixed interval until its context ends.
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/dcrespo1/book-list-app/events"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/stream"
+	"github.com/dcrespo1/book-list-app/webhooks"
+)
+
+// Every runs fn immediately and then every interval until ctx is cancelled,
+// logging failures under name.
+func Every(ctx context.Context, name string, interval time.Duration, fn func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := fn(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("job failed", "job", name, "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// TrashPurger is the persistence interface used by PurgeTrash. *database.Queries satisfies it.
+type TrashPurger interface {
+	PurgeTrashedBooks(ctx context.Context, deletedAt sql.NullTime) (int64, error)
+}
+
+// PurgeTrash returns a job that permanently deletes readlist entries that have been
+// in the trash for longer than retention. Entries lent out and not yet returned are
+// kept until their loan is closed.
+func PurgeTrash(store TrashPurger, retention time.Duration) func(context.Context) error {
+	return func(ctx context.Context) error {
+		cutoff := sql.NullTime{Time: time.Now().Add(-retention), Valid: true}
+		n, err := store.PurgeTrashedBooks(ctx, cutoff)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			slog.Info("purged trashed books", "count", n)
+		}
+		return nil
+	}
+}
+
+// IdempotencyKeyExpirer is the persistence interface used by ExpireIdempotencyKeys. *database.Queries satisfies it.
+type IdempotencyKeyExpirer interface {
+	DeleteExpiredIdempotencyKeys(ctx context.Context, expiresAt time.Time) (int64, error)
+}
+
+// ExpireIdempotencyKeys returns a job that deletes idempotency keys past their TTL.
+func ExpireIdempotencyKeys(store IdempotencyKeyExpirer) func(context.Context) error {
+	return func(ctx context.Context) error {
+		n, err := store.DeleteExpiredIdempotencyKeys(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			slog.Info("expired idempotency keys", "count", n)
+		}
+		return nil
+	}
+}
+
+// RateLimitBucketPruner is the persistence interface used by PruneRateLimitBuckets. *database.Queries satisfies it.
+type RateLimitBucketPruner interface {
+	DeleteIdleRateLimitBuckets(ctx context.Context, updatedAt time.Time) (int64, error)
+}
+
+// PruneRateLimitBuckets returns a job that deletes rate limit buckets unused for
+// longer than idle. A bucket idle for the longest limit period has refilled, so
+// deleting it loses nothing.
+func PruneRateLimitBuckets(store RateLimitBucketPruner, idle time.Duration) func(context.Context) error {
+	return func(ctx context.Context) error {
+		n, err := store.DeleteIdleRateLimitBuckets(ctx, time.Now().Add(-idle))
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			slog.Info("pruned rate limit buckets", "count", n)
+		}
+		return nil
+	}
+}
+
+// AccountPurger is the persistence interface used by PurgeDeletedAccounts.
+// *users.DBStore satisfies it.
+type AccountPurger interface {
+	ListDueAccountDeletions(ctx context.Context, deletionScheduledFor sql.NullTime) ([]string, error)
+	PurgeAccount(ctx context.Context, userID string) error
+}
+
+// PurgeDeletedAccounts returns a job that purges the accounts whose deletion grace
+// period has ended. Each account is purged in its own transaction, so one failure
+// does not hold back the others.
+func PurgeDeletedAccounts(store AccountPurger) func(context.Context) error {
+	return func(ctx context.Context) error {
+		due, err := store.ListDueAccountDeletions(ctx, sql.NullTime{Time: time.Now(), Valid: true})
+		if err != nil {
+			return err
+		}
+		var errs []error
+		for _, userID := range due {
+			if err := store.PurgeAccount(ctx, userID); err != nil {
+				errs = append(errs, fmt.Errorf("purge account %s: %w", userID, err))
+				continue
+			}
+			slog.Info("purged deleted account", "user_id", userID)
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// OverdueLoanFlagger is the persistence interface used by FlagOverdueLoans.
+// *DBOverdueLoanFlagger satisfies it.
+type OverdueLoanFlagger interface {
+	ListOverdueLoans(ctx context.Context, now time.Time) ([]database.ListOverdueLoansRow, error)
+	// WithinTx runs fn with a LoanTx bound to a transaction, which is committed if fn
+	// returns nil and rolled back otherwise.
+	WithinTx(ctx context.Context, fn func(LoanTx) error) error
+}
+
+// LoanTx claims a loan's loan.overdue event and records the event, in one
+// transaction. *database.Queries satisfies it.
+type LoanTx interface {
+	FlagLoanOverdue(ctx context.Context, id int32) (int64, error)
+	webhooks.Enqueuer
+	stream.Appender
+}
+
+// DBOverdueLoanFlagger implements OverdueLoanFlagger over the generated queries and
+// their database.
+type DBOverdueLoanFlagger struct {
+	*database.Queries
+	DB *sql.DB
+}
+
+func (s *DBOverdueLoanFlagger) WithinTx(ctx context.Context, fn func(LoanTx) error) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(s.Queries.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// OverdueLoan is the data of a loan.overdue event.
+type OverdueLoan struct {
+	ID             int32   `json:"id"`
+	BookID         int32   `json:"book_id"`
+	WorkID         string  `json:"work_id"`
+	Title          string  `json:"title"`
+	BorrowerName   *string `json:"borrower_name"`
+	BorrowerUserID *string `json:"borrower_user_id"`
+	LentOn         string  `json:"lent_on"`
+	DueOn          string  `json:"due_on"`
+}
+
+// FlagOverdueLoans returns a job that emits a loan.overdue event for each loan that
+// has passed its due date. Each loan is flagged and its event published through
+// publisher in one transaction, so the event is sent once even when several
+// instances run the job: a loan another instance has flagged is skipped, and one
+// whose event could not be published stays unflagged and is retried on the next run.
+func FlagOverdueLoans(store OverdueLoanFlagger, publisher func(tx LoanTx) events.Publisher) func(context.Context) error {
+	return func(ctx context.Context) error {
+		loans, err := store.ListOverdueLoans(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		var (
+			errs    []error
+			flagged int
+		)
+		for _, l := range loans {
+			data := OverdueLoan{
+				ID:     l.ID,
+				BookID: l.BookID,
+				WorkID: l.WorkID,
+				Title:  l.Title,
+				LentOn: l.LentOn.Format(time.DateOnly),
+				DueOn:  l.DueOn.Time.Format(time.DateOnly),
+			}
+			if l.BorrowerName.Valid {
+				data.BorrowerName = &l.BorrowerName.String
+			}
+			if l.BorrowerUserID.Valid {
+				data.BorrowerUserID = &l.BorrowerUserID.String
+			}
+
+			claimed := false
+			err := store.WithinTx(ctx, func(tx LoanTx) error {
+				n, err := tx.FlagLoanOverdue(ctx, l.ID)
+				if err != nil || n == 0 {
+					return err
+				}
+				claimed = true
+				return publisher(tx).Publish(ctx, events.Event{
+					Type:       events.LoanOverdue,
+					UserID:     l.UserID,
+					OccurredAt: time.Now().UTC(),
+					Data:       data,
+				})
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("loan %d: %w", l.ID, err))
+				continue
+			}
+			if claimed {
+				flagged++
+			}
+		}
+		if flagged > 0 {
+			slog.Info("flagged overdue loans", "count", flagged)
+		}
+		return errors.Join(errs...)
+	}
+}
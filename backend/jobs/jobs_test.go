@@ -0,0 +1,234 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dcrespo1/book-list-app/events"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+)
+
+type fakePurger struct {
+	cutoff sql.NullTime
+	err    error
+}
+
+func (f *fakePurger) PurgeTrashedBooks(_ context.Context, deletedAt sql.NullTime) (int64, error) {
+	f.cutoff = deletedAt
+	return 2, f.err
+}
+
+func TestPurgeTrash_UsesRetentionCutoff(t *testing.T) {
+	store := &fakePurger{}
+	before := time.Now()
+	if err := PurgeTrash(store, 48*time.Hour)(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := before.Add(-48 * time.Hour)
+	if !store.cutoff.Valid || store.cutoff.Time.Before(want) || store.cutoff.Time.After(want.Add(time.Second)) {
+		t.Errorf("cutoff: got %v, want ~%v", store.cutoff.Time, want)
+	}
+}
+
+func TestPurgeTrash_PropagatesError(t *testing.T) {
+	store := &fakePurger{err: errors.New("db down")}
+	if err := PurgeTrash(store, time.Hour)(context.Background()); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestEvery_RunsImmediatelyAndStopsOnCancel(t *testing.T) {
+	var runs atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		Every(ctx, "test", time.Millisecond, func(context.Context) error {
+			if runs.Add(1) == 3 {
+				cancel()
+			}
+			return nil
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Every did not return after cancel")
+	}
+	if runs.Load() < 3 {
+		t.Errorf("runs: got %d, want at least 3", runs.Load())
+	}
+}
+
+type fakeExpirer struct {
+	before time.Time
+}
+
+func (f *fakeExpirer) DeleteExpiredIdempotencyKeys(_ context.Context, expiresAt time.Time) (int64, error) {
+	f.before = expiresAt
+	return 1, nil
+}
+
+func TestExpireIdempotencyKeys_UsesNow(t *testing.T) {
+	store := &fakeExpirer{}
+	before := time.Now()
+	if err := ExpireIdempotencyKeys(store)(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.before.Before(before) || store.before.After(time.Now()) {
+		t.Errorf("cutoff: got %v, want now", store.before)
+	}
+}
+
+type fakeBucketPruner struct {
+	before time.Time
+}
+
+func (f *fakeBucketPruner) DeleteIdleRateLimitBuckets(_ context.Context, updatedAt time.Time) (int64, error) {
+	f.before = updatedAt
+	return 3, nil
+}
+
+func TestPruneRateLimitBuckets_UsesIdleCutoff(t *testing.T) {
+	store := &fakeBucketPruner{}
+	before := time.Now()
+	if err := PruneRateLimitBuckets(store, time.Minute)(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := before.Add(-time.Minute)
+	if store.before.Before(want) || store.before.After(want.Add(time.Second)) {
+		t.Errorf("cutoff: got %v, want ~%v", store.before, want)
+	}
+}
+
+type fakeAccountPurger struct {
+	due    []string
+	failOn string
+	purged []string
+}
+
+func (f *fakeAccountPurger) ListDueAccountDeletions(_ context.Context, _ sql.NullTime) ([]string, error) {
+	return f.due, nil
+}
+
+func (f *fakeAccountPurger) PurgeAccount(_ context.Context, userID string) error {
+	if userID == f.failOn {
+		return errors.New("db down")
+	}
+	f.purged = append(f.purged, userID)
+	return nil
+}
+
+func TestPurgeDeletedAccounts_ContinuesPastFailures(t *testing.T) {
+	store := &fakeAccountPurger{due: []string{"a", "b", "c"}, failOn: "b"}
+	err := PurgeDeletedAccounts(store)(context.Background())
+
+	if err == nil {
+		t.Error("expected the failed purge to be reported")
+	}
+	if len(store.purged) != 2 || store.purged[0] != "a" || store.purged[1] != "c" {
+		t.Errorf("purged: got %v, want [a c]", store.purged)
+	}
+}
+
+// fakeLoanFlagger is a test double for OverdueLoanFlagger. A transaction whose fn
+// fails leaves flagged as it was.
+type fakeLoanFlagger struct {
+	overdue []database.ListOverdueLoansRow
+	flagged []int32
+}
+
+func (f *fakeLoanFlagger) ListOverdueLoans(_ context.Context, _ time.Time) ([]database.ListOverdueLoansRow, error) {
+	return f.overdue, nil
+}
+
+func (f *fakeLoanFlagger) WithinTx(_ context.Context, fn func(LoanTx) error) error {
+	before := len(f.flagged)
+	err := fn(f)
+	if err != nil {
+		f.flagged = f.flagged[:before]
+	}
+	return err
+}
+
+func (f *fakeLoanFlagger) FlagLoanOverdue(_ context.Context, id int32) (int64, error) {
+	if slices.Contains(f.flagged, id) {
+		return 0, nil
+	}
+	f.flagged = append(f.flagged, id)
+	return 1, nil
+}
+
+func (f *fakeLoanFlagger) EnqueueWebhookDeliveries(_ context.Context, _ database.EnqueueWebhookDeliveriesParams) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeLoanFlagger) InsertReadlistEvent(_ context.Context, _ database.InsertReadlistEventParams) (int64, error) {
+	return 0, nil
+}
+
+type fakePublisher struct {
+	failOn int32
+	events []events.Event
+}
+
+func (f *fakePublisher) Publish(_ context.Context, e events.Event) error {
+	if e.Data.(OverdueLoan).ID == f.failOn {
+		return errors.New("publish failed")
+	}
+	f.events = append(f.events, e)
+	return nil
+}
+
+// bind is a FlagOverdueLoans publisher that publishes every transaction's events to f.
+func (f *fakePublisher) bind(LoanTx) events.Publisher {
+	return f
+}
+
+func TestFlagOverdueLoans_FlagsOnlyPublishedLoans(t *testing.T) {
+	due := sql.NullTime{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	store := &fakeLoanFlagger{overdue: []database.ListOverdueLoansRow{
+		{ID: 1, UserID: "a", Title: "Dune", DueOn: due},
+		{ID: 2, UserID: "b", Title: "Emma", DueOn: due},
+	}}
+	publisher := &fakePublisher{failOn: 2}
+
+	err := FlagOverdueLoans(store, publisher.bind)(context.Background())
+
+	if err == nil {
+		t.Error("expected the failed publish to be reported")
+	}
+	if len(store.flagged) != 1 || store.flagged[0] != 1 {
+		t.Errorf("flagged: got %v, want [1]", store.flagged)
+	}
+	if len(publisher.events) != 1 || publisher.events[0].Type != events.LoanOverdue || publisher.events[0].UserID != "a" {
+		t.Errorf("events: got %+v", publisher.events)
+	}
+	if got := publisher.events[0].Data.(OverdueLoan).DueOn; got != "2026-01-01" {
+		t.Errorf("due_on: got %q, want 2026-01-01", got)
+	}
+}
+
+func TestFlagOverdueLoans_PublishesOnlyClaimedLoans(t *testing.T) {
+	// Two instances listed the same loan before either flagged it.
+	due := sql.NullTime{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	store := &fakeLoanFlagger{overdue: []database.ListOverdueLoansRow{{ID: 1, UserID: "a", DueOn: due}}}
+	publisher := &fakePublisher{}
+
+	for range 2 {
+		if err := FlagOverdueLoans(store, publisher.bind)(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(publisher.events) != 1 {
+		t.Errorf("events: got %d, want 1", len(publisher.events))
+	}
+}
@@ -0,0 +1,31 @@
+// Package openapi embeds the API's OpenAPI 3.1 description, serves it with a docs
+// page, and validates traffic against it.
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+//go:embed openapi.json
+var spec []byte
+
+//go:embed docs.html
+var docs []byte
+
+// Spec returns the OpenAPI document as JSON.
+func Spec() []byte {
+	return spec
+}
+
+// ServeSpec serves GET /openapi.json.
+func ServeSpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(spec)
+}
+
+// ServeDocs serves GET /docs, a page that renders /openapi.json.
+func ServeDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(docs)
+}
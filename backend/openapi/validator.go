@@ -0,0 +1,626 @@
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dcrespo1/book-list-app/problem"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// specURL names the spec to the schema compiler. Schemas are compiled from JSON
+// Pointers into it.
+const specURL = "mem://openapi.json"
+
+// maxBodyBytes matches the handlers' cap on request bodies. Larger bodies are left
+// to the handler to reject.
+const maxBodyBytes = 1 << 20
+
+var methods = []string{"get", "put", "post", "delete", "patch"}
+
+// Validator checks requests and responses against the spec. Paths are matched
+// under their server URL, so the API's operations are found under /v1; paths the
+// spec does not describe, including the deprecated unversioned aliases, are not
+// checked.
+type Validator struct {
+	ops []*operation
+}
+
+type operation struct {
+	id     string
+	method string
+	// segments is the path template split on "/"; parameters are "{name}".
+	segments []string
+	params   []parameter
+	body     map[string]*jsonschema.Schema // by media type
+	// responses maps a status, or "default", to its schemas by media type. A nil
+	// schema means the body is not checked; an empty map means no body.
+	responses map[string]map[string]*jsonschema.Schema
+}
+
+type parameter struct {
+	name     string
+	in       string
+	required bool
+	// typ is the JSON type the raw string is converted to before validation.
+	typ    string
+	schema *jsonschema.Schema
+}
+
+// Load compiles every schema in the embedded spec.
+func Load() (*Validator, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(spec))
+	if err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+	c := jsonschema.NewCompiler()
+	c.DefaultDraft(jsonschema.Draft2020)
+	c.AssertFormat()
+	if err := c.AddResource(specURL, doc); err != nil {
+		return nil, fmt.Errorf("add spec: %w", err)
+	}
+	l := loader{root: object(doc), compiler: c}
+
+	v := &Validator{}
+	base := serverPath(l.root["servers"])
+	for path, item := range object(l.root["paths"]) {
+		prefix := base
+		if servers, ok := object(item)["servers"]; ok {
+			prefix = serverPath(servers)
+		}
+		for _, method := range methods {
+			o, ok := object(item)[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			op, err := l.operation(prefix, path, method, o)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(method), path, err)
+			}
+			v.ops = append(v.ops, op)
+		}
+	}
+	// Literal segments win over parameters, so /readlist/events is matched before
+	// /readlist/{id}.
+	slices.SortFunc(v.ops, func(a, b *operation) int {
+		for i := range min(len(a.segments), len(b.segments)) {
+			ap, bp := isParam(a.segments[i]), isParam(b.segments[i])
+			if ap != bp {
+				if ap {
+					return 1
+				}
+				return -1
+			}
+		}
+		return len(a.segments) - len(b.segments)
+	})
+	return v, nil
+}
+
+type loader struct {
+	root     map[string]any
+	compiler *jsonschema.Compiler
+}
+
+// operation compiles the operation at path, which is served under prefix.
+func (l loader) operation(prefix, path, method string, o map[string]any) (*operation, error) {
+	opPtr := "/paths/" + escape(path) + "/" + method
+	op := &operation{
+		method:    strings.ToUpper(method),
+		segments:  strings.Split(strings.Trim(prefix+path, "/"), "/"),
+		responses: map[string]map[string]*jsonschema.Schema{},
+	}
+	op.id, _ = o["operationId"].(string)
+
+	for i, p := range array(o["parameters"]) {
+		ptr, p := l.resolve(opPtr+"/parameters/"+strconv.Itoa(i), object(p))
+		schema, err := l.compile(ptr + "/schema")
+		if err != nil {
+			return nil, err
+		}
+		param := parameter{schema: schema, typ: l.jsonType(object(p["schema"]))}
+		param.name, _ = p["name"].(string)
+		param.in, _ = p["in"].(string)
+		param.required, _ = p["required"].(bool)
+		if param.in == "header" {
+			param.name = http.CanonicalHeaderKey(param.name)
+		}
+		op.params = append(op.params, param)
+	}
+
+	if b, ok := o["requestBody"].(map[string]any); ok {
+		ptr, b := l.resolve(opPtr+"/requestBody", b)
+		content, err := l.content(ptr, b)
+		if err != nil {
+			return nil, err
+		}
+		op.body = content
+	}
+
+	for status, res := range object(o["responses"]) {
+		ptr, res := l.resolve(opPtr+"/responses/"+status, object(res))
+		content, err := l.content(ptr, res)
+		if err != nil {
+			return nil, err
+		}
+		op.responses[status] = content
+	}
+	return op, nil
+}
+
+// content compiles the schema of each media type in a request body or response.
+func (l loader) content(ptr string, o map[string]any) (map[string]*jsonschema.Schema, error) {
+	out := map[string]*jsonschema.Schema{}
+	for media, m := range object(o["content"]) {
+		out[media] = nil
+		if _, ok := object(m)["schema"]; !ok || !isJSON(media) {
+			continue
+		}
+		schema, err := l.compile(ptr + "/content/" + escape(media) + "/schema")
+		if err != nil {
+			return nil, err
+		}
+		out[media] = schema
+	}
+	return out, nil
+}
+
+func (l loader) compile(ptr string) (*jsonschema.Schema, error) {
+	return l.compiler.Compile(specURL + "#" + ptr)
+}
+
+// resolve follows a local $ref, returning the target and its pointer.
+func (l loader) resolve(ptr string, o map[string]any) (string, map[string]any) {
+	for {
+		ref, ok := o["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return ptr, o
+		}
+		ptr, o = ref[1:], l.root
+		for _, key := range strings.Split(ptr[1:], "/") {
+			o = object(o[unescape(key)])
+		}
+	}
+}
+
+// jsonType is the first non-null type a parameter schema allows.
+func (l loader) jsonType(schema map[string]any) string {
+	_, schema = l.resolve("", schema)
+	types, ok := schema["type"].([]any)
+	if !ok {
+		types = []any{schema["type"]}
+	}
+	for _, t := range types {
+		if t, ok := t.(string); ok && t != "null" {
+			return t
+		}
+	}
+	return "string"
+}
+
+// RequestError is a request the spec does not allow.
+type RequestError struct {
+	Type   problem.Type
+	Detail string
+	// Fields describes each invalid body field, keyed by JSON Pointer. It is set
+	// for validation_failed.
+	Fields map[string]string
+}
+
+func (e *RequestError) Error() string {
+	if len(e.Fields) == 0 {
+		return e.Type.Code + ": " + e.Detail
+	}
+	var fields []string
+	for ptr, msg := range e.Fields {
+		fields = append(fields, ptr+" "+msg)
+	}
+	slices.Sort(fields)
+	return e.Type.Code + ": " + strings.Join(fields, "; ")
+}
+
+// Write responds to r with the problem e describes.
+func (e *RequestError) Write(w http.ResponseWriter, r *http.Request) {
+	if len(e.Fields) > 0 {
+		problem.WriteInvalid(w, r, e.Fields)
+		return
+	}
+	problem.Write(w, r, e.Type, e.Detail)
+}
+
+// match finds the operation for r and its path parameters.
+func (v *Validator) match(r *http.Request) (*operation, map[string]string) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	for _, op := range v.ops {
+		if op.method != r.Method || len(op.segments) != len(segments) {
+			continue
+		}
+		params := map[string]string{}
+		matched := true
+		for i, s := range op.segments {
+			if isParam(s) {
+				params[s[1:len(s)-1]] = segments[i]
+			} else if s != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return op, params
+		}
+	}
+	return nil, nil
+}
+
+// Operation returns the operationId of the operation r matches, or "" when the
+// spec does not describe r.
+func (v *Validator) Operation(r *http.Request) string {
+	if op, _ := v.match(r); op != nil {
+		return op.id
+	}
+	return ""
+}
+
+// ValidateRequest checks r's parameters and JSON body against its operation,
+// leaving r's body readable. It returns a *RequestError for requests the spec
+// does not allow. Malformed, oversized and non-JSON bodies are left to the handler.
+func (v *Validator) ValidateRequest(r *http.Request) error {
+	op, params := v.match(r)
+	if op == nil {
+		return nil
+	}
+	if err := op.validateRequest(r, params); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (op *operation) validateRequest(r *http.Request, pathParams map[string]string) *RequestError {
+	query := r.URL.Query()
+	for _, p := range op.params {
+		var raw string
+		var present bool
+		switch p.in {
+		case "path":
+			raw, present = pathParams[p.name]
+		case "query":
+			present = query.Has(p.name)
+			raw = query.Get(p.name)
+		case "header":
+			raw = r.Header.Get(p.name)
+			present = raw != ""
+		}
+		if !present {
+			if p.required {
+				return &RequestError{Type: problem.InvalidParameter, Detail: p.name + " is required"}
+			}
+			continue
+		}
+		val, ok := convert(p.typ, raw)
+		if !ok {
+			return &RequestError{Type: problem.InvalidParameter, Detail: p.name + " must be " + article(p.typ)}
+		}
+		if err := p.schema.Validate(val); err != nil {
+			return &RequestError{Type: problem.InvalidParameter, Detail: p.name + " " + describe(err)[""]}
+		}
+	}
+
+	if op.body == nil {
+		return nil
+	}
+	mediaType := "application/json"
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mt, _, err := mime.ParseMediaType(ct)
+		if err != nil {
+			return nil
+		}
+		mediaType = mt
+	}
+	schema, ok := op.body[mediaType]
+	if !ok || schema == nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes+1))
+	r.Body = readCloser{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+	if err != nil || len(body) > maxBodyBytes || len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+	if err := schema.Validate(doc); err != nil {
+		fields := describe(err)
+		if msg, ok := fields[""]; ok {
+			return &RequestError{Type: problem.InvalidBody, Detail: "request body " + msg}
+		}
+		return &RequestError{Type: problem.ValidationFailed, Fields: fields}
+	}
+	return nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// ValidateResponse checks that status is documented for r's operation and that
+// the body has a documented media type and matches its schema. Streamed bodies
+// are not checked.
+func (v *Validator) ValidateResponse(r *http.Request, status int, header http.Header, body []byte) error {
+	op, _ := v.match(r)
+	if op == nil {
+		return nil
+	}
+	return op.validateResponse(status, header, body)
+}
+
+func (op *operation) validateResponse(status int, header http.Header, body []byte) error {
+	content, ok := op.responses[strconv.Itoa(status)]
+	if !ok {
+		if content, ok = op.responses["default"]; !ok {
+			return fmt.Errorf("%s: status %d is not documented", op.id, status)
+		}
+	}
+	if len(content) == 0 {
+		if len(body) > 0 {
+			return fmt.Errorf("%s: status %d has a body but none is documented", op.id, status)
+		}
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("%s: status %d: invalid Content-Type %q", op.id, status, header.Get("Content-Type"))
+	}
+	schema, ok := content[mediaType]
+	if !ok {
+		return fmt.Errorf("%s: status %d: Content-Type %s is not documented", op.id, status, mediaType)
+	}
+	if schema == nil {
+		return nil
+	}
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s: status %d: invalid JSON: %w", op.id, status, err)
+	}
+	if err := schema.Validate(doc); err != nil {
+		fields := describe(err)
+		msgs := make([]string, 0, len(fields))
+		for ptr, msg := range fields {
+			msgs = append(msgs, "#"+ptr+" "+msg)
+		}
+		slices.Sort(msgs)
+		return fmt.Errorf("%s: status %d: %s", op.id, status, strings.Join(msgs, "; "))
+	}
+	return nil
+}
+
+// Middleware rejects requests the spec does not allow with the problem
+// ValidateRequest describes, and passes each response that does not match the
+// spec to report. Responses are sent as the handler wrote them either way.
+func (v *Validator) Middleware(report func(r *http.Request, err error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			op, params := v.match(r)
+			if op == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if err := op.validateRequest(r, params); err != nil {
+				err.Write(w, r)
+				return
+			}
+
+			rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			if err := op.validateResponse(rec.status, w.Header(), rec.body.Bytes()); err != nil {
+				report(r, err)
+			}
+		})
+	}
+}
+
+// recorder passes a response through while keeping a copy of the body, unless
+// the response is an event stream.
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+	streaming   bool
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	if rec.wroteHeader {
+		return
+	}
+	rec.wroteHeader = true
+	rec.status = status
+	rec.streaming = strings.HasPrefix(rec.Header().Get("Content-Type"), "text/event-stream")
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	if !rec.wroteHeader {
+		rec.WriteHeader(http.StatusOK)
+	}
+	if !rec.streaming {
+		rec.body.Write(b)
+	}
+	return rec.ResponseWriter.Write(b)
+}
+
+func (rec *recorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// describe turns a validation error into messages in the handlers' wording, keyed
+// by the JSON Pointer of the offending value. Only the first message for each
+// value is kept.
+func describe(err error) map[string]string {
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return map[string]string{"": err.Error()}
+	}
+	out := map[string]string{}
+	describeCauses(verr, out)
+	return out
+}
+
+func describeCauses(e *jsonschema.ValidationError, out map[string]string) {
+	for _, cause := range e.Causes {
+		describeCauses(cause, out)
+	}
+	if len(e.Causes) > 0 {
+		return
+	}
+
+	ptr := ""
+	for _, token := range e.InstanceLocation {
+		ptr += "/" + escape(token)
+	}
+	add := func(ptr, msg string) {
+		if _, ok := out[ptr]; !ok {
+			out[ptr] = msg
+		}
+	}
+	switch k := e.ErrorKind.(type) {
+	case *kind.Required:
+		for _, name := range k.Missing {
+			add(ptr+"/"+escape(name), "is required")
+		}
+	case *kind.AdditionalProperties:
+		for _, name := range k.Properties {
+			add(ptr+"/"+escape(name), "is not a known field")
+		}
+	case *kind.Type:
+		var want []string
+		for _, t := range k.Want {
+			if t != "null" {
+				want = append(want, article(t))
+			}
+		}
+		if len(want) > 0 {
+			add(ptr, "must be "+strings.Join(want, " or "))
+		}
+	case *kind.Enum:
+		want := make([]string, len(k.Want))
+		for i, w := range k.Want {
+			want[i] = fmt.Sprint(w)
+		}
+		add(ptr, "must be one of "+strings.Join(want, ", "))
+	case *kind.MinLength:
+		if k.Want == 1 {
+			add(ptr, "must not be empty")
+		} else {
+			add(ptr, "must be at least "+strconv.Itoa(k.Want)+" characters")
+		}
+	case *kind.MaxLength:
+		add(ptr, "must be at most "+strconv.Itoa(k.Want)+" characters")
+	case *kind.MinItems:
+		add(ptr, "must have at least "+strconv.Itoa(k.Want)+" items")
+	case *kind.MaxItems:
+		add(ptr, "must have at most "+strconv.Itoa(k.Want)+" items")
+	case *kind.Minimum:
+		add(ptr, "must be at least "+ratString(k.Want))
+	case *kind.Maximum:
+		add(ptr, "must be at most "+ratString(k.Want))
+	case *kind.Pattern:
+		add(ptr, "must match "+k.Want)
+	case *kind.Format:
+		add(ptr, "must be a valid "+k.Want)
+	default:
+		add(ptr, e.ErrorKind.LocalizedString(printer))
+	}
+}
+
+var printer = message.NewPrinter(language.English)
+
+// convert parses a raw parameter as the JSON type its schema declares.
+func convert(typ, raw string) (any, bool) {
+	switch typ {
+	case "integer":
+		n, err := strconv.ParseInt(raw, 10, 64)
+		return n, err == nil
+	case "number":
+		f, err := strconv.ParseFloat(raw, 64)
+		return f, err == nil
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		return b, err == nil
+	default:
+		return raw, true
+	}
+}
+
+func article(typ string) string {
+	switch typ {
+	case "integer", "array", "object":
+		return "an " + typ
+	default:
+		return "a " + typ
+	}
+}
+
+func ratString(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	return r.FloatString(2)
+}
+
+// serverPath is the path of the first server URL in an OpenAPI servers list, to
+// which paths are relative.
+func serverPath(servers any) string {
+	list := array(servers)
+	if len(list) == 0 {
+		return ""
+	}
+	raw, _ := object(list[0])["url"].(string)
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
+
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// escape encodes a JSON Pointer token.
+func escape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func unescape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+func object(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func array(v any) []any {
+	a, _ := v.([]any)
+	return a
+}
@@ -0,0 +1,246 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dcrespo1/book-list-app/problem"
+)
+
+func load(t *testing.T) *Validator {
+	t.Helper()
+	v, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return v
+}
+
+func TestLoad_CompilesEveryOperation(t *testing.T) {
+	v := load(t)
+	if len(v.ops) == 0 {
+		t.Fatal("no operations loaded")
+	}
+	seen := map[string]bool{}
+	for _, op := range v.ops {
+		if op.id == "" || seen[op.id] {
+			t.Errorf("%s %v: missing or duplicate operationId %q", op.method, op.segments, op.id)
+		}
+		seen[op.id] = true
+	}
+}
+
+func TestSpec_ProblemCodesMatchTypes(t *testing.T) {
+	var doc struct {
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]struct {
+					Enum []string `json:"enum"`
+				} `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(Spec(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	got := doc.Components.Schemas["Problem"].Properties["code"].Enum
+	var want []string
+	for _, typ := range problem.Types {
+		want = append(want, typ.Code)
+	}
+	slices.Sort(got)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("Problem.code enum: got %v, want %v", got, want)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	v := load(t)
+	cases := map[string]string{
+		"GET /v1/readlist":                    "listReadlist",
+		"GET /v1/readlist/":                   "listReadlist",
+		"GET /v1/readlist/events":             "streamReadlistEvents",
+		"GET /v1/readlist/OL1W":               "getReadlistEntry",
+		"POST /v1/readlist/bulk":              "bulkReadlist",
+		"PUT /v1/readlist/1/formats/ebook":    "putOwnedFormat",
+		"DELETE /v1/readlist/1/formats/ebook": "deleteOwnedFormat",
+		"GET /health":                         "getHealth",
+		"GET /v1/health":                      "",
+		"GET /readlist":                       "",
+		"GET /loans":                          "",
+		"POST /v1/readlist/1/formats/ebook/x": "",
+	}
+	for req, want := range cases {
+		method, path, _ := strings.Cut(req, " ")
+		op, _ := v.match(httptest.NewRequest(method, path, nil))
+		got := ""
+		if op != nil {
+			got = op.id
+		}
+		if got != want {
+			t.Errorf("%s: got %q, want %q", req, got, want)
+		}
+	}
+}
+
+func TestValidateRequest_Parameters(t *testing.T) {
+	v := load(t)
+	cases := []struct {
+		method, target string
+		header         map[string]string
+		wantErr        bool
+	}{
+		{"GET", "/v1/search?q=dune", nil, false},
+		{"GET", "/v1/search?q=dune&lang=en", nil, false},
+		{"GET", "/v1/search", nil, true},
+		{"GET", "/v1/search?q=dune&lang=english", nil, true},
+		{"GET", "/v1/readlist?owned=true&format=ebook", nil, false},
+		{"GET", "/v1/readlist?owned=maybe", nil, true},
+		{"GET", "/v1/readlist?sort=random", nil, true},
+		{"PATCH", "/v1/readlist/abc", nil, true},
+		{"DELETE", "/v1/readlist/0", nil, true},
+		{"PUT", "/v1/readlist/1/formats/scroll", nil, true},
+		{"GET", "/v1/readlist/events", map[string]string{"Last-Event-ID": "12"}, false},
+		{"GET", "/v1/readlist/events", map[string]string{"Last-Event-ID": "abc"}, true},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRequest(tc.method, tc.target, nil)
+		for k, val := range tc.header {
+			r.Header.Set(k, val)
+		}
+		err := v.ValidateRequest(r)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s %s: got error %v, want error %v", tc.method, tc.target, err, tc.wantErr)
+			continue
+		}
+		var reqErr *RequestError
+		if err != nil && (!errors.As(err, &reqErr) || reqErr.Type != problem.InvalidParameter) {
+			t.Errorf("%s %s: got %v, want invalid_parameter", tc.method, tc.target, err)
+		}
+	}
+}
+
+func TestValidateRequest_Body(t *testing.T) {
+	v := load(t)
+	cases := []struct {
+		name, method, target, contentType, body string
+		wantFields                              []string
+	}{
+		{"valid", "POST", "/v1/readlist", "", `{"title":"Dune","authors":"Frank Herbert","work_id":"OL1W"}`, nil},
+		{"missing and unknown", "POST", "/v1/readlist", "application/json", `{"title":"Dune","colour":"red"}`, []string{"/authors", "/colour", "/work_id"}},
+		{"too long", "POST", "/v1/readlist", "", `{"title":"` + strings.Repeat("a", 501) + `","authors":"A","work_id":"OL1W"}`, []string{"/title"}},
+		{"nested", "POST", "/v1/readlist/bulk", "", `{"operations":[{"op":"archive","ids":["1"]}]}`, []string{"/operations/0/ids/0", "/operations/0/op"}},
+		{"json patch", "PATCH", "/v1/readlist/1", "application/json-patch+json", `[{"op":"move","path":"/title"}]`, []string{"/0/op", "/0/path"}},
+		{"merge patch", "PATCH", "/v1/readlist/1", "application/merge-patch+json", `{"rating":6}`, []string{"/rating"}},
+		{"empty optional body", "PUT", "/v1/readlist/1/formats/ebook", "", "", nil},
+		{"currency", "PUT", "/v1/readlist/1/formats/ebook", "", `{"price_cents":999,"currency":"usd"}`, []string{"/currency"}},
+		// Left to the handler, which answers with its own 400 or 415.
+		{"malformed", "POST", "/v1/readlist", "", `{"title":`, nil},
+		{"other media type", "PATCH", "/v1/readlist/1", "text/plain", `{"rating":6}`, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			if tc.contentType != "" {
+				r.Header.Set("Content-Type", tc.contentType)
+			}
+			err := v.ValidateRequest(r)
+			if rest, _ := io.ReadAll(r.Body); string(rest) != tc.body {
+				t.Errorf("body not restored: got %q", rest)
+			}
+			if tc.wantFields == nil {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			var reqErr *RequestError
+			if !errors.As(err, &reqErr) {
+				t.Fatalf("got %v, want a RequestError", err)
+			}
+			var got []string
+			for ptr := range reqErr.Fields {
+				got = append(got, ptr)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tc.wantFields) {
+				t.Errorf("fields: got %v, want %v", reqErr.Fields, tc.wantFields)
+			}
+		})
+	}
+}
+
+func TestValidateResponse(t *testing.T) {
+	v := load(t)
+	jsonHeader := http.Header{"Content-Type": {"application/json"}}
+	problemHeader := http.Header{"Content-Type": {problem.ContentType}}
+	entry := `{"id":1,"title":"Dune","authors":"Frank Herbert","subjects":null,"description":null,` +
+		`"cover_art_url":null,"work_id":"OL1W","status":"reading","rating":null,"notes":null,` +
+		`"version":1,"updated_at":"2026-01-02T03:04:05Z"}`
+	cases := []struct {
+		name    string
+		target  string
+		status  int
+		header  http.Header
+		body    string
+		wantErr bool
+	}{
+		{"valid", "/v1/readlist/OL1W", 200, jsonHeader, entry, false},
+		{"drifted field", "/v1/readlist/OL1W", 200, jsonHeader, strings.Replace(entry, `"version":1`, `"version":"1"`, 1), true},
+		{"extra field", "/v1/readlist/OL1W", 200, jsonHeader, strings.Replace(entry, `"id":1`, `"id":1,"user_id":"x"`, 1), true},
+		{"not modified", "/v1/readlist/OL1W", 304, http.Header{}, "", false},
+		{"problem", "/v1/readlist/OL1W", 404, problemHeader, `{"type":"/problems/not_found","title":"Not found","status":404,"code":"not_found"}`, false},
+		{"undocumented status", "/v1/readlist/OL1W", 418, jsonHeader, `{}`, true},
+		{"undocumented media type", "/v1/readlist/OL1W", 404, jsonHeader, `{}`, true},
+		{"unknown code", "/v1/readlist/OL1W", 404, problemHeader, `{"type":"/problems/gone","title":"Gone","status":404,"code":"gone"}`, true},
+		{"undescribed path", "/loans", 418, jsonHeader, `nonsense`, false},
+	}
+	for _, tc := range cases {
+		err := v.ValidateResponse(httptest.NewRequest("GET", tc.target, nil), tc.status, tc.header, []byte(tc.body))
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: got error %v, want error %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	v := load(t)
+	var reported []error
+	handler := v.Middleware(func(r *http.Request, err error) { reported = append(reported, err) })(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":"not a number"}`))
+		}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/v1/readlist", strings.NewReader(`{"title":""}`)))
+	if w.Code != http.StatusUnprocessableEntity || len(reported) != 0 {
+		t.Errorf("invalid request: status %d, reported %v", w.Code, reported)
+	}
+
+	w = httptest.NewRecorder()
+	body := `{"title":"Dune","authors":"Frank Herbert","work_id":"OL1W"}`
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/v1/readlist", strings.NewReader(body)))
+	if w.Code != http.StatusOK || w.Body.String() != `{"id":"not a number"}` {
+		t.Errorf("response was altered: %d %s", w.Code, w.Body)
+	}
+	// The handler answered 200 where the spec documents 201.
+	if len(reported) != 1 {
+		t.Errorf("reported: got %v, want one error", reported)
+	}
+}
+
+func TestServeDocs(t *testing.T) {
+	w := httptest.NewRecorder()
+	ServeDocs(w, httptest.NewRequest("GET", "/docs", nil))
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") || !strings.Contains(w.Body.String(), "/openapi.json") {
+		t.Errorf("unexpected docs page: %s", w.Header().Get("Content-Type"))
+	}
+}
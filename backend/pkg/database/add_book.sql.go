@@ -11,8 +11,8 @@ import (
 )
 
 const addBook = `-- name: AddBook :one
-INSERT INTO books (user_id, title, authors, subjects, description, cover_art_url, work_id)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO books (user_id, title, authors, subjects, description, cover_art_url, work_id, status)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING id
 `
 
@@ -24,6 +24,7 @@ type AddBookParams struct {
 	Description sql.NullString
 	CoverArtUrl sql.NullString
 	WorkID      string
+	Status      string
 }
 
 func (q *Queries) AddBook(ctx context.Context, arg AddBookParams) (int32, error) {
@@ -35,6 +36,7 @@ func (q *Queries) AddBook(ctx context.Context, arg AddBookParams) (int32, error)
 		arg.Description,
 		arg.CoverArtUrl,
 		arg.WorkID,
+		arg.Status,
 	)
 	var id int32
 	err := row.Scan(&id)
@@ -7,19 +7,30 @@ package database
 
 import (
 	"context"
+
+	"github.com/lib/pq"
 )
 
 const deleteBookByID = `-- name: DeleteBookByID :execrows
-DELETE FROM books WHERE id = $1 AND user_id = $2
+UPDATE books
+SET deleted_at = NOW(),
+    version    = version + 1,
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+  AND (COALESCE(cardinality($3::int[]), 0) = 0 OR version = ANY($3::int[]))
 `
 
 type DeleteBookByIDParams struct {
-	ID     int32
-	UserID string
+	ID      int32
+	UserID  string
+	IfMatch []int32
 }
 
+// Moves the entry to the trash; PurgeTrashedBooks removes it for good later.
+// When if_match is non-empty the row must currently be at one of those versions;
+// an empty or NULL if_match (a nil Go slice) accepts any version.
 func (q *Queries) DeleteBookByID(ctx context.Context, arg DeleteBookByIDParams) (int64, error) {
-	result, err := q.db.ExecContext(ctx, deleteBookByID, arg.ID, arg.UserID)
+	result, err := q.db.ExecContext(ctx, deleteBookByID, arg.ID, arg.UserID, pq.Array(arg.IfMatch))
 	if err != nil {
 		return 0, err
 	}
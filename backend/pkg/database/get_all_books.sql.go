@@ -10,7 +10,7 @@ import (
 )
 
 const getAllBooks = `-- name: GetAllBooks :many
-SELECT id, title, authors, subjects, description, cover_art_url, work_id, user_id, status, rating, notes FROM books WHERE user_id = $1 ORDER BY id DESC
+SELECT id, title, authors, subjects, description, cover_art_url, work_id, user_id, status, rating, notes, version, updated_at, deleted_at FROM books WHERE user_id = $1 AND deleted_at IS NULL ORDER BY id DESC
 `
 
 func (q *Queries) GetAllBooks(ctx context.Context, userID string) ([]Book, error) {
@@ -34,6 +34,9 @@ func (q *Queries) GetAllBooks(ctx context.Context, userID string) ([]Book, error
 			&i.Status,
 			&i.Rating,
 			&i.Notes,
+			&i.Version,
+			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -10,7 +10,7 @@ import (
 )
 
 const getBookByID = `-- name: GetBookByID :one
-SELECT id, title, authors, subjects, description, cover_art_url, work_id, user_id, status, rating, notes FROM books WHERE id = $1 AND user_id = $2
+SELECT id, title, authors, subjects, description, cover_art_url, work_id, user_id, status, rating, notes, version, updated_at, deleted_at FROM books WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
 `
 
 type GetBookByIDParams struct {
@@ -33,6 +33,9 @@ func (q *Queries) GetBookByID(ctx context.Context, arg GetBookByIDParams) (Book,
 		&i.Status,
 		&i.Rating,
 		&i.Notes,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -6,6 +6,8 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
+	"time"
 )
 
 type Book struct {
@@ -20,4 +22,167 @@ type Book struct {
 	Status      string
 	Rating      sql.NullInt32
 	Notes       sql.NullString
+	Version     int32
+	UpdatedAt   time.Time
+	DeletedAt   sql.NullTime
+}
+
+type Comment struct {
+	ID        int32
+	WorkID    string
+	UserID    string
+	ParentID  sql.NullInt32
+	Body      string
+	Spoiler   bool
+	Chapter   sql.NullInt32
+	Page      sql.NullInt32
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt sql.NullTime
+}
+
+type Household struct {
+	ID        int32
+	Name      string
+	CreatedAt time.Time
+}
+
+type HouseholdCopy struct {
+	ID          int32
+	HouseholdID int32
+	WorkID      string
+	Title       string
+	Authors     string
+	CoverArtUrl sql.NullString
+	Format      string
+	Condition   sql.NullString
+	Location    sql.NullString
+	AddedBy     string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type HouseholdInvite struct {
+	ID          int32
+	HouseholdID int32
+	TokenHash   string
+	Role        string
+	CreatedBy   string
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+	AcceptedBy  sql.NullString
+	AcceptedAt  sql.NullTime
+}
+
+type HouseholdMember struct {
+	HouseholdID int32
+	UserID      string
+	Role        string
+	JoinedAt    time.Time
+}
+
+type IdempotencyKey struct {
+	UserID          string
+	Key             string
+	RequestHash     string
+	StatusCode      sql.NullInt32
+	ResponseHeaders json.RawMessage
+	ResponseBody    []byte
+	CreatedAt       time.Time
+	ExpiresAt       time.Time
+}
+
+type Loan struct {
+	ID               int32
+	BookID           int32
+	UserID           string
+	BorrowerName     sql.NullString
+	BorrowerUserID   sql.NullString
+	LentOn           time.Time
+	DueOn            sql.NullTime
+	ReturnedOn       sql.NullTime
+	Notes            sql.NullString
+	OverdueFlaggedAt sql.NullTime
+	CreatedAt        time.Time
+}
+
+type OwnedFormat struct {
+	BookID      int32
+	UserID      string
+	Format      string
+	PurchasedOn sql.NullTime
+	PriceCents  sql.NullInt32
+	Currency    sql.NullString
+	Store       sql.NullString
+	Source      sql.NullString
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type PersonalAccessToken struct {
+	ID         int32
+	UserID     string
+	Name       string
+	TokenHash  string
+	Prefix     string
+	Scopes     []string
+	CreatedAt  time.Time
+	ExpiresAt  sql.NullTime
+	LastUsedAt sql.NullTime
+	RevokedAt  sql.NullTime
+}
+
+type RateLimitBucket struct {
+	Key       string
+	Tokens    float64
+	Allowed   bool
+	UpdatedAt time.Time
+}
+
+type ReadlistEvent struct {
+	ID        int64
+	UserID    string
+	Type      string
+	Payload   json.RawMessage
+	CreatedAt time.Time
+}
+
+type User struct {
+	UserID                 string
+	Username               sql.NullString
+	Email                  sql.NullString
+	Name                   sql.NullString
+	DefaultSort            string
+	Privacy                string
+	DefaultStatus          string
+	Timezone               string
+	Language               sql.NullString
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
+	DeletionTokenHash      sql.NullString
+	DeletionTokenExpiresAt sql.NullTime
+	DeletionScheduledFor   sql.NullTime
+}
+
+type WebhookDelivery struct {
+	ID             int32
+	SubscriptionID int32
+	EventType      string
+	Payload        json.RawMessage
+	Status         string
+	Attempts       int32
+	NextAttemptAt  time.Time
+	LastStatusCode sql.NullInt32
+	LastError      sql.NullString
+	CreatedAt      time.Time
+	DeliveredAt    sql.NullTime
+}
+
+type WebhookSubscription struct {
+	ID         int32
+	UserID     string
+	Url        string
+	Secret     string
+	EventTypes []string
+	CreatedAt  time.Time
 }
@@ -8,34 +8,74 @@ package database
 import (
 	"context"
 	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 const updateBook = `-- name: UpdateBook :one
 UPDATE books
-SET status = $1,
-    rating = $2,
-    notes  = $3
-WHERE id = $4 AND user_id = $5
-RETURNING id, title, authors, subjects, description, cover_art_url, work_id, user_id, status, rating, notes
+SET status     = COALESCE($1::text, books.status),
+    rating     = CASE WHEN $2::bool THEN $3::int ELSE books.rating END,
+    notes      = CASE WHEN $4::bool THEN $5::text ELSE books.notes END,
+    version    = books.version + 1,
+    updated_at = NOW()
+FROM (
+    SELECT b.id, b.status FROM books b
+    WHERE b.id = $6 AND b.user_id = $7 AND b.deleted_at IS NULL
+    FOR UPDATE
+) AS prev
+WHERE books.id = prev.id
+  AND (COALESCE(cardinality($8::int[]), 0) = 0 OR books.version = ANY($8::int[]))
+RETURNING books.id, books.title, books.authors, books.subjects, books.description, books.cover_art_url, books.work_id, books.user_id, books.status, books.rating, books.notes, books.version, books.updated_at, books.deleted_at, prev.status AS previous_status
 `
 
 type UpdateBookParams struct {
-	Status string
-	Rating sql.NullInt32
-	Notes  sql.NullString
-	ID     int32
-	UserID string
+	Status    sql.NullString
+	SetRating bool
+	Rating    sql.NullInt32
+	SetNotes  bool
+	Notes     sql.NullString
+	ID        int32
+	UserID    string
+	IfMatch   []int32
+}
+
+type UpdateBookRow struct {
+	ID             int32
+	Title          string
+	Authors        string
+	Subjects       sql.NullString
+	Description    sql.NullString
+	CoverArtUrl    sql.NullString
+	WorkID         string
+	UserID         string
+	Status         string
+	Rating         sql.NullInt32
+	Notes          sql.NullString
+	Version        int32
+	UpdatedAt      time.Time
+	DeletedAt      sql.NullTime
+	PreviousStatus string
 }
 
-func (q *Queries) UpdateBook(ctx context.Context, arg UpdateBookParams) (Book, error) {
+// Applies a partial update and bumps the version, in one statement. status is
+// left alone when NULL; rating and notes are only written (possibly to NULL)
+// when their set_ flag is true. When if_match is non-empty the row must
+// currently be at one of those versions; an empty or NULL if_match (a nil Go
+// slice) accepts any version. previous_status is the status before this update.
+func (q *Queries) UpdateBook(ctx context.Context, arg UpdateBookParams) (UpdateBookRow, error) {
 	row := q.db.QueryRowContext(ctx, updateBook,
 		arg.Status,
+		arg.SetRating,
 		arg.Rating,
+		arg.SetNotes,
 		arg.Notes,
 		arg.ID,
 		arg.UserID,
+		pq.Array(arg.IfMatch),
 	)
-	var i Book
+	var i UpdateBookRow
 	err := row.Scan(
 		&i.ID,
 		&i.Title,
@@ -48,6 +88,10 @@ func (q *Queries) UpdateBook(ctx context.Context, arg UpdateBookParams) (Book, e
 		&i.Status,
 		&i.Rating,
 		&i.Notes,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.PreviousStatus,
 	)
 	return i, err
 }
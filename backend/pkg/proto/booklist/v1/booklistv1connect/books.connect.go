@@ -0,0 +1,145 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: booklist/v1/books.proto
+
+package booklistv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/dcrespo1/book-list-app/pkg/proto/booklist/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// BookServiceName is the fully-qualified name of the BookService service.
+	BookServiceName = "booklist.v1.BookService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// BookServiceSearchBooksProcedure is the fully-qualified name of the BookService's SearchBooks RPC.
+	BookServiceSearchBooksProcedure = "/booklist.v1.BookService/SearchBooks"
+	// BookServiceGetBookDetailsProcedure is the fully-qualified name of the BookService's
+	// GetBookDetails RPC.
+	BookServiceGetBookDetailsProcedure = "/booklist.v1.BookService/GetBookDetails"
+)
+
+// BookServiceClient is a client for the booklist.v1.BookService service.
+type BookServiceClient interface {
+	// SearchBooks favours editions in lang or, when it is empty, the caller's
+	// language preference.
+	SearchBooks(context.Context, *connect.Request[v1.SearchBooksRequest]) (*connect.Response[v1.SearchBooksResponse], error)
+	GetBookDetails(context.Context, *connect.Request[v1.GetBookDetailsRequest]) (*connect.Response[v1.GetBookDetailsResponse], error)
+}
+
+// NewBookServiceClient constructs a client for the booklist.v1.BookService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewBookServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) BookServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	bookServiceMethods := v1.File_booklist_v1_books_proto.Services().ByName("BookService").Methods()
+	return &bookServiceClient{
+		searchBooks: connect.NewClient[v1.SearchBooksRequest, v1.SearchBooksResponse](
+			httpClient,
+			baseURL+BookServiceSearchBooksProcedure,
+			connect.WithSchema(bookServiceMethods.ByName("SearchBooks")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		getBookDetails: connect.NewClient[v1.GetBookDetailsRequest, v1.GetBookDetailsResponse](
+			httpClient,
+			baseURL+BookServiceGetBookDetailsProcedure,
+			connect.WithSchema(bookServiceMethods.ByName("GetBookDetails")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// bookServiceClient implements BookServiceClient.
+type bookServiceClient struct {
+	searchBooks    *connect.Client[v1.SearchBooksRequest, v1.SearchBooksResponse]
+	getBookDetails *connect.Client[v1.GetBookDetailsRequest, v1.GetBookDetailsResponse]
+}
+
+// SearchBooks calls booklist.v1.BookService.SearchBooks.
+func (c *bookServiceClient) SearchBooks(ctx context.Context, req *connect.Request[v1.SearchBooksRequest]) (*connect.Response[v1.SearchBooksResponse], error) {
+	return c.searchBooks.CallUnary(ctx, req)
+}
+
+// GetBookDetails calls booklist.v1.BookService.GetBookDetails.
+func (c *bookServiceClient) GetBookDetails(ctx context.Context, req *connect.Request[v1.GetBookDetailsRequest]) (*connect.Response[v1.GetBookDetailsResponse], error) {
+	return c.getBookDetails.CallUnary(ctx, req)
+}
+
+// BookServiceHandler is an implementation of the booklist.v1.BookService service.
+type BookServiceHandler interface {
+	// SearchBooks favours editions in lang or, when it is empty, the caller's
+	// language preference.
+	SearchBooks(context.Context, *connect.Request[v1.SearchBooksRequest]) (*connect.Response[v1.SearchBooksResponse], error)
+	GetBookDetails(context.Context, *connect.Request[v1.GetBookDetailsRequest]) (*connect.Response[v1.GetBookDetailsResponse], error)
+}
+
+// NewBookServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewBookServiceHandler(svc BookServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	bookServiceMethods := v1.File_booklist_v1_books_proto.Services().ByName("BookService").Methods()
+	bookServiceSearchBooksHandler := connect.NewUnaryHandler(
+		BookServiceSearchBooksProcedure,
+		svc.SearchBooks,
+		connect.WithSchema(bookServiceMethods.ByName("SearchBooks")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	bookServiceGetBookDetailsHandler := connect.NewUnaryHandler(
+		BookServiceGetBookDetailsProcedure,
+		svc.GetBookDetails,
+		connect.WithSchema(bookServiceMethods.ByName("GetBookDetails")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/booklist.v1.BookService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case BookServiceSearchBooksProcedure:
+			bookServiceSearchBooksHandler.ServeHTTP(w, r)
+		case BookServiceGetBookDetailsProcedure:
+			bookServiceGetBookDetailsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedBookServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedBookServiceHandler struct{}
+
+func (UnimplementedBookServiceHandler) SearchBooks(context.Context, *connect.Request[v1.SearchBooksRequest]) (*connect.Response[v1.SearchBooksResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("booklist.v1.BookService.SearchBooks is not implemented"))
+}
+
+func (UnimplementedBookServiceHandler) GetBookDetails(context.Context, *connect.Request[v1.GetBookDetailsRequest]) (*connect.Response[v1.GetBookDetailsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("booklist.v1.BookService.GetBookDetails is not implemented"))
+}
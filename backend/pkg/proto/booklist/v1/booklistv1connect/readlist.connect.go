@@ -0,0 +1,303 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: booklist/v1/readlist.proto
+
+package booklistv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/dcrespo1/book-list-app/pkg/proto/booklist/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// ReadlistServiceName is the fully-qualified name of the ReadlistService service.
+	ReadlistServiceName = "booklist.v1.ReadlistService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// ReadlistServiceListReadlistProcedure is the fully-qualified name of the ReadlistService's
+	// ListReadlist RPC.
+	ReadlistServiceListReadlistProcedure = "/booklist.v1.ReadlistService/ListReadlist"
+	// ReadlistServiceGetReadlistEntryProcedure is the fully-qualified name of the ReadlistService's
+	// GetReadlistEntry RPC.
+	ReadlistServiceGetReadlistEntryProcedure = "/booklist.v1.ReadlistService/GetReadlistEntry"
+	// ReadlistServiceAddToReadlistProcedure is the fully-qualified name of the ReadlistService's
+	// AddToReadlist RPC.
+	ReadlistServiceAddToReadlistProcedure = "/booklist.v1.ReadlistService/AddToReadlist"
+	// ReadlistServiceUpdateReadlistEntryProcedure is the fully-qualified name of the ReadlistService's
+	// UpdateReadlistEntry RPC.
+	ReadlistServiceUpdateReadlistEntryProcedure = "/booklist.v1.ReadlistService/UpdateReadlistEntry"
+	// ReadlistServiceDeleteReadlistEntryProcedure is the fully-qualified name of the ReadlistService's
+	// DeleteReadlistEntry RPC.
+	ReadlistServiceDeleteReadlistEntryProcedure = "/booklist.v1.ReadlistService/DeleteReadlistEntry"
+	// ReadlistServiceListTrashProcedure is the fully-qualified name of the ReadlistService's ListTrash
+	// RPC.
+	ReadlistServiceListTrashProcedure = "/booklist.v1.ReadlistService/ListTrash"
+	// ReadlistServiceRestoreFromTrashProcedure is the fully-qualified name of the ReadlistService's
+	// RestoreFromTrash RPC.
+	ReadlistServiceRestoreFromTrashProcedure = "/booklist.v1.ReadlistService/RestoreFromTrash"
+)
+
+// ReadlistServiceClient is a client for the booklist.v1.ReadlistService service.
+type ReadlistServiceClient interface {
+	// ListReadlist returns entries in the order given by sort or the caller's
+	// default_sort preference, with the formats each is owned in.
+	ListReadlist(context.Context, *connect.Request[v1.ListReadlistRequest]) (*connect.Response[v1.ListReadlistResponse], error)
+	GetReadlistEntry(context.Context, *connect.Request[v1.GetReadlistEntryRequest]) (*connect.Response[v1.GetReadlistEntryResponse], error)
+	// AddToReadlist adds a work with the caller's default status.
+	AddToReadlist(context.Context, *connect.Request[v1.AddToReadlistRequest]) (*connect.Response[v1.AddToReadlistResponse], error)
+	UpdateReadlistEntry(context.Context, *connect.Request[v1.UpdateReadlistEntryRequest]) (*connect.Response[v1.UpdateReadlistEntryResponse], error)
+	// DeleteReadlistEntry moves an entry to the trash.
+	DeleteReadlistEntry(context.Context, *connect.Request[v1.DeleteReadlistEntryRequest]) (*connect.Response[v1.DeleteReadlistEntryResponse], error)
+	// ListTrash returns deleted entries, most recently deleted first.
+	ListTrash(context.Context, *connect.Request[v1.ListTrashRequest]) (*connect.Response[v1.ListTrashResponse], error)
+	// RestoreFromTrash fails with ALREADY_EXISTS if the work has been added again
+	// since it was deleted.
+	RestoreFromTrash(context.Context, *connect.Request[v1.RestoreFromTrashRequest]) (*connect.Response[v1.RestoreFromTrashResponse], error)
+}
+
+// NewReadlistServiceClient constructs a client for the booklist.v1.ReadlistService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewReadlistServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) ReadlistServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	readlistServiceMethods := v1.File_booklist_v1_readlist_proto.Services().ByName("ReadlistService").Methods()
+	return &readlistServiceClient{
+		listReadlist: connect.NewClient[v1.ListReadlistRequest, v1.ListReadlistResponse](
+			httpClient,
+			baseURL+ReadlistServiceListReadlistProcedure,
+			connect.WithSchema(readlistServiceMethods.ByName("ListReadlist")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		getReadlistEntry: connect.NewClient[v1.GetReadlistEntryRequest, v1.GetReadlistEntryResponse](
+			httpClient,
+			baseURL+ReadlistServiceGetReadlistEntryProcedure,
+			connect.WithSchema(readlistServiceMethods.ByName("GetReadlistEntry")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		addToReadlist: connect.NewClient[v1.AddToReadlistRequest, v1.AddToReadlistResponse](
+			httpClient,
+			baseURL+ReadlistServiceAddToReadlistProcedure,
+			connect.WithSchema(readlistServiceMethods.ByName("AddToReadlist")),
+			connect.WithClientOptions(opts...),
+		),
+		updateReadlistEntry: connect.NewClient[v1.UpdateReadlistEntryRequest, v1.UpdateReadlistEntryResponse](
+			httpClient,
+			baseURL+ReadlistServiceUpdateReadlistEntryProcedure,
+			connect.WithSchema(readlistServiceMethods.ByName("UpdateReadlistEntry")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteReadlistEntry: connect.NewClient[v1.DeleteReadlistEntryRequest, v1.DeleteReadlistEntryResponse](
+			httpClient,
+			baseURL+ReadlistServiceDeleteReadlistEntryProcedure,
+			connect.WithSchema(readlistServiceMethods.ByName("DeleteReadlistEntry")),
+			connect.WithClientOptions(opts...),
+		),
+		listTrash: connect.NewClient[v1.ListTrashRequest, v1.ListTrashResponse](
+			httpClient,
+			baseURL+ReadlistServiceListTrashProcedure,
+			connect.WithSchema(readlistServiceMethods.ByName("ListTrash")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		restoreFromTrash: connect.NewClient[v1.RestoreFromTrashRequest, v1.RestoreFromTrashResponse](
+			httpClient,
+			baseURL+ReadlistServiceRestoreFromTrashProcedure,
+			connect.WithSchema(readlistServiceMethods.ByName("RestoreFromTrash")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// readlistServiceClient implements ReadlistServiceClient.
+type readlistServiceClient struct {
+	listReadlist        *connect.Client[v1.ListReadlistRequest, v1.ListReadlistResponse]
+	getReadlistEntry    *connect.Client[v1.GetReadlistEntryRequest, v1.GetReadlistEntryResponse]
+	addToReadlist       *connect.Client[v1.AddToReadlistRequest, v1.AddToReadlistResponse]
+	updateReadlistEntry *connect.Client[v1.UpdateReadlistEntryRequest, v1.UpdateReadlistEntryResponse]
+	deleteReadlistEntry *connect.Client[v1.DeleteReadlistEntryRequest, v1.DeleteReadlistEntryResponse]
+	listTrash           *connect.Client[v1.ListTrashRequest, v1.ListTrashResponse]
+	restoreFromTrash    *connect.Client[v1.RestoreFromTrashRequest, v1.RestoreFromTrashResponse]
+}
+
+// ListReadlist calls booklist.v1.ReadlistService.ListReadlist.
+func (c *readlistServiceClient) ListReadlist(ctx context.Context, req *connect.Request[v1.ListReadlistRequest]) (*connect.Response[v1.ListReadlistResponse], error) {
+	return c.listReadlist.CallUnary(ctx, req)
+}
+
+// GetReadlistEntry calls booklist.v1.ReadlistService.GetReadlistEntry.
+func (c *readlistServiceClient) GetReadlistEntry(ctx context.Context, req *connect.Request[v1.GetReadlistEntryRequest]) (*connect.Response[v1.GetReadlistEntryResponse], error) {
+	return c.getReadlistEntry.CallUnary(ctx, req)
+}
+
+// AddToReadlist calls booklist.v1.ReadlistService.AddToReadlist.
+func (c *readlistServiceClient) AddToReadlist(ctx context.Context, req *connect.Request[v1.AddToReadlistRequest]) (*connect.Response[v1.AddToReadlistResponse], error) {
+	return c.addToReadlist.CallUnary(ctx, req)
+}
+
+// UpdateReadlistEntry calls booklist.v1.ReadlistService.UpdateReadlistEntry.
+func (c *readlistServiceClient) UpdateReadlistEntry(ctx context.Context, req *connect.Request[v1.UpdateReadlistEntryRequest]) (*connect.Response[v1.UpdateReadlistEntryResponse], error) {
+	return c.updateReadlistEntry.CallUnary(ctx, req)
+}
+
+// DeleteReadlistEntry calls booklist.v1.ReadlistService.DeleteReadlistEntry.
+func (c *readlistServiceClient) DeleteReadlistEntry(ctx context.Context, req *connect.Request[v1.DeleteReadlistEntryRequest]) (*connect.Response[v1.DeleteReadlistEntryResponse], error) {
+	return c.deleteReadlistEntry.CallUnary(ctx, req)
+}
+
+// ListTrash calls booklist.v1.ReadlistService.ListTrash.
+func (c *readlistServiceClient) ListTrash(ctx context.Context, req *connect.Request[v1.ListTrashRequest]) (*connect.Response[v1.ListTrashResponse], error) {
+	return c.listTrash.CallUnary(ctx, req)
+}
+
+// RestoreFromTrash calls booklist.v1.ReadlistService.RestoreFromTrash.
+func (c *readlistServiceClient) RestoreFromTrash(ctx context.Context, req *connect.Request[v1.RestoreFromTrashRequest]) (*connect.Response[v1.RestoreFromTrashResponse], error) {
+	return c.restoreFromTrash.CallUnary(ctx, req)
+}
+
+// ReadlistServiceHandler is an implementation of the booklist.v1.ReadlistService service.
+type ReadlistServiceHandler interface {
+	// ListReadlist returns entries in the order given by sort or the caller's
+	// default_sort preference, with the formats each is owned in.
+	ListReadlist(context.Context, *connect.Request[v1.ListReadlistRequest]) (*connect.Response[v1.ListReadlistResponse], error)
+	GetReadlistEntry(context.Context, *connect.Request[v1.GetReadlistEntryRequest]) (*connect.Response[v1.GetReadlistEntryResponse], error)
+	// AddToReadlist adds a work with the caller's default status.
+	AddToReadlist(context.Context, *connect.Request[v1.AddToReadlistRequest]) (*connect.Response[v1.AddToReadlistResponse], error)
+	UpdateReadlistEntry(context.Context, *connect.Request[v1.UpdateReadlistEntryRequest]) (*connect.Response[v1.UpdateReadlistEntryResponse], error)
+	// DeleteReadlistEntry moves an entry to the trash.
+	DeleteReadlistEntry(context.Context, *connect.Request[v1.DeleteReadlistEntryRequest]) (*connect.Response[v1.DeleteReadlistEntryResponse], error)
+	// ListTrash returns deleted entries, most recently deleted first.
+	ListTrash(context.Context, *connect.Request[v1.ListTrashRequest]) (*connect.Response[v1.ListTrashResponse], error)
+	// RestoreFromTrash fails with ALREADY_EXISTS if the work has been added again
+	// since it was deleted.
+	RestoreFromTrash(context.Context, *connect.Request[v1.RestoreFromTrashRequest]) (*connect.Response[v1.RestoreFromTrashResponse], error)
+}
+
+// NewReadlistServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewReadlistServiceHandler(svc ReadlistServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	readlistServiceMethods := v1.File_booklist_v1_readlist_proto.Services().ByName("ReadlistService").Methods()
+	readlistServiceListReadlistHandler := connect.NewUnaryHandler(
+		ReadlistServiceListReadlistProcedure,
+		svc.ListReadlist,
+		connect.WithSchema(readlistServiceMethods.ByName("ListReadlist")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	readlistServiceGetReadlistEntryHandler := connect.NewUnaryHandler(
+		ReadlistServiceGetReadlistEntryProcedure,
+		svc.GetReadlistEntry,
+		connect.WithSchema(readlistServiceMethods.ByName("GetReadlistEntry")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	readlistServiceAddToReadlistHandler := connect.NewUnaryHandler(
+		ReadlistServiceAddToReadlistProcedure,
+		svc.AddToReadlist,
+		connect.WithSchema(readlistServiceMethods.ByName("AddToReadlist")),
+		connect.WithHandlerOptions(opts...),
+	)
+	readlistServiceUpdateReadlistEntryHandler := connect.NewUnaryHandler(
+		ReadlistServiceUpdateReadlistEntryProcedure,
+		svc.UpdateReadlistEntry,
+		connect.WithSchema(readlistServiceMethods.ByName("UpdateReadlistEntry")),
+		connect.WithHandlerOptions(opts...),
+	)
+	readlistServiceDeleteReadlistEntryHandler := connect.NewUnaryHandler(
+		ReadlistServiceDeleteReadlistEntryProcedure,
+		svc.DeleteReadlistEntry,
+		connect.WithSchema(readlistServiceMethods.ByName("DeleteReadlistEntry")),
+		connect.WithHandlerOptions(opts...),
+	)
+	readlistServiceListTrashHandler := connect.NewUnaryHandler(
+		ReadlistServiceListTrashProcedure,
+		svc.ListTrash,
+		connect.WithSchema(readlistServiceMethods.ByName("ListTrash")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	readlistServiceRestoreFromTrashHandler := connect.NewUnaryHandler(
+		ReadlistServiceRestoreFromTrashProcedure,
+		svc.RestoreFromTrash,
+		connect.WithSchema(readlistServiceMethods.ByName("RestoreFromTrash")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/booklist.v1.ReadlistService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case ReadlistServiceListReadlistProcedure:
+			readlistServiceListReadlistHandler.ServeHTTP(w, r)
+		case ReadlistServiceGetReadlistEntryProcedure:
+			readlistServiceGetReadlistEntryHandler.ServeHTTP(w, r)
+		case ReadlistServiceAddToReadlistProcedure:
+			readlistServiceAddToReadlistHandler.ServeHTTP(w, r)
+		case ReadlistServiceUpdateReadlistEntryProcedure:
+			readlistServiceUpdateReadlistEntryHandler.ServeHTTP(w, r)
+		case ReadlistServiceDeleteReadlistEntryProcedure:
+			readlistServiceDeleteReadlistEntryHandler.ServeHTTP(w, r)
+		case ReadlistServiceListTrashProcedure:
+			readlistServiceListTrashHandler.ServeHTTP(w, r)
+		case ReadlistServiceRestoreFromTrashProcedure:
+			readlistServiceRestoreFromTrashHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedReadlistServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedReadlistServiceHandler struct{}
+
+func (UnimplementedReadlistServiceHandler) ListReadlist(context.Context, *connect.Request[v1.ListReadlistRequest]) (*connect.Response[v1.ListReadlistResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("booklist.v1.ReadlistService.ListReadlist is not implemented"))
+}
+
+func (UnimplementedReadlistServiceHandler) GetReadlistEntry(context.Context, *connect.Request[v1.GetReadlistEntryRequest]) (*connect.Response[v1.GetReadlistEntryResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("booklist.v1.ReadlistService.GetReadlistEntry is not implemented"))
+}
+
+func (UnimplementedReadlistServiceHandler) AddToReadlist(context.Context, *connect.Request[v1.AddToReadlistRequest]) (*connect.Response[v1.AddToReadlistResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("booklist.v1.ReadlistService.AddToReadlist is not implemented"))
+}
+
+func (UnimplementedReadlistServiceHandler) UpdateReadlistEntry(context.Context, *connect.Request[v1.UpdateReadlistEntryRequest]) (*connect.Response[v1.UpdateReadlistEntryResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("booklist.v1.ReadlistService.UpdateReadlistEntry is not implemented"))
+}
+
+func (UnimplementedReadlistServiceHandler) DeleteReadlistEntry(context.Context, *connect.Request[v1.DeleteReadlistEntryRequest]) (*connect.Response[v1.DeleteReadlistEntryResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("booklist.v1.ReadlistService.DeleteReadlistEntry is not implemented"))
+}
+
+func (UnimplementedReadlistServiceHandler) ListTrash(context.Context, *connect.Request[v1.ListTrashRequest]) (*connect.Response[v1.ListTrashResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("booklist.v1.ReadlistService.ListTrash is not implemented"))
+}
+
+func (UnimplementedReadlistServiceHandler) RestoreFromTrash(context.Context, *connect.Request[v1.RestoreFromTrashRequest]) (*connect.Response[v1.RestoreFromTrashResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("booklist.v1.ReadlistService.RestoreFromTrash is not implemented"))
+}
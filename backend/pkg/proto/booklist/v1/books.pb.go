@@ -0,0 +1,449 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: booklist/v1/books.proto
+
+package booklistv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SearchBooksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// A two-letter ISO 639-1 code. Optional.
+	Lang          string `protobuf:"bytes,2,opt,name=lang,proto3" json:"lang,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchBooksRequest) Reset() {
+	*x = SearchBooksRequest{}
+	mi := &file_booklist_v1_books_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchBooksRequest) ProtoMessage() {}
+
+func (x *SearchBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_books_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchBooksRequest.ProtoReflect.Descriptor instead.
+func (*SearchBooksRequest) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_books_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SearchBooksRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchBooksRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+type SearchBooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*SearchResult        `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchBooksResponse) Reset() {
+	*x = SearchBooksResponse{}
+	mi := &file_booklist_v1_books_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchBooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchBooksResponse) ProtoMessage() {}
+
+func (x *SearchBooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_books_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchBooksResponse.ProtoReflect.Descriptor instead.
+func (*SearchBooksResponse) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_books_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SearchBooksResponse) GetResults() []*SearchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type SearchResult struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	WorkId  string                 `protobuf:"bytes,1,opt,name=work_id,json=workId,proto3" json:"work_id,omitempty"`
+	Title   string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Authors []string               `protobuf:"bytes,3,rep,name=authors,proto3" json:"authors,omitempty"`
+	// Zero when Open Library does not know it.
+	FirstPublishYear int32 `protobuf:"varint,4,opt,name=first_publish_year,json=firstPublishYear,proto3" json:"first_publish_year,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SearchResult) Reset() {
+	*x = SearchResult{}
+	mi := &file_booklist_v1_books_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResult) ProtoMessage() {}
+
+func (x *SearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_books_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResult.ProtoReflect.Descriptor instead.
+func (*SearchResult) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_books_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SearchResult) GetWorkId() string {
+	if x != nil {
+		return x.WorkId
+	}
+	return ""
+}
+
+func (x *SearchResult) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *SearchResult) GetAuthors() []string {
+	if x != nil {
+		return x.Authors
+	}
+	return nil
+}
+
+func (x *SearchResult) GetFirstPublishYear() int32 {
+	if x != nil {
+		return x.FirstPublishYear
+	}
+	return 0
+}
+
+type GetBookDetailsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkId        string                 `protobuf:"bytes,1,opt,name=work_id,json=workId,proto3" json:"work_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBookDetailsRequest) Reset() {
+	*x = GetBookDetailsRequest{}
+	mi := &file_booklist_v1_books_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBookDetailsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBookDetailsRequest) ProtoMessage() {}
+
+func (x *GetBookDetailsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_books_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBookDetailsRequest.ProtoReflect.Descriptor instead.
+func (*GetBookDetailsRequest) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_books_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetBookDetailsRequest) GetWorkId() string {
+	if x != nil {
+		return x.WorkId
+	}
+	return ""
+}
+
+type GetBookDetailsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Subjects      []string               `protobuf:"bytes,3,rep,name=subjects,proto3" json:"subjects,omitempty"`
+	Links         []*Link                `protobuf:"bytes,4,rep,name=links,proto3" json:"links,omitempty"`
+	CoverArtUrl   string                 `protobuf:"bytes,5,opt,name=cover_art_url,json=coverArtUrl,proto3" json:"cover_art_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBookDetailsResponse) Reset() {
+	*x = GetBookDetailsResponse{}
+	mi := &file_booklist_v1_books_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBookDetailsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBookDetailsResponse) ProtoMessage() {}
+
+func (x *GetBookDetailsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_books_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBookDetailsResponse.ProtoReflect.Descriptor instead.
+func (*GetBookDetailsResponse) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_books_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetBookDetailsResponse) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *GetBookDetailsResponse) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *GetBookDetailsResponse) GetSubjects() []string {
+	if x != nil {
+		return x.Subjects
+	}
+	return nil
+}
+
+func (x *GetBookDetailsResponse) GetLinks() []*Link {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+func (x *GetBookDetailsResponse) GetCoverArtUrl() string {
+	if x != nil {
+		return x.CoverArtUrl
+	}
+	return ""
+}
+
+type Link struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Link) Reset() {
+	*x = Link{}
+	mi := &file_booklist_v1_books_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Link) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Link) ProtoMessage() {}
+
+func (x *Link) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_books_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Link.ProtoReflect.Descriptor instead.
+func (*Link) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_books_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Link) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Link) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+var File_booklist_v1_books_proto protoreflect.FileDescriptor
+
+const file_booklist_v1_books_proto_rawDesc = "" +
+	"\n" +
+	"\x17booklist/v1/books.proto\x12\vbooklist.v1\">\n" +
+	"\x12SearchBooksRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x12\n" +
+	"\x04lang\x18\x02 \x01(\tR\x04lang\"J\n" +
+	"\x13SearchBooksResponse\x123\n" +
+	"\aresults\x18\x01 \x03(\v2\x19.booklist.v1.SearchResultR\aresults\"\x85\x01\n" +
+	"\fSearchResult\x12\x17\n" +
+	"\awork_id\x18\x01 \x01(\tR\x06workId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
+	"\aauthors\x18\x03 \x03(\tR\aauthors\x12,\n" +
+	"\x12first_publish_year\x18\x04 \x01(\x05R\x10firstPublishYear\"0\n" +
+	"\x15GetBookDetailsRequest\x12\x17\n" +
+	"\awork_id\x18\x01 \x01(\tR\x06workId\"\xb9\x01\n" +
+	"\x16GetBookDetailsResponse\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bsubjects\x18\x03 \x03(\tR\bsubjects\x12'\n" +
+	"\x05links\x18\x04 \x03(\v2\x11.booklist.v1.LinkR\x05links\x12\"\n" +
+	"\rcover_art_url\x18\x05 \x01(\tR\vcoverArtUrl\".\n" +
+	"\x04Link\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url2\xc4\x01\n" +
+	"\vBookService\x12U\n" +
+	"\vSearchBooks\x12\x1f.booklist.v1.SearchBooksRequest\x1a .booklist.v1.SearchBooksResponse\"\x03\x90\x02\x01\x12^\n" +
+	"\x0eGetBookDetails\x12\".booklist.v1.GetBookDetailsRequest\x1a#.booklist.v1.GetBookDetailsResponse\"\x03\x90\x02\x01BDZBgithub.com/dcrespo1/book-list-app/pkg/proto/booklist/v1;booklistv1b\x06proto3"
+
+var (
+	file_booklist_v1_books_proto_rawDescOnce sync.Once
+	file_booklist_v1_books_proto_rawDescData []byte
+)
+
+func file_booklist_v1_books_proto_rawDescGZIP() []byte {
+	file_booklist_v1_books_proto_rawDescOnce.Do(func() {
+		file_booklist_v1_books_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_booklist_v1_books_proto_rawDesc), len(file_booklist_v1_books_proto_rawDesc)))
+	})
+	return file_booklist_v1_books_proto_rawDescData
+}
+
+var file_booklist_v1_books_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_booklist_v1_books_proto_goTypes = []any{
+	(*SearchBooksRequest)(nil),     // 0: booklist.v1.SearchBooksRequest
+	(*SearchBooksResponse)(nil),    // 1: booklist.v1.SearchBooksResponse
+	(*SearchResult)(nil),           // 2: booklist.v1.SearchResult
+	(*GetBookDetailsRequest)(nil),  // 3: booklist.v1.GetBookDetailsRequest
+	(*GetBookDetailsResponse)(nil), // 4: booklist.v1.GetBookDetailsResponse
+	(*Link)(nil),                   // 5: booklist.v1.Link
+}
+var file_booklist_v1_books_proto_depIdxs = []int32{
+	2, // 0: booklist.v1.SearchBooksResponse.results:type_name -> booklist.v1.SearchResult
+	5, // 1: booklist.v1.GetBookDetailsResponse.links:type_name -> booklist.v1.Link
+	0, // 2: booklist.v1.BookService.SearchBooks:input_type -> booklist.v1.SearchBooksRequest
+	3, // 3: booklist.v1.BookService.GetBookDetails:input_type -> booklist.v1.GetBookDetailsRequest
+	1, // 4: booklist.v1.BookService.SearchBooks:output_type -> booklist.v1.SearchBooksResponse
+	4, // 5: booklist.v1.BookService.GetBookDetails:output_type -> booklist.v1.GetBookDetailsResponse
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_booklist_v1_books_proto_init() }
+func file_booklist_v1_books_proto_init() {
+	if File_booklist_v1_books_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_booklist_v1_books_proto_rawDesc), len(file_booklist_v1_books_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_booklist_v1_books_proto_goTypes,
+		DependencyIndexes: file_booklist_v1_books_proto_depIdxs,
+		MessageInfos:      file_booklist_v1_books_proto_msgTypes,
+	}.Build()
+	File_booklist_v1_books_proto = out.File
+	file_booklist_v1_books_proto_goTypes = nil
+	file_booklist_v1_books_proto_depIdxs = nil
+}
@@ -0,0 +1,1304 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: booklist/v1/readlist.proto
+
+package booklistv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Status int32
+
+const (
+	Status_STATUS_UNSPECIFIED  Status = 0
+	Status_STATUS_WANT_TO_READ Status = 1
+	Status_STATUS_READING      Status = 2
+	Status_STATUS_FINISHED     Status = 3
+	Status_STATUS_ABANDONED    Status = 4
+)
+
+// Enum value maps for Status.
+var (
+	Status_name = map[int32]string{
+		0: "STATUS_UNSPECIFIED",
+		1: "STATUS_WANT_TO_READ",
+		2: "STATUS_READING",
+		3: "STATUS_FINISHED",
+		4: "STATUS_ABANDONED",
+	}
+	Status_value = map[string]int32{
+		"STATUS_UNSPECIFIED":  0,
+		"STATUS_WANT_TO_READ": 1,
+		"STATUS_READING":      2,
+		"STATUS_FINISHED":     3,
+		"STATUS_ABANDONED":    4,
+	}
+)
+
+func (x Status) Enum() *Status {
+	p := new(Status)
+	*p = x
+	return p
+}
+
+func (x Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_booklist_v1_readlist_proto_enumTypes[0].Descriptor()
+}
+
+func (Status) Type() protoreflect.EnumType {
+	return &file_booklist_v1_readlist_proto_enumTypes[0]
+}
+
+func (x Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Status.Descriptor instead.
+func (Status) EnumDescriptor() ([]byte, []int) {
+	return file_booklist_v1_readlist_proto_rawDescGZIP(), []int{0}
+}
+
+type ReadlistSort int32
+
+const (
+	ReadlistSort_READLIST_SORT_UNSPECIFIED  ReadlistSort = 0
+	ReadlistSort_READLIST_SORT_ADDED_DESC   ReadlistSort = 1
+	ReadlistSort_READLIST_SORT_ADDED_ASC    ReadlistSort = 2
+	ReadlistSort_READLIST_SORT_TITLE        ReadlistSort = 3
+	ReadlistSort_READLIST_SORT_AUTHOR       ReadlistSort = 4
+	ReadlistSort_READLIST_SORT_UPDATED_DESC ReadlistSort = 5
+)
+
+// Enum value maps for ReadlistSort.
+var (
+	ReadlistSort_name = map[int32]string{
+		0: "READLIST_SORT_UNSPECIFIED",
+		1: "READLIST_SORT_ADDED_DESC",
+		2: "READLIST_SORT_ADDED_ASC",
+		3: "READLIST_SORT_TITLE",
+		4: "READLIST_SORT_AUTHOR",
+		5: "READLIST_SORT_UPDATED_DESC",
+	}
+	ReadlistSort_value = map[string]int32{
+		"READLIST_SORT_UNSPECIFIED":  0,
+		"READLIST_SORT_ADDED_DESC":   1,
+		"READLIST_SORT_ADDED_ASC":    2,
+		"READLIST_SORT_TITLE":        3,
+		"READLIST_SORT_AUTHOR":       4,
+		"READLIST_SORT_UPDATED_DESC": 5,
+	}
+)
+
+func (x ReadlistSort) Enum() *ReadlistSort {
+	p := new(ReadlistSort)
+	*p = x
+	return p
+}
+
+func (x ReadlistSort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReadlistSort) Descriptor() protoreflect.EnumDescriptor {
+	return file_booklist_v1_readlist_proto_enumTypes[1].Descriptor()
+}
+
+func (ReadlistSort) Type() protoreflect.EnumType {
+	return &file_booklist_v1_readlist_proto_enumTypes[1]
+}
+
+func (x ReadlistSort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReadlistSort.Descriptor instead.
+func (ReadlistSort) EnumDescriptor() ([]byte, []int) {
+	return file_booklist_v1_readlist_proto_rawDescGZIP(), []int{1}
+}
+
+type ReadlistEntry struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	WorkId      string                 `protobuf:"bytes,2,opt,name=work_id,json=workId,proto3" json:"work_id,omitempty"`
+	Title       string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Authors     string                 `protobuf:"bytes,4,opt,name=authors,proto3" json:"authors,omitempty"`
+	Subjects    *string                `protobuf:"bytes,5,opt,name=subjects,proto3,oneof" json:"subjects,omitempty"`
+	Description *string                `protobuf:"bytes,6,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	CoverArtUrl *string                `protobuf:"bytes,7,opt,name=cover_art_url,json=coverArtUrl,proto3,oneof" json:"cover_art_url,omitempty"`
+	Status      Status                 `protobuf:"varint,8,opt,name=status,proto3,enum=booklist.v1.Status" json:"status,omitempty"`
+	// 1 to 5.
+	Rating *int32  `protobuf:"varint,9,opt,name=rating,proto3,oneof" json:"rating,omitempty"`
+	Notes  *string `protobuf:"bytes,10,opt,name=notes,proto3,oneof" json:"notes,omitempty"`
+	// Changes on every update; send it as if_match to guard against lost updates.
+	Version   int32                  `protobuf:"varint,11,opt,name=version,proto3" json:"version,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Only set on entries in the trash.
+	DeletedAt *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	// Only ListReadlist sets it.
+	Owned         []*OwnedFormat `protobuf:"bytes,14,rep,name=owned,proto3" json:"owned,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadlistEntry) Reset() {
+	*x = ReadlistEntry{}
+	mi := &file_booklist_v1_readlist_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadlistEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadlistEntry) ProtoMessage() {}
+
+func (x *ReadlistEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_readlist_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadlistEntry.ProtoReflect.Descriptor instead.
+func (*ReadlistEntry) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_readlist_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ReadlistEntry) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ReadlistEntry) GetWorkId() string {
+	if x != nil {
+		return x.WorkId
+	}
+	return ""
+}
+
+func (x *ReadlistEntry) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ReadlistEntry) GetAuthors() string {
+	if x != nil {
+		return x.Authors
+	}
+	return ""
+}
+
+func (x *ReadlistEntry) GetSubjects() string {
+	if x != nil && x.Subjects != nil {
+		return *x.Subjects
+	}
+	return ""
+}
+
+func (x *ReadlistEntry) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *ReadlistEntry) GetCoverArtUrl() string {
+	if x != nil && x.CoverArtUrl != nil {
+		return *x.CoverArtUrl
+	}
+	return ""
+}
+
+func (x *ReadlistEntry) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+func (x *ReadlistEntry) GetRating() int32 {
+	if x != nil && x.Rating != nil {
+		return *x.Rating
+	}
+	return 0
+}
+
+func (x *ReadlistEntry) GetNotes() string {
+	if x != nil && x.Notes != nil {
+		return *x.Notes
+	}
+	return ""
+}
+
+func (x *ReadlistEntry) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ReadlistEntry) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *ReadlistEntry) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *ReadlistEntry) GetOwned() []*OwnedFormat {
+	if x != nil {
+		return x.Owned
+	}
+	return nil
+}
+
+type OwnedFormat struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Format string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`
+	// YYYY-MM-DD.
+	PurchasedOn   *string                `protobuf:"bytes,2,opt,name=purchased_on,json=purchasedOn,proto3,oneof" json:"purchased_on,omitempty"`
+	PriceCents    *int32                 `protobuf:"varint,3,opt,name=price_cents,json=priceCents,proto3,oneof" json:"price_cents,omitempty"`
+	Currency      *string                `protobuf:"bytes,4,opt,name=currency,proto3,oneof" json:"currency,omitempty"`
+	Store         *string                `protobuf:"bytes,5,opt,name=store,proto3,oneof" json:"store,omitempty"`
+	Source        *string                `protobuf:"bytes,6,opt,name=source,proto3,oneof" json:"source,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OwnedFormat) Reset() {
+	*x = OwnedFormat{}
+	mi := &file_booklist_v1_readlist_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OwnedFormat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OwnedFormat) ProtoMessage() {}
+
+func (x *OwnedFormat) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_readlist_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OwnedFormat.ProtoReflect.Descriptor instead.
+func (*OwnedFormat) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_readlist_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OwnedFormat) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *OwnedFormat) GetPurchasedOn() string {
+	if x != nil && x.PurchasedOn != nil {
+		return *x.PurchasedOn
+	}
+	return ""
+}
+
+func (x *OwnedFormat) GetPriceCents() int32 {
+	if x != nil && x.PriceCents != nil {
+		return *x.PriceCents
+	}
+	return 0
+}
+
+func (x *OwnedFormat) GetCurrency() string {
+	if x != nil && x.Currency != nil {
+		return *x.Currency
+	}
+	return ""
+}
+
+func (x *OwnedFormat) GetStore() string {
+	if x != nil && x.Store != nil {
+		return *x.Store
+	}
+	return ""
+}
+
+func (x *OwnedFormat) GetSource() string {
+	if x != nil && x.Source != nil {
+		return *x.Source
+	}
+	return ""
+}
+
+func (x *OwnedFormat) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ListReadlistRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Sort  ReadlistSort           `protobuf:"varint,1,opt,name=sort,proto3,enum=booklist.v1.ReadlistSort" json:"sort,omitempty"`
+	// Unspecified lists every status.
+	Status        Status `protobuf:"varint,2,opt,name=status,proto3,enum=booklist.v1.Status" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReadlistRequest) Reset() {
+	*x = ListReadlistRequest{}
+	mi := &file_booklist_v1_readlist_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReadlistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReadlistRequest) ProtoMessage() {}
+
+func (x *ListReadlistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_readlist_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReadlistRequest.ProtoReflect.Descriptor instead.
+func (*ListReadlistRequest) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_readlist_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListReadlistRequest) GetSort() ReadlistSort {
+	if x != nil {
+		return x.Sort
+	}
+	return ReadlistSort_READLIST_SORT_UNSPECIFIED
+}
+
+func (x *ListReadlistRequest) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+type ListReadlistResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*ReadlistEntry       `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReadlistResponse) Reset() {
+	*x = ListReadlistResponse{}
+	mi := &file_booklist_v1_readlist_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReadlistResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReadlistResponse) ProtoMessage() {}
+
+func (x *ListReadlistResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_readlist_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReadlistResponse.ProtoReflect.Descriptor instead.
+func (*ListReadlistResponse) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_readlist_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListReadlistResponse) GetEntries() []*ReadlistEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type GetReadlistEntryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkId        string                 `protobuf:"bytes,1,opt,name=work_id,json=workId,proto3" json:"work_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReadlistEntryRequest) Reset() {
+	*x = GetReadlistEntryRequest{}
+	mi := &file_booklist_v1_readlist_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReadlistEntryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReadlistEntryRequest) ProtoMessage() {}
+
+func (x *GetReadlistEntryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_readlist_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReadlistEntryRequest.ProtoReflect.Descriptor instead.
+func (*GetReadlistEntryRequest) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_readlist_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetReadlistEntryRequest) GetWorkId() string {
+	if x != nil {
+		return x.WorkId
+	}
+	return ""
+}
+
+type GetReadlistEntryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entry         *ReadlistEntry         `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReadlistEntryResponse) Reset() {
+	*x = GetReadlistEntryResponse{}
+	mi := &file_booklist_v1_readlist_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReadlistEntryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReadlistEntryResponse) ProtoMessage() {}
+
+func (x *GetReadlistEntryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_readlist_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReadlistEntryResponse.ProtoReflect.Descriptor instead.
+func (*GetReadlistEntryResponse) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_readlist_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetReadlistEntryResponse) GetEntry() *ReadlistEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+type AddToReadlistRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkId        string                 `protobuf:"bytes,1,opt,name=work_id,json=workId,proto3" json:"work_id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Authors       string                 `protobuf:"bytes,3,opt,name=authors,proto3" json:"authors,omitempty"`
+	Subjects      *string                `protobuf:"bytes,4,opt,name=subjects,proto3,oneof" json:"subjects,omitempty"`
+	Description   *string                `protobuf:"bytes,5,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	CoverArtUrl   *string                `protobuf:"bytes,6,opt,name=cover_art_url,json=coverArtUrl,proto3,oneof" json:"cover_art_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddToReadlistRequest) Reset() {
+	*x = AddToReadlistRequest{}
+	mi := &file_booklist_v1_readlist_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddToReadlistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddToReadlistRequest) ProtoMessage() {}
+
+func (x *AddToReadlistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_readlist_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddToReadlistRequest.ProtoReflect.Descriptor instead.
+func (*AddToReadlistRequest) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_readlist_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AddToReadlistRequest) GetWorkId() string {
+	if x != nil {
+		return x.WorkId
+	}
+	return ""
+}
+
+func (x *AddToReadlistRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *AddToReadlistRequest) GetAuthors() string {
+	if x != nil {
+		return x.Authors
+	}
+	return ""
+}
+
+func (x *AddToReadlistRequest) GetSubjects() string {
+	if x != nil && x.Subjects != nil {
+		return *x.Subjects
+	}
+	return ""
+}
+
+func (x *AddToReadlistRequest) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *AddToReadlistRequest) GetCoverArtUrl() string {
+	if x != nil && x.CoverArtUrl != nil {
+		return *x.CoverArtUrl
+	}
+	return ""
+}
+
+type AddToReadlistResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entry         *ReadlistEntry         `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddToReadlistResponse) Reset() {
+	*x = AddToReadlistResponse{}
+	mi := &file_booklist_v1_readlist_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddToReadlistResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddToReadlistResponse) ProtoMessage() {}
+
+func (x *AddToReadlistResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_readlist_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddToReadlistResponse.ProtoReflect.Descriptor instead.
+func (*AddToReadlistResponse) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_readlist_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AddToReadlistResponse) GetEntry() *ReadlistEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+type UpdateReadlistEntryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Unspecified leaves the status unchanged.
+	Status Status `protobuf:"varint,2,opt,name=status,proto3,enum=booklist.v1.Status" json:"status,omitempty"`
+	// Set to change the rating, 1 to 5; clear_rating removes it.
+	Rating      *int32 `protobuf:"varint,3,opt,name=rating,proto3,oneof" json:"rating,omitempty"`
+	ClearRating bool   `protobuf:"varint,4,opt,name=clear_rating,json=clearRating,proto3" json:"clear_rating,omitempty"`
+	// Set to change the notes; clear_notes removes them.
+	Notes      *string `protobuf:"bytes,5,opt,name=notes,proto3,oneof" json:"notes,omitempty"`
+	ClearNotes bool    `protobuf:"varint,6,opt,name=clear_notes,json=clearNotes,proto3" json:"clear_notes,omitempty"`
+	// When set, the entry must still be at this version.
+	IfMatch       *int32 `protobuf:"varint,7,opt,name=if_match,json=ifMatch,proto3,oneof" json:"if_match,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateReadlistEntryRequest) Reset() {
+	*x = UpdateReadlistEntryRequest{}
+	mi := &file_booklist_v1_readlist_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateReadlistEntryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateReadlistEntryRequest) ProtoMessage() {}
+
+func (x *UpdateReadlistEntryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_readlist_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateReadlistEntryRequest.ProtoReflect.Descriptor instead.
+func (*UpdateReadlistEntryRequest) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_readlist_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateReadlistEntryRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateReadlistEntryRequest) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+func (x *UpdateReadlistEntryRequest) GetRating() int32 {
+	if x != nil && x.Rating != nil {
+		return *x.Rating
+	}
+	return 0
+}
+
+func (x *UpdateReadlistEntryRequest) GetClearRating() bool {
+	if x != nil {
+		return x.ClearRating
+	}
+	return false
+}
+
+func (x *UpdateReadlistEntryRequest) GetNotes() string {
+	if x != nil && x.Notes != nil {
+		return *x.Notes
+	}
+	return ""
+}
+
+func (x *UpdateReadlistEntryRequest) GetClearNotes() bool {
+	if x != nil {
+		return x.ClearNotes
+	}
+	return false
+}
+
+func (x *UpdateReadlistEntryRequest) GetIfMatch() int32 {
+	if x != nil && x.IfMatch != nil {
+		return *x.IfMatch
+	}
+	return 0
+}
+
+type UpdateReadlistEntryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entry         *ReadlistEntry         `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateReadlistEntryResponse) Reset() {
+	*x = UpdateReadlistEntryResponse{}
+	mi := &file_booklist_v1_readlist_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateReadlistEntryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateReadlistEntryResponse) ProtoMessage() {}
+
+func (x *UpdateReadlistEntryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_readlist_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateReadlistEntryResponse.ProtoReflect.Descriptor instead.
+func (*UpdateReadlistEntryResponse) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_readlist_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateReadlistEntryResponse) GetEntry() *ReadlistEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+type DeleteReadlistEntryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// When set, the entry must still be at this version.
+	IfMatch       *int32 `protobuf:"varint,2,opt,name=if_match,json=ifMatch,proto3,oneof" json:"if_match,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteReadlistEntryRequest) Reset() {
+	*x = DeleteReadlistEntryRequest{}
+	mi := &file_booklist_v1_readlist_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteReadlistEntryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReadlistEntryRequest) ProtoMessage() {}
+
+func (x *DeleteReadlistEntryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_readlist_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReadlistEntryRequest.ProtoReflect.Descriptor instead.
+func (*DeleteReadlistEntryRequest) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_readlist_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteReadlistEntryRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeleteReadlistEntryRequest) GetIfMatch() int32 {
+	if x != nil && x.IfMatch != nil {
+		return *x.IfMatch
+	}
+	return 0
+}
+
+type DeleteReadlistEntryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteReadlistEntryResponse) Reset() {
+	*x = DeleteReadlistEntryResponse{}
+	mi := &file_booklist_v1_readlist_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteReadlistEntryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReadlistEntryResponse) ProtoMessage() {}
+
+func (x *DeleteReadlistEntryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_readlist_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReadlistEntryResponse.ProtoReflect.Descriptor instead.
+func (*DeleteReadlistEntryResponse) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_readlist_proto_rawDescGZIP(), []int{11}
+}
+
+type ListTrashRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrashRequest) Reset() {
+	*x = ListTrashRequest{}
+	mi := &file_booklist_v1_readlist_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrashRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrashRequest) ProtoMessage() {}
+
+func (x *ListTrashRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_readlist_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrashRequest.ProtoReflect.Descriptor instead.
+func (*ListTrashRequest) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_readlist_proto_rawDescGZIP(), []int{12}
+}
+
+type ListTrashResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*ReadlistEntry       `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrashResponse) Reset() {
+	*x = ListTrashResponse{}
+	mi := &file_booklist_v1_readlist_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrashResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrashResponse) ProtoMessage() {}
+
+func (x *ListTrashResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_readlist_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrashResponse.ProtoReflect.Descriptor instead.
+func (*ListTrashResponse) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_readlist_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListTrashResponse) GetEntries() []*ReadlistEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type RestoreFromTrashRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreFromTrashRequest) Reset() {
+	*x = RestoreFromTrashRequest{}
+	mi := &file_booklist_v1_readlist_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreFromTrashRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreFromTrashRequest) ProtoMessage() {}
+
+func (x *RestoreFromTrashRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_readlist_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreFromTrashRequest.ProtoReflect.Descriptor instead.
+func (*RestoreFromTrashRequest) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_readlist_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RestoreFromTrashRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type RestoreFromTrashResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entry         *ReadlistEntry         `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreFromTrashResponse) Reset() {
+	*x = RestoreFromTrashResponse{}
+	mi := &file_booklist_v1_readlist_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreFromTrashResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreFromTrashResponse) ProtoMessage() {}
+
+func (x *RestoreFromTrashResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_booklist_v1_readlist_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreFromTrashResponse.ProtoReflect.Descriptor instead.
+func (*RestoreFromTrashResponse) Descriptor() ([]byte, []int) {
+	return file_booklist_v1_readlist_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RestoreFromTrashResponse) GetEntry() *ReadlistEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+var File_booklist_v1_readlist_proto protoreflect.FileDescriptor
+
+const file_booklist_v1_readlist_proto_rawDesc = "" +
+	"\n" +
+	"\x1abooklist/v1/readlist.proto\x12\vbooklist.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc2\x04\n" +
+	"\rReadlistEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x17\n" +
+	"\awork_id\x18\x02 \x01(\tR\x06workId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x18\n" +
+	"\aauthors\x18\x04 \x01(\tR\aauthors\x12\x1f\n" +
+	"\bsubjects\x18\x05 \x01(\tH\x00R\bsubjects\x88\x01\x01\x12%\n" +
+	"\vdescription\x18\x06 \x01(\tH\x01R\vdescription\x88\x01\x01\x12'\n" +
+	"\rcover_art_url\x18\a \x01(\tH\x02R\vcoverArtUrl\x88\x01\x01\x12+\n" +
+	"\x06status\x18\b \x01(\x0e2\x13.booklist.v1.StatusR\x06status\x12\x1b\n" +
+	"\x06rating\x18\t \x01(\x05H\x03R\x06rating\x88\x01\x01\x12\x19\n" +
+	"\x05notes\x18\n" +
+	" \x01(\tH\x04R\x05notes\x88\x01\x01\x12\x18\n" +
+	"\aversion\x18\v \x01(\x05R\aversion\x129\n" +
+	"\n" +
+	"updated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x129\n" +
+	"\n" +
+	"deleted_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12.\n" +
+	"\x05owned\x18\x0e \x03(\v2\x18.booklist.v1.OwnedFormatR\x05ownedB\v\n" +
+	"\t_subjectsB\x0e\n" +
+	"\f_descriptionB\x10\n" +
+	"\x0e_cover_art_urlB\t\n" +
+	"\a_ratingB\b\n" +
+	"\x06_notes\"\xca\x02\n" +
+	"\vOwnedFormat\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12&\n" +
+	"\fpurchased_on\x18\x02 \x01(\tH\x00R\vpurchasedOn\x88\x01\x01\x12$\n" +
+	"\vprice_cents\x18\x03 \x01(\x05H\x01R\n" +
+	"priceCents\x88\x01\x01\x12\x1f\n" +
+	"\bcurrency\x18\x04 \x01(\tH\x02R\bcurrency\x88\x01\x01\x12\x19\n" +
+	"\x05store\x18\x05 \x01(\tH\x03R\x05store\x88\x01\x01\x12\x1b\n" +
+	"\x06source\x18\x06 \x01(\tH\x04R\x06source\x88\x01\x01\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\x0f\n" +
+	"\r_purchased_onB\x0e\n" +
+	"\f_price_centsB\v\n" +
+	"\t_currencyB\b\n" +
+	"\x06_storeB\t\n" +
+	"\a_source\"q\n" +
+	"\x13ListReadlistRequest\x12-\n" +
+	"\x04sort\x18\x01 \x01(\x0e2\x19.booklist.v1.ReadlistSortR\x04sort\x12+\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x13.booklist.v1.StatusR\x06status\"L\n" +
+	"\x14ListReadlistResponse\x124\n" +
+	"\aentries\x18\x01 \x03(\v2\x1a.booklist.v1.ReadlistEntryR\aentries\"2\n" +
+	"\x17GetReadlistEntryRequest\x12\x17\n" +
+	"\awork_id\x18\x01 \x01(\tR\x06workId\"L\n" +
+	"\x18GetReadlistEntryResponse\x120\n" +
+	"\x05entry\x18\x01 \x01(\v2\x1a.booklist.v1.ReadlistEntryR\x05entry\"\xff\x01\n" +
+	"\x14AddToReadlistRequest\x12\x17\n" +
+	"\awork_id\x18\x01 \x01(\tR\x06workId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
+	"\aauthors\x18\x03 \x01(\tR\aauthors\x12\x1f\n" +
+	"\bsubjects\x18\x04 \x01(\tH\x00R\bsubjects\x88\x01\x01\x12%\n" +
+	"\vdescription\x18\x05 \x01(\tH\x01R\vdescription\x88\x01\x01\x12'\n" +
+	"\rcover_art_url\x18\x06 \x01(\tH\x02R\vcoverArtUrl\x88\x01\x01B\v\n" +
+	"\t_subjectsB\x0e\n" +
+	"\f_descriptionB\x10\n" +
+	"\x0e_cover_art_url\"I\n" +
+	"\x15AddToReadlistResponse\x120\n" +
+	"\x05entry\x18\x01 \x01(\v2\x1a.booklist.v1.ReadlistEntryR\x05entry\"\x97\x02\n" +
+	"\x1aUpdateReadlistEntryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12+\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x13.booklist.v1.StatusR\x06status\x12\x1b\n" +
+	"\x06rating\x18\x03 \x01(\x05H\x00R\x06rating\x88\x01\x01\x12!\n" +
+	"\fclear_rating\x18\x04 \x01(\bR\vclearRating\x12\x19\n" +
+	"\x05notes\x18\x05 \x01(\tH\x01R\x05notes\x88\x01\x01\x12\x1f\n" +
+	"\vclear_notes\x18\x06 \x01(\bR\n" +
+	"clearNotes\x12\x1e\n" +
+	"\bif_match\x18\a \x01(\x05H\x02R\aifMatch\x88\x01\x01B\t\n" +
+	"\a_ratingB\b\n" +
+	"\x06_notesB\v\n" +
+	"\t_if_match\"O\n" +
+	"\x1bUpdateReadlistEntryResponse\x120\n" +
+	"\x05entry\x18\x01 \x01(\v2\x1a.booklist.v1.ReadlistEntryR\x05entry\"Y\n" +
+	"\x1aDeleteReadlistEntryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1e\n" +
+	"\bif_match\x18\x02 \x01(\x05H\x00R\aifMatch\x88\x01\x01B\v\n" +
+	"\t_if_match\"\x1d\n" +
+	"\x1bDeleteReadlistEntryResponse\"\x12\n" +
+	"\x10ListTrashRequest\"I\n" +
+	"\x11ListTrashResponse\x124\n" +
+	"\aentries\x18\x01 \x03(\v2\x1a.booklist.v1.ReadlistEntryR\aentries\")\n" +
+	"\x17RestoreFromTrashRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"L\n" +
+	"\x18RestoreFromTrashResponse\x120\n" +
+	"\x05entry\x18\x01 \x01(\v2\x1a.booklist.v1.ReadlistEntryR\x05entry*x\n" +
+	"\x06Status\x12\x16\n" +
+	"\x12STATUS_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13STATUS_WANT_TO_READ\x10\x01\x12\x12\n" +
+	"\x0eSTATUS_READING\x10\x02\x12\x13\n" +
+	"\x0fSTATUS_FINISHED\x10\x03\x12\x14\n" +
+	"\x10STATUS_ABANDONED\x10\x04*\xbb\x01\n" +
+	"\fReadlistSort\x12\x1d\n" +
+	"\x19READLIST_SORT_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18READLIST_SORT_ADDED_DESC\x10\x01\x12\x1b\n" +
+	"\x17READLIST_SORT_ADDED_ASC\x10\x02\x12\x17\n" +
+	"\x13READLIST_SORT_TITLE\x10\x03\x12\x18\n" +
+	"\x14READLIST_SORT_AUTHOR\x10\x04\x12\x1e\n" +
+	"\x1aREADLIST_SORT_UPDATED_DESC\x10\x052\xaf\x05\n" +
+	"\x0fReadlistService\x12X\n" +
+	"\fListReadlist\x12 .booklist.v1.ListReadlistRequest\x1a!.booklist.v1.ListReadlistResponse\"\x03\x90\x02\x01\x12d\n" +
+	"\x10GetReadlistEntry\x12$.booklist.v1.GetReadlistEntryRequest\x1a%.booklist.v1.GetReadlistEntryResponse\"\x03\x90\x02\x01\x12V\n" +
+	"\rAddToReadlist\x12!.booklist.v1.AddToReadlistRequest\x1a\".booklist.v1.AddToReadlistResponse\x12h\n" +
+	"\x13UpdateReadlistEntry\x12'.booklist.v1.UpdateReadlistEntryRequest\x1a(.booklist.v1.UpdateReadlistEntryResponse\x12h\n" +
+	"\x13DeleteReadlistEntry\x12'.booklist.v1.DeleteReadlistEntryRequest\x1a(.booklist.v1.DeleteReadlistEntryResponse\x12O\n" +
+	"\tListTrash\x12\x1d.booklist.v1.ListTrashRequest\x1a\x1e.booklist.v1.ListTrashResponse\"\x03\x90\x02\x01\x12_\n" +
+	"\x10RestoreFromTrash\x12$.booklist.v1.RestoreFromTrashRequest\x1a%.booklist.v1.RestoreFromTrashResponseBDZBgithub.com/dcrespo1/book-list-app/pkg/proto/booklist/v1;booklistv1b\x06proto3"
+
+var (
+	file_booklist_v1_readlist_proto_rawDescOnce sync.Once
+	file_booklist_v1_readlist_proto_rawDescData []byte
+)
+
+func file_booklist_v1_readlist_proto_rawDescGZIP() []byte {
+	file_booklist_v1_readlist_proto_rawDescOnce.Do(func() {
+		file_booklist_v1_readlist_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_booklist_v1_readlist_proto_rawDesc), len(file_booklist_v1_readlist_proto_rawDesc)))
+	})
+	return file_booklist_v1_readlist_proto_rawDescData
+}
+
+var file_booklist_v1_readlist_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_booklist_v1_readlist_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_booklist_v1_readlist_proto_goTypes = []any{
+	(Status)(0),                         // 0: booklist.v1.Status
+	(ReadlistSort)(0),                   // 1: booklist.v1.ReadlistSort
+	(*ReadlistEntry)(nil),               // 2: booklist.v1.ReadlistEntry
+	(*OwnedFormat)(nil),                 // 3: booklist.v1.OwnedFormat
+	(*ListReadlistRequest)(nil),         // 4: booklist.v1.ListReadlistRequest
+	(*ListReadlistResponse)(nil),        // 5: booklist.v1.ListReadlistResponse
+	(*GetReadlistEntryRequest)(nil),     // 6: booklist.v1.GetReadlistEntryRequest
+	(*GetReadlistEntryResponse)(nil),    // 7: booklist.v1.GetReadlistEntryResponse
+	(*AddToReadlistRequest)(nil),        // 8: booklist.v1.AddToReadlistRequest
+	(*AddToReadlistResponse)(nil),       // 9: booklist.v1.AddToReadlistResponse
+	(*UpdateReadlistEntryRequest)(nil),  // 10: booklist.v1.UpdateReadlistEntryRequest
+	(*UpdateReadlistEntryResponse)(nil), // 11: booklist.v1.UpdateReadlistEntryResponse
+	(*DeleteReadlistEntryRequest)(nil),  // 12: booklist.v1.DeleteReadlistEntryRequest
+	(*DeleteReadlistEntryResponse)(nil), // 13: booklist.v1.DeleteReadlistEntryResponse
+	(*ListTrashRequest)(nil),            // 14: booklist.v1.ListTrashRequest
+	(*ListTrashResponse)(nil),           // 15: booklist.v1.ListTrashResponse
+	(*RestoreFromTrashRequest)(nil),     // 16: booklist.v1.RestoreFromTrashRequest
+	(*RestoreFromTrashResponse)(nil),    // 17: booklist.v1.RestoreFromTrashResponse
+	(*timestamppb.Timestamp)(nil),       // 18: google.protobuf.Timestamp
+}
+var file_booklist_v1_readlist_proto_depIdxs = []int32{
+	0,  // 0: booklist.v1.ReadlistEntry.status:type_name -> booklist.v1.Status
+	18, // 1: booklist.v1.ReadlistEntry.updated_at:type_name -> google.protobuf.Timestamp
+	18, // 2: booklist.v1.ReadlistEntry.deleted_at:type_name -> google.protobuf.Timestamp
+	3,  // 3: booklist.v1.ReadlistEntry.owned:type_name -> booklist.v1.OwnedFormat
+	18, // 4: booklist.v1.OwnedFormat.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 5: booklist.v1.ListReadlistRequest.sort:type_name -> booklist.v1.ReadlistSort
+	0,  // 6: booklist.v1.ListReadlistRequest.status:type_name -> booklist.v1.Status
+	2,  // 7: booklist.v1.ListReadlistResponse.entries:type_name -> booklist.v1.ReadlistEntry
+	2,  // 8: booklist.v1.GetReadlistEntryResponse.entry:type_name -> booklist.v1.ReadlistEntry
+	2,  // 9: booklist.v1.AddToReadlistResponse.entry:type_name -> booklist.v1.ReadlistEntry
+	0,  // 10: booklist.v1.UpdateReadlistEntryRequest.status:type_name -> booklist.v1.Status
+	2,  // 11: booklist.v1.UpdateReadlistEntryResponse.entry:type_name -> booklist.v1.ReadlistEntry
+	2,  // 12: booklist.v1.ListTrashResponse.entries:type_name -> booklist.v1.ReadlistEntry
+	2,  // 13: booklist.v1.RestoreFromTrashResponse.entry:type_name -> booklist.v1.ReadlistEntry
+	4,  // 14: booklist.v1.ReadlistService.ListReadlist:input_type -> booklist.v1.ListReadlistRequest
+	6,  // 15: booklist.v1.ReadlistService.GetReadlistEntry:input_type -> booklist.v1.GetReadlistEntryRequest
+	8,  // 16: booklist.v1.ReadlistService.AddToReadlist:input_type -> booklist.v1.AddToReadlistRequest
+	10, // 17: booklist.v1.ReadlistService.UpdateReadlistEntry:input_type -> booklist.v1.UpdateReadlistEntryRequest
+	12, // 18: booklist.v1.ReadlistService.DeleteReadlistEntry:input_type -> booklist.v1.DeleteReadlistEntryRequest
+	14, // 19: booklist.v1.ReadlistService.ListTrash:input_type -> booklist.v1.ListTrashRequest
+	16, // 20: booklist.v1.ReadlistService.RestoreFromTrash:input_type -> booklist.v1.RestoreFromTrashRequest
+	5,  // 21: booklist.v1.ReadlistService.ListReadlist:output_type -> booklist.v1.ListReadlistResponse
+	7,  // 22: booklist.v1.ReadlistService.GetReadlistEntry:output_type -> booklist.v1.GetReadlistEntryResponse
+	9,  // 23: booklist.v1.ReadlistService.AddToReadlist:output_type -> booklist.v1.AddToReadlistResponse
+	11, // 24: booklist.v1.ReadlistService.UpdateReadlistEntry:output_type -> booklist.v1.UpdateReadlistEntryResponse
+	13, // 25: booklist.v1.ReadlistService.DeleteReadlistEntry:output_type -> booklist.v1.DeleteReadlistEntryResponse
+	15, // 26: booklist.v1.ReadlistService.ListTrash:output_type -> booklist.v1.ListTrashResponse
+	17, // 27: booklist.v1.ReadlistService.RestoreFromTrash:output_type -> booklist.v1.RestoreFromTrashResponse
+	21, // [21:28] is the sub-list for method output_type
+	14, // [14:21] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_booklist_v1_readlist_proto_init() }
+func file_booklist_v1_readlist_proto_init() {
+	if File_booklist_v1_readlist_proto != nil {
+		return
+	}
+	file_booklist_v1_readlist_proto_msgTypes[0].OneofWrappers = []any{}
+	file_booklist_v1_readlist_proto_msgTypes[1].OneofWrappers = []any{}
+	file_booklist_v1_readlist_proto_msgTypes[6].OneofWrappers = []any{}
+	file_booklist_v1_readlist_proto_msgTypes[8].OneofWrappers = []any{}
+	file_booklist_v1_readlist_proto_msgTypes[10].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_booklist_v1_readlist_proto_rawDesc), len(file_booklist_v1_readlist_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_booklist_v1_readlist_proto_goTypes,
+		DependencyIndexes: file_booklist_v1_readlist_proto_depIdxs,
+		EnumInfos:         file_booklist_v1_readlist_proto_enumTypes,
+		MessageInfos:      file_booklist_v1_readlist_proto_msgTypes,
+	}.Build()
+	File_booklist_v1_readlist_proto = out.File
+	file_booklist_v1_readlist_proto_goTypes = nil
+	file_booklist_v1_readlist_proto_depIdxs = nil
+}
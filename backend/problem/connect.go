@@ -0,0 +1,84 @@
+package problem
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrorDomain is the domain of the google.rpc.ErrorInfo carried by RPC errors.
+const ErrorDomain = "booklist"
+
+// ConnectError is the RPC counterpart of a problem of type t. Its code follows t's
+// HTTP status, and it carries a google.rpc.ErrorInfo whose reason is t's code, with
+// the request ID in its metadata when there is one.
+func ConnectError(ctx context.Context, t Type, detail string) *connect.Error {
+	err := connect.NewError(connectCode(t.Status), errors.New(detail))
+	info := &errdetails.ErrorInfo{Reason: t.Code, Domain: ErrorDomain}
+	if id := chimw.GetReqID(ctx); id != "" {
+		info.Metadata = map[string]string{"request_id": id}
+	}
+	addDetail(err, info)
+	return err
+}
+
+// ConnectInvalid is the RPC counterpart of WriteInvalid: a validation_failed error
+// that also carries a google.rpc.BadRequest with a violation per field. Keys are
+// proto field names.
+func ConnectInvalid(ctx context.Context, fields map[string]string) *connect.Error {
+	err := ConnectError(ctx, ValidationFailed, "one or more fields are invalid")
+	bad := &errdetails.BadRequest{}
+	for field, msg := range fields {
+		bad.FieldViolations = append(bad.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: msg,
+		})
+	}
+	slices.SortFunc(bad.FieldViolations, func(a, b *errdetails.BadRequest_FieldViolation) int {
+		return strings.Compare(a.Field, b.Field)
+	})
+	addDetail(err, bad)
+	return err
+}
+
+func addDetail(err *connect.Error, msg proto.Message) {
+	// NewErrorDetail only fails for messages that cannot be marshalled, which the
+	// well-known detail types always can.
+	if detail, e := connect.NewErrorDetail(msg); e == nil {
+		err.AddDetail(detail)
+	}
+}
+
+// connectCode maps an HTTP status to the closest RPC code. Conflicts become
+// already_exists, as a duplicate entry is the only conflict the RPC services meet.
+func connectCode(status int) connect.Code {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity:
+		return connect.CodeInvalidArgument
+	case http.StatusUnauthorized:
+		return connect.CodeUnauthenticated
+	case http.StatusForbidden:
+		return connect.CodePermissionDenied
+	case http.StatusNotFound:
+		return connect.CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return connect.CodeUnimplemented
+	case http.StatusConflict:
+		return connect.CodeAlreadyExists
+	case http.StatusPreconditionFailed:
+		return connect.CodeAborted
+	case http.StatusRequestEntityTooLarge, http.StatusTooManyRequests:
+		return connect.CodeResourceExhausted
+	case http.StatusServiceUnavailable:
+		return connect.CodeUnavailable
+	default:
+		return connect.CodeInternal
+	}
+}
@@ -0,0 +1,72 @@
+package problem
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/go-chi/chi/v5/middleware"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func TestConnectError(t *testing.T) {
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "req-1")
+	err := ConnectError(ctx, VersionMismatch, "book has been modified")
+
+	if err.Code() != connect.CodeAborted {
+		t.Errorf("code: got %v, want %v", err.Code(), connect.CodeAborted)
+	}
+	if err.Message() != "book has been modified" {
+		t.Errorf("message: got %q", err.Message())
+	}
+	if len(err.Details()) != 1 {
+		t.Fatalf("details: got %d, want 1", len(err.Details()))
+	}
+	v, _ := err.Details()[0].Value()
+	info, ok := v.(*errdetails.ErrorInfo)
+	if !ok {
+		t.Fatalf("detail: got %T, want *errdetails.ErrorInfo", v)
+	}
+	if info.Reason != "version_mismatch" || info.Domain != ErrorDomain || info.Metadata["request_id"] != "req-1" {
+		t.Errorf("error info: got %v", info)
+	}
+}
+
+func TestConnectError_EveryTypeHasACode(t *testing.T) {
+	for _, typ := range Types {
+		if typ != Internal && connectCode(typ.Status) == connect.CodeInternal {
+			t.Errorf("%s: status %d maps to internal", typ.Code, typ.Status)
+		}
+	}
+}
+
+func TestConnectInvalid(t *testing.T) {
+	err := ConnectInvalid(context.Background(), map[string]string{
+		"work_id": "is required",
+		"title":   "is required",
+	})
+
+	if err.Code() != connect.CodeInvalidArgument {
+		t.Errorf("code: got %v, want %v", err.Code(), connect.CodeInvalidArgument)
+	}
+	var bad *errdetails.BadRequest
+	for _, d := range err.Details() {
+		if v, _ := d.Value(); v != nil {
+			if b, ok := v.(*errdetails.BadRequest); ok {
+				bad = b
+			}
+		}
+	}
+	if bad == nil {
+		t.Fatal("expected a BadRequest detail")
+	}
+	want := []string{"title", "work_id"}
+	if len(bad.FieldViolations) != len(want) {
+		t.Fatalf("violations: got %v", bad.FieldViolations)
+	}
+	for i, v := range bad.FieldViolations {
+		if v.Field != want[i] || v.Description == "" {
+			t.Errorf("violations[%d]: got %v, want field %s", i, v, want[i])
+		}
+	}
+}
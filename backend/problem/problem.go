@@ -0,0 +1,151 @@
+// Package problem writes error responses as RFC 9457 problem details. Every problem
+// carries a stable code that clients can branch on; the detail text is for people
+// and may change.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+// ContentType is the media type of problem responses.
+const ContentType = "application/problem+json"
+
+// typePath prefixes the type URI of every problem; the code follows it.
+const typePath = "/problems/"
+
+// Type is a kind of problem. Its code never changes once published.
+type Type struct {
+	Code   string
+	Status int
+	Title  string
+}
+
+// URI is the problem type URI, relative to the API's base URL. It resolves to a
+// description of the type served by Describe.
+func (t Type) URI() string {
+	return typePath + t.Code
+}
+
+var (
+	InvalidParameter = Type{"invalid_parameter", http.StatusBadRequest, "Invalid parameter"}
+	InvalidBody      = Type{"invalid_body", http.StatusBadRequest, "Invalid request body"}
+	Unauthenticated  = Type{"unauthenticated", http.StatusUnauthorized, "Authentication required"}
+	InvalidToken     = Type{"invalid_token", http.StatusUnauthorized, "Invalid token"}
+	// MalformedToken is a bearer token that was sent but is empty.
+	MalformedToken    = Type{"malformed_token", http.StatusBadRequest, "Malformed token"}
+	Forbidden         = Type{"forbidden", http.StatusForbidden, "Forbidden"}
+	InsufficientScope = Type{"insufficient_scope", http.StatusForbidden, "Insufficient scope"}
+	NotFound          = Type{"not_found", http.StatusNotFound, "Not found"}
+	MethodNotAllowed  = Type{"method_not_allowed", http.StatusMethodNotAllowed, "Method not allowed"}
+	BookExists        = Type{"book_exists", http.StatusConflict, "Book already in readlist"}
+	BookLentOut       = Type{"book_lent_out", http.StatusConflict, "Book already lent out"}
+	LoanReturned      = Type{"loan_returned", http.StatusConflict, "Loan already returned"}
+	AlreadyMember     = Type{"already_member", http.StatusConflict, "Already a member"}
+	// OwnershipTransferRequired is an owner trying to leave or change their own role.
+	OwnershipTransferRequired = Type{"ownership_transfer_required", http.StatusConflict, "Ownership transfer required"}
+	DeletionScheduled         = Type{"deletion_scheduled", http.StatusConflict, "Account deletion already scheduled"}
+	IdempotencyInProgress     = Type{"idempotency_in_progress", http.StatusConflict, "Request in progress"}
+	// VersionMismatch is an If-Match precondition naming a version that is no longer current.
+	VersionMismatch      = Type{"version_mismatch", http.StatusPreconditionFailed, "Version mismatch"}
+	BodyTooLarge         = Type{"body_too_large", http.StatusRequestEntityTooLarge, "Request body too large"}
+	UnsupportedMediaType = Type{"unsupported_media_type", http.StatusUnsupportedMediaType, "Unsupported media type"}
+	// ValidationFailed lists each offending field in Problem.Errors.
+	ValidationFailed     = Type{"validation_failed", http.StatusUnprocessableEntity, "Validation failed"}
+	InvalidConfirmation  = Type{"invalid_confirmation", http.StatusUnprocessableEntity, "Invalid confirmation"}
+	IdempotencyKeyReused = Type{"idempotency_key_reused", http.StatusUnprocessableEntity, "Idempotency key reused"}
+	RateLimited          = Type{"rate_limited", http.StatusTooManyRequests, "Rate limit exceeded"}
+	Internal             = Type{"internal", http.StatusInternalServerError, "Internal server error"}
+	Unavailable          = Type{"unavailable", http.StatusServiceUnavailable, "Service unavailable"}
+)
+
+// Types lists every problem type the API can return.
+var Types = []Type{
+	InvalidParameter, InvalidBody, Unauthenticated, InvalidToken, MalformedToken,
+	Forbidden, InsufficientScope, NotFound, MethodNotAllowed, BookExists, BookLentOut,
+	LoanReturned, AlreadyMember, OwnershipTransferRequired, DeletionScheduled,
+	IdempotencyInProgress, VersionMismatch, BodyTooLarge, UnsupportedMediaType,
+	ValidationFailed, InvalidConfirmation, IdempotencyKeyReused, RateLimited, Internal,
+	Unavailable,
+}
+
+// Problem is an RFC 9457 problem details object. Code and RequestID are extensions.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError is one invalid field of a request body, located by a JSON Pointer.
+type FieldError struct {
+	Pointer string `json:"pointer"`
+	Detail  string `json:"detail"`
+}
+
+// New builds a problem of type t about request r.
+func New(r *http.Request, t Type, detail string) Problem {
+	return Problem{
+		Type:      t.URI(),
+		Title:     t.Title,
+		Status:    t.Status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		Code:      t.Code,
+		RequestID: chimw.GetReqID(r.Context()),
+	}
+}
+
+// Write responds to r with a problem of type t.
+func Write(w http.ResponseWriter, r *http.Request, t Type, detail string) {
+	New(r, t, detail).Write(w)
+}
+
+// WriteInvalid responds to r with a validation_failed problem listing each field.
+// Keys are JSON field names, with nested names joined by "/" as in
+// "operations/0/notes", or JSON Pointers such as "/0/value"; values describe what
+// is wrong.
+func WriteInvalid(w http.ResponseWriter, r *http.Request, fields map[string]string) {
+	p := New(r, ValidationFailed, "one or more fields are invalid")
+	for field, msg := range fields {
+		if !strings.HasPrefix(field, "/") {
+			field = "/" + strings.ReplaceAll(field, "~", "~0")
+		}
+		p.Errors = append(p.Errors, FieldError{Pointer: "#" + field, Detail: msg})
+	}
+	slices.SortFunc(p.Errors, func(a, b FieldError) int { return strings.Compare(a.Pointer, b.Pointer) })
+	p.Write(w)
+}
+
+func (p Problem) Write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// Describe serves GET /problems/{code}, documenting the type a problem's type URI names.
+func Describe(w http.ResponseWriter, r *http.Request) {
+	code := chi.URLParam(r, "code")
+	i := slices.IndexFunc(Types, func(t Type) bool { return t.Code == code })
+	if i < 0 {
+		Write(w, r, NotFound, "unknown problem type")
+		return
+	}
+	t := Types[i]
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"type":   t.URI(),
+		"code":   t.Code,
+		"title":  t.Title,
+		"status": t.Status,
+	})
+}
@@ -0,0 +1,120 @@
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+func decode(t *testing.T, w *httptest.ResponseRecorder) Problem {
+	t.Helper()
+	if got := w.Header().Get("Content-Type"); got != ContentType {
+		t.Errorf("Content-Type: got %q, want %q", got, ContentType)
+	}
+	var p Problem
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return p
+}
+
+func TestWrite(t *testing.T) {
+	h := chimw.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Write(w, r, BookExists, "book already in readlist")
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/readlist", nil))
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusConflict)
+	}
+	p := decode(t, w)
+	want := Problem{
+		Type:     "/problems/book_exists",
+		Title:    "Book already in readlist",
+		Status:   http.StatusConflict,
+		Detail:   "book already in readlist",
+		Instance: "/readlist",
+		Code:     "book_exists",
+	}
+	if p.RequestID == "" {
+		t.Error("expected a request_id")
+	}
+	want.RequestID = p.RequestID
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("got %+v, want %+v", p, want)
+	}
+}
+
+func TestWriteInvalid(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteInvalid(w, httptest.NewRequest(http.MethodPost, "/readlist/bulk", nil), map[string]string{
+		"operations/1/ids": "is required",
+		"mode":             "must be atomic or partial",
+		"a~b":              "is not a known field",
+		"/0/value":         "is required",
+	})
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+	p := decode(t, w)
+	if p.Code != ValidationFailed.Code {
+		t.Errorf("code: got %q, want %q", p.Code, ValidationFailed.Code)
+	}
+	want := []string{"#/0/value", "#/a~0b", "#/mode", "#/operations/1/ids"}
+	if len(p.Errors) != len(want) {
+		t.Fatalf("errors: got %+v", p.Errors)
+	}
+	for i, e := range p.Errors {
+		if e.Pointer != want[i] || e.Detail == "" {
+			t.Errorf("errors[%d]: got %+v, want pointer %s", i, e, want[i])
+		}
+	}
+}
+
+func TestTypes_CodesAreUnique(t *testing.T) {
+	seen := map[string]bool{}
+	for _, typ := range Types {
+		if seen[typ.Code] {
+			t.Errorf("duplicate code %q", typ.Code)
+		}
+		seen[typ.Code] = true
+	}
+}
+
+func describe(code string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/problems/"+code, nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("code", code)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	Describe(w, r)
+	return w
+}
+
+func TestDescribe(t *testing.T) {
+	w := describe("rate_limited")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	var got struct {
+		Code   string `json:"code"`
+		Status int    `json:"status"`
+	}
+	json.NewDecoder(w.Body).Decode(&got)
+	if got.Code != "rate_limited" || got.Status != http.StatusTooManyRequests {
+		t.Errorf("got %+v", got)
+	}
+
+	if w := describe("no_such_problem"); w.Code != http.StatusNotFound {
+		t.Errorf("unknown code: status: got %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ClientIP works out the address a request came from. X-Forwarded-For is only
+// believed when the connection comes from a trusted proxy, and then only up to the
+// first address that is not itself a trusted proxy, so clients cannot spoof it.
+type ClientIP struct {
+	trusted []netip.Prefix
+}
+
+// NewClientIP trusts the given proxies, each an IP address or CIDR range.
+func NewClientIP(trustedProxies []string) (*ClientIP, error) {
+	c := &ClientIP{}
+	for _, p := range trustedProxies {
+		if !strings.Contains(p, "/") {
+			addr, err := netip.ParseAddr(p)
+			if err != nil {
+				return nil, fmt.Errorf("trusted proxy %q: %w", p, err)
+			}
+			c.trusted = append(c.trusted, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %w", p, err)
+		}
+		c.trusted = append(c.trusted, prefix.Masked())
+	}
+	return c, nil
+}
+
+// Of returns the client address of r. A nil ClientIP trusts no proxies.
+func (c *ClientIP) Of(r *http.Request) string {
+	return c.of(r.RemoteAddr, r.Header)
+}
+
+// of returns the client address of a request from remoteAddr carrying header.
+func (c *ClientIP) of(remoteAddr string, header http.Header) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	peer, err := netip.ParseAddr(host)
+	if err != nil || !c.isTrusted(peer) {
+		return host
+	}
+
+	// Walk the chain from the nearest hop back towards the client.
+	hops := strings.Split(strings.Join(header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		if !c.isTrusted(addr) {
+			return addr.Unmap().String()
+		}
+		peer = addr
+	}
+	return peer.Unmap().String()
+}
+
+func (c *ClientIP) isTrusted(addr netip.Addr) bool {
+	if c == nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range c.trusted {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+// Package ratelimit throttles callers with token buckets. Each budget (a Limit) gives
+// every caller, identified by their token's sub or else their IP address, a bucket of
+// Burst requests that refills evenly over Per. Responses carry RateLimit-* headers;
+// a caller with an empty bucket gets 429 with Retry-After.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/problem"
+)
+
+// Limit is one budget: Burst requests, refilled at Burst per Per.
+type Limit struct {
+	Name  string // prefixes bucket keys, so each budget has its own buckets
+	Burst int
+	Per   time.Duration
+}
+
+// rate is the refill rate in tokens per second.
+func (l Limit) rate() float64 {
+	return float64(l.Burst) / l.Per.Seconds()
+}
+
+// ParseLimit reads a limit written as "<requests>/<duration>", e.g. "60/1m".
+func ParseLimit(name, s string) (Limit, error) {
+	n, per, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("rate limit %q: want <requests>/<duration>", s)
+	}
+	burst, err := strconv.Atoi(n)
+	if err != nil || burst <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q: requests must be a positive integer", s)
+	}
+	d, err := time.ParseDuration(per)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q: duration must be positive", s)
+	}
+	return Limit{Name: name, Burst: burst, Per: d}, nil
+}
+
+// Result is the state of a bucket after a request.
+type Result struct {
+	Allowed bool
+	// Tokens left in the bucket; fractional while refilling.
+	Tokens float64
+}
+
+// Store keeps buckets. MemoryStore suits a single instance; PostgresStore shares
+// buckets between instances.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Middleware limits requests against limit, keyed by the authenticated sub when there
+// is one and the client IP otherwise, so it must run after auth.AuthMiddleware or
+// auth.OptionalAuthMiddleware. If the store fails the request is let through.
+func Middleware(store Store, limit Limit, ips *ClientIP) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			res, err := store.Take(r.Context(), bucketKey(r.Context(), limit, ips.Of(r)), limit)
+			if err != nil {
+				slog.Error("rate limit store failed; allowing request", "budget", limit.Name, "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			setHeaders(w.Header(), limit, res)
+			if !res.Allowed {
+				problem.Write(w, r, problem.RateLimited, "rate limit exceeded")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Allow takes a token from the bucket of r's caller for limit, as Middleware does,
+// but leaves the response to the caller. It charges work a single request does
+// many times over, such as the upstream calls of one GraphQL query. If the store
+// fails the work is allowed.
+func Allow(r *http.Request, store Store, limit Limit, ips *ClientIP) bool {
+	res, err := store.Take(r.Context(), bucketKey(r.Context(), limit, ips.Of(r)), limit)
+	if err != nil {
+		slog.Error("rate limit store failed; allowing request", "budget", limit.Name, "error", err)
+		return true
+	}
+	return res.Allowed
+}
+
+// Interceptor is Middleware for Connect services, covering unary RPCs. It must come
+// after auth.AuthInterceptor. The headers Middleware sets go in the response
+// headers, or in the error metadata when the RPC fails.
+func Interceptor(store Store, limit Limit, ips *ClientIP) connect.Interceptor {
+	return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			res, err := store.Take(ctx, bucketKey(ctx, limit, ips.of(req.Peer().Addr, req.Header())), limit)
+			if err != nil {
+				slog.Error("rate limit store failed; allowing request", "budget", limit.Name, "error", err)
+				return next(ctx, req)
+			}
+
+			if !res.Allowed {
+				cerr := problem.ConnectError(ctx, problem.RateLimited, "rate limit exceeded")
+				setHeaders(cerr.Meta(), limit, res)
+				return nil, cerr
+			}
+			resp, err := next(ctx, req)
+			var cerr *connect.Error
+			switch {
+			case err == nil:
+				setHeaders(resp.Header(), limit, res)
+			case errors.As(err, &cerr):
+				setHeaders(cerr.Meta(), limit, res)
+			}
+			return resp, err
+		}
+	})
+}
+
+// bucketKey is the caller's bucket for limit: their sub when authenticated, their
+// address otherwise.
+func bucketKey(ctx context.Context, limit Limit, ip string) string {
+	if sub, ok := appauth.SubFromContext(ctx); ok {
+		return limit.Name + ":sub:" + sub
+	}
+	return limit.Name + ":ip:" + ip
+}
+
+// setHeaders describes the bucket's state after a request in h, with Retry-After
+// when the request was refused.
+func setHeaders(h http.Header, limit Limit, res Result) {
+	rate := limit.rate()
+	h.Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+	h.Set("RateLimit-Remaining", strconv.Itoa(int(math.Max(0, math.Floor(res.Tokens)))))
+	h.Set("RateLimit-Reset", strconv.Itoa(seconds((float64(limit.Burst)-res.Tokens)/rate)))
+	h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Burst, seconds(limit.Per.Seconds())))
+	if !res.Allowed {
+		h.Set("Retry-After", strconv.Itoa(max(1, seconds((1-res.Tokens)/rate))))
+	}
+}
+
+// seconds rounds a non-negative number of seconds up to a whole second.
+func seconds(s float64) int {
+	return int(math.Ceil(math.Max(0, s)))
+}
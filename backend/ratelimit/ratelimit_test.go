@@ -0,0 +1,228 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// clock is a settable time source for MemoryStore.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func newLimited(store Store, limit Limit, ips *ClientIP) http.Handler {
+	return Middleware(store, limit, ips)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
+
+func get(h http.Handler, remoteAddr, sub string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/search", nil)
+	r.RemoteAddr = remoteAddr
+	if sub != "" {
+		r = r.WithContext(appauth.SubToContext(r.Context(), sub))
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestParseLimit(t *testing.T) {
+	l, err := ParseLimit("proxy", "60/1m")
+	if err != nil || l.Burst != 60 || l.Per != time.Minute || l.Name != "proxy" {
+		t.Errorf("got %+v, %v", l, err)
+	}
+	for _, s := range []string{"60", "0/1m", "x/1m", "60/soon", "60/-1s"} {
+		if _, err := ParseLimit("proxy", s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
+
+func TestMiddleware_LimitsAndRefills(t *testing.T) {
+	c := &clock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	h := newLimited(&MemoryStore{Now: c.now}, Limit{Name: "proxy", Burst: 2, Per: 10 * time.Second}, nil)
+
+	for i := range 2 {
+		if w := get(h, "192.0.2.1:1234", ""); w.Code != http.StatusNoContent {
+			t.Fatalf("request %d: status: got %d, want %d", i, w.Code, http.StatusNoContent)
+		}
+	}
+	w := get(h, "192.0.2.1:1234", "")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status: got %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if got := w.Header().Get("Retry-After"); got != "5" {
+		t.Errorf("Retry-After: got %q, want 5", got)
+	}
+	if got := w.Header().Get("RateLimit-Remaining"); got != "0" {
+		t.Errorf("RateLimit-Remaining: got %q, want 0", got)
+	}
+
+	c.t = c.t.Add(5 * time.Second)
+	w = get(h, "192.0.2.1:1234", "")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("after refill: status: got %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if got := w.Header().Get("RateLimit-Limit"); got != "2" {
+		t.Errorf("RateLimit-Limit: got %q, want 2", got)
+	}
+	if got := w.Header().Get("RateLimit-Reset"); got != "10" {
+		t.Errorf("RateLimit-Reset: got %q, want 10", got)
+	}
+	if got := w.Header().Get("RateLimit-Policy"); got != "2;w=10" {
+		t.Errorf("RateLimit-Policy: got %q, want 2;w=10", got)
+	}
+}
+
+func TestMiddleware_KeysBySubThenIP(t *testing.T) {
+	h := newLimited(&MemoryStore{}, Limit{Name: "api", Burst: 1, Per: time.Hour}, nil)
+
+	get(h, "192.0.2.1:1234", "alice")
+	if w := get(h, "192.0.2.1:1234", "bob"); w.Code != http.StatusNoContent {
+		t.Errorf("another user on the same IP: status: got %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if w := get(h, "192.0.2.1:1234", ""); w.Code != http.StatusNoContent {
+		t.Errorf("anonymous: status: got %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if w := get(h, "198.51.100.9:1234", "alice"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("same user from another IP: status: got %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+}
+
+func TestMiddleware_BudgetsAreSeparate(t *testing.T) {
+	store := &MemoryStore{}
+	proxy := newLimited(store, Limit{Name: "proxy", Burst: 1, Per: time.Hour}, nil)
+	api := newLimited(store, Limit{Name: "api", Burst: 1, Per: time.Hour}, nil)
+
+	get(proxy, "192.0.2.1:1234", "")
+	if w := get(api, "192.0.2.1:1234", ""); w.Code != http.StatusNoContent {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Limit) (Result, error) {
+	return Result{}, errors.New("db down")
+}
+
+func TestMiddleware_StoreFailureAllows(t *testing.T) {
+	h := newLimited(failingStore{}, Limit{Name: "api", Burst: 1, Per: time.Hour}, nil)
+
+	if w := get(h, "192.0.2.1:1234", ""); w.Code != http.StatusNoContent {
+		t.Errorf("status: got %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
+
+func TestAllow_SharesTheMiddlewareBucket(t *testing.T) {
+	store := &MemoryStore{}
+	limit := Limit{Name: "proxy", Burst: 2, Per: time.Hour}
+	r := requestAs("alice")
+
+	if !Allow(r, store, limit, nil) {
+		t.Fatal("first call: got refused")
+	}
+	get(newLimited(store, limit, nil), "192.0.2.1:1234", "alice")
+	if Allow(r, store, limit, nil) {
+		t.Error("expected the bucket the middleware drew on to be empty")
+	}
+	if !Allow(r, failingStore{}, limit, nil) {
+		t.Error("store failure: got refused")
+	}
+}
+
+// requestAs returns a request authenticated as sub.
+func requestAs(sub string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	return r.WithContext(appauth.SubToContext(r.Context(), sub))
+}
+
+// callAs returns a function making an RPC through interceptor as sub.
+func callAs(interceptor connect.Interceptor) func(sub string) (connect.AnyResponse, error) {
+	next := interceptor.WrapUnary(func(context.Context, connect.AnyRequest) (connect.AnyResponse, error) {
+		return connect.NewResponse(&emptypb.Empty{}), nil
+	})
+	return func(sub string) (connect.AnyResponse, error) {
+		return next(appauth.SubToContext(context.Background(), sub), connect.NewRequest(&emptypb.Empty{}))
+	}
+}
+
+func TestInterceptor_LimitsBySub(t *testing.T) {
+	call := callAs(Interceptor(&MemoryStore{}, Limit{Name: "api", Burst: 1, Per: time.Hour}, nil))
+
+	resp, err := call("alice")
+	if err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	if got := resp.Header().Get("RateLimit-Remaining"); got != "0" {
+		t.Errorf("RateLimit-Remaining: got %q, want %q", got, "0")
+	}
+	if _, err := call("bob"); err != nil {
+		t.Errorf("another user: %v", err)
+	}
+
+	_, err = call("alice")
+	var cerr *connect.Error
+	if !errors.As(err, &cerr) || cerr.Code() != connect.CodeResourceExhausted {
+		t.Fatalf("got %v, want resource_exhausted", err)
+	}
+	if cerr.Meta().Get("Retry-After") == "" {
+		t.Error("expected Retry-After in the error metadata")
+	}
+}
+
+func TestMemoryStore_SweepsFullBuckets(t *testing.T) {
+	c := &clock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := &MemoryStore{Now: c.now}
+	limit := Limit{Name: "api", Burst: 1, Per: time.Second}
+
+	store.Take(context.Background(), "a", limit)
+	c.t = c.t.Add(sweepInterval)
+	store.Take(context.Background(), "b", limit)
+
+	if _, ok := store.buckets["a"]; ok {
+		t.Error("expected the refilled bucket to be swept")
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	ips, err := NewClientIP([]string{"10.0.0.0/8", "192.0.2.10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name, remote, xff, want string
+	}{
+		{"direct", "198.51.100.1:4000", "", "198.51.100.1"},
+		{"untrusted peer cannot spoof", "198.51.100.1:4000", "203.0.113.5", "198.51.100.1"},
+		{"trusted proxy", "10.1.2.3:4000", "203.0.113.5", "203.0.113.5"},
+		{"spoofed prefix ignored", "10.1.2.3:4000", "1.1.1.1, 203.0.113.5", "203.0.113.5"},
+		{"proxy chain", "192.0.2.10:4000", "203.0.113.5, 10.9.9.9", "203.0.113.5"},
+		{"all trusted", "10.1.2.3:4000", "10.4.4.4", "10.4.4.4"},
+		{"garbage header", "10.1.2.3:4000", "not-an-ip", "10.1.2.3"},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tc.remote
+		if tc.xff != "" {
+			r.Header.Set("X-Forwarded-For", tc.xff)
+		}
+		if got := ips.Of(r); got != tc.want {
+			t.Errorf("%s: got %s, want %s", tc.name, got, tc.want)
+		}
+	}
+
+	if _, err := NewClientIP([]string{"not-a-cidr/8"}); err == nil {
+		t.Error("expected an error for an invalid proxy")
+	}
+}
@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dcrespo1/book-list-app/pkg/database"
+)
+
+// sweepInterval is how often MemoryStore drops buckets that have refilled.
+const sweepInterval = time.Minute
+
+// MemoryStore keeps buckets in process memory. Each instance limits on its own.
+type MemoryStore struct {
+	// Now returns the current time. Defaults to time.Now; set in tests.
+	Now func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+// refill brings b up to date at now.
+func (b *bucket) refill(now time.Time) {
+	b.tokens = min(float64(b.limit.Burst), b.tokens+now.Sub(b.updated).Seconds()*b.limit.rate())
+	b.updated = now
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	now := time.Now()
+	if s.Now != nil {
+		now = s.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.buckets == nil {
+		s.buckets = map[string]*bucket{}
+	}
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now, limit: limit}
+		s.buckets[key] = b
+	}
+	b.limit = limit
+	b.refill(now)
+	if b.tokens < 1 {
+		return Result{Allowed: false, Tokens: b.tokens}, nil
+	}
+	b.tokens--
+	return Result{Allowed: true, Tokens: b.tokens}, nil
+}
+
+// sweep drops full buckets; a new bucket starts full, so they carry no state.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if b.refill(now); b.tokens >= float64(b.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// Queries is the persistence interface used by PostgresStore. *database.Queries satisfies it.
+type Queries interface {
+	TakeRateLimitToken(ctx context.Context, arg database.TakeRateLimitTokenParams) (database.TakeRateLimitTokenRow, error)
+}
+
+// PostgresStore keeps buckets in the rate_limit_buckets table so that every instance
+// shares them. Bucket times come from the database clock.
+type PostgresStore struct {
+	Queries Queries
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	row, err := s.Queries.TakeRateLimitToken(ctx, database.TakeRateLimitTokenParams{
+		Key:   key,
+		Burst: float64(limit.Burst),
+		Rate:  limit.rate(),
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Allowed: row.Allowed, Tokens: row.Tokens}, nil
+}
@@ -0,0 +1,168 @@
+// Package stream backs the live readlist feed. Publisher appends events to the
+// readlist_events table, whose insert trigger issues a Postgres NOTIFY; Broker
+// LISTENs for those notifications on every instance and wakes the SSE
+// connections of the affected user, which then read the new rows themselves.
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dcrespo1/book-list-app/events"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/lib/pq"
+)
+
+// Channel is the NOTIFY channel raised by the readlist_events insert trigger.
+const Channel = "readlist_events"
+
+// Appender is the persistence interface used by Publisher. *database.Queries satisfies it.
+type Appender interface {
+	InsertReadlistEvent(ctx context.Context, arg database.InsertReadlistEventParams) (int64, error)
+}
+
+// Publisher implements events.Publisher by appending to the event log. Only the
+// added/updated/deleted events are streamed; status changes arrive as updates.
+type Publisher struct {
+	Queries Appender
+}
+
+func (p *Publisher) Publish(ctx context.Context, e events.Event) error {
+	switch e.Type {
+	case events.BookAdded, events.BookUpdated, events.BookDeleted:
+	default:
+		return nil
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode stream event: %w", err)
+	}
+	_, err = p.Queries.InsertReadlistEvent(ctx, database.InsertReadlistEventParams{
+		UserID:  e.UserID,
+		Type:    e.Type,
+		Payload: payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to append stream event: %w", err)
+	}
+	return nil
+}
+
+// Listener is the subset of *pq.Listener that Broker needs.
+type Listener interface {
+	NotificationChannel() <-chan *pq.Notification
+	Ping() error
+}
+
+// Broker fans NOTIFY wake-ups out to in-process subscribers, keyed by user.
+// Wake-ups carry no data: a woken subscriber re-reads the log from its last id,
+// so coalescing several notifications into one is harmless.
+type Broker struct {
+	mu   sync.Mutex
+	subs map[string]map[chan struct{}]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{subs: make(map[string]map[chan struct{}]struct{})}
+}
+
+// Subscribe registers interest in a user's events. The returned function must be
+// called to release the subscription.
+func (b *Broker) Subscribe(userID string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	b.mu.Lock()
+	if b.subs[userID] == nil {
+		b.subs[userID] = make(map[chan struct{}]struct{})
+	}
+	b.subs[userID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.subs[userID], ch)
+		if len(b.subs[userID]) == 0 {
+			delete(b.subs, userID)
+		}
+		b.mu.Unlock()
+	}
+}
+
+// Notify wakes every subscriber of userID.
+func (b *Broker) Notify(userID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[userID] {
+		wake(ch)
+	}
+}
+
+// NotifyAll wakes every subscriber. Used after the listener reconnects, when
+// notifications may have been lost.
+func (b *Broker) NotifyAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, chans := range b.subs {
+		for ch := range chans {
+			wake(ch)
+		}
+	}
+}
+
+func wake(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default: // a wake-up is already pending
+	}
+}
+
+// Run dispatches notifications from l until ctx is cancelled, pinging the
+// connection periodically so a silently dropped connection is noticed.
+func (b *Broker) Run(ctx context.Context, l Listener) {
+	ping := time.NewTicker(90 * time.Second)
+	defer ping.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-l.NotificationChannel():
+			if n == nil {
+				// pq sends nil after re-establishing a dropped connection.
+				b.NotifyAll()
+				continue
+			}
+			if _, userID, ok := strings.Cut(n.Extra, ":"); ok {
+				b.Notify(userID)
+			}
+		case <-ping.C:
+			if err := l.Ping(); err != nil {
+				slog.Warn("readlist event listener ping failed", "error", err)
+			}
+		}
+	}
+}
+
+// Pruner is the persistence interface used by Prune. *database.Queries satisfies it.
+type Pruner interface {
+	DeleteReadlistEventsBefore(ctx context.Context, createdAt time.Time) (int64, error)
+}
+
+// Prune returns a job that deletes events older than retention. Clients resuming
+// from an id older than the retention window miss those events.
+func Prune(p Pruner, retention time.Duration) func(context.Context) error {
+	return func(ctx context.Context) error {
+		n, err := p.DeleteReadlistEventsBefore(ctx, time.Now().Add(-retention))
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			slog.Info("pruned readlist events", "count", n)
+		}
+		return nil
+	}
+}
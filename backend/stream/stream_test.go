@@ -0,0 +1,107 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dcrespo1/book-list-app/events"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/lib/pq"
+)
+
+type fakeListener struct {
+	ch chan *pq.Notification
+}
+
+func (f *fakeListener) NotificationChannel() <-chan *pq.Notification { return f.ch }
+func (f *fakeListener) Ping() error                                  { return nil }
+
+func woken(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	case <-time.After(time.Second):
+		return false
+	}
+}
+
+func TestBroker_RoutesNotificationToUser(t *testing.T) {
+	b := NewBroker()
+	l := &fakeListener{ch: make(chan *pq.Notification)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go b.Run(ctx, l)
+
+	alice, unsubAlice := b.Subscribe("alice")
+	defer unsubAlice()
+	bob, unsubBob := b.Subscribe("bob")
+	defer unsubBob()
+
+	l.ch <- &pq.Notification{Channel: Channel, Extra: "42:alice"}
+
+	if !woken(alice) {
+		t.Error("expected alice to be woken")
+	}
+	select {
+	case <-bob:
+		t.Error("bob should not be woken by alice's event")
+	default:
+	}
+}
+
+func TestBroker_ReconnectWakesEveryone(t *testing.T) {
+	b := NewBroker()
+	l := &fakeListener{ch: make(chan *pq.Notification)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go b.Run(ctx, l)
+
+	alice, _ := b.Subscribe("alice")
+	bob, _ := b.Subscribe("bob")
+	l.ch <- nil
+
+	if !woken(alice) || !woken(bob) {
+		t.Error("expected all subscribers to be woken after reconnect")
+	}
+}
+
+func TestBroker_UnsubscribeStopsWakeUps(t *testing.T) {
+	b := NewBroker()
+	ch, unsubscribe := b.Subscribe("alice")
+	unsubscribe()
+	b.Notify("alice")
+
+	select {
+	case <-ch:
+		t.Error("unsubscribed channel was woken")
+	default:
+	}
+	if len(b.subs) != 0 {
+		t.Errorf("expected no remaining subscriptions, got %d", len(b.subs))
+	}
+}
+
+type fakeAppender struct {
+	inserted []database.InsertReadlistEventParams
+}
+
+func (f *fakeAppender) InsertReadlistEvent(_ context.Context, arg database.InsertReadlistEventParams) (int64, error) {
+	f.inserted = append(f.inserted, arg)
+	return int64(len(f.inserted)), nil
+}
+
+func TestPublisher_SkipsStatusChanged(t *testing.T) {
+	q := &fakeAppender{}
+	p := &Publisher{Queries: q}
+
+	for _, typ := range []string{events.BookAdded, events.BookStatusChanged, events.BookDeleted} {
+		if err := p.Publish(context.Background(), events.Event{Type: typ, UserID: "alice"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(q.inserted) != 2 || q.inserted[0].Type != events.BookAdded || q.inserted[1].Type != events.BookDeleted {
+		t.Errorf("unexpected inserts: %+v", q.inserted)
+	}
+}
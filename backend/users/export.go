@@ -0,0 +1,225 @@
+package users
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dcrespo1/book-list-app/pkg/database"
+)
+
+// ExportStore reads everything stored for a user. *database.Queries satisfies it.
+type ExportStore interface {
+	GetUser(ctx context.Context, userID string) (database.User, error)
+	ExportBooks(ctx context.Context, userID string) ([]database.Book, error)
+	ExportComments(ctx context.Context, userID string) ([]database.Comment, error)
+	ListWebhookSubscriptions(ctx context.Context, userID string) ([]database.WebhookSubscription, error)
+	ExportWebhookDeliveries(ctx context.Context, userID string) ([]database.WebhookDelivery, error)
+	ExportReadlistEvents(ctx context.Context, userID string) ([]database.ReadlistEvent, error)
+	ExportIdempotencyKeys(ctx context.Context, userID string) ([]database.IdempotencyKey, error)
+	ExportPersonalAccessTokens(ctx context.Context, userID string) ([]database.PersonalAccessToken, error)
+	ExportHouseholdMemberships(ctx context.Context, userID string) ([]database.HouseholdMember, error)
+	ExportHouseholdCopies(ctx context.Context, addedBy string) ([]database.HouseholdCopy, error)
+	ExportHouseholdInvites(ctx context.Context, createdBy string) ([]database.HouseholdInvite, error)
+	ExportLoans(ctx context.Context, userID string) ([]database.Loan, error)
+	ExportOwnedFormats(ctx context.Context, userID string) ([]database.OwnedFormat, error)
+}
+
+// dataset is one user-scoped table in an export. omit names columns that hold
+// credentials rather than the user's data.
+type dataset struct {
+	name  string
+	omit  []string
+	fetch func(ctx context.Context, store ExportStore, userID string) (any, error)
+}
+
+// datasets lists every user-scoped table except rate_limit_buckets (see purges).
+// A new table holding user data must be added here and to purges.
+var datasets = []dataset{
+	{name: "profile", omit: []string{"deletion_token_hash"}, fetch: func(ctx context.Context, s ExportStore, id string) (any, error) {
+		u, err := s.GetUser(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return []database.User{}, nil
+		}
+		return []database.User{u}, err
+	}},
+	{name: "books", fetch: func(ctx context.Context, s ExportStore, id string) (any, error) {
+		return s.ExportBooks(ctx, id)
+	}},
+	{name: "comments", fetch: func(ctx context.Context, s ExportStore, id string) (any, error) {
+		return s.ExportComments(ctx, id)
+	}},
+	{name: "webhook_subscriptions", omit: []string{"secret"}, fetch: func(ctx context.Context, s ExportStore, id string) (any, error) {
+		return s.ListWebhookSubscriptions(ctx, id)
+	}},
+	{name: "webhook_deliveries", fetch: func(ctx context.Context, s ExportStore, id string) (any, error) {
+		return s.ExportWebhookDeliveries(ctx, id)
+	}},
+	{name: "readlist_events", fetch: func(ctx context.Context, s ExportStore, id string) (any, error) {
+		return s.ExportReadlistEvents(ctx, id)
+	}},
+	// Stored responses are left out: they repeat data exported elsewhere and may
+	// hold credentials issued before secret-bearing routes stopped being stored.
+	{name: "idempotency_keys", omit: []string{"response_headers", "response_body"}, fetch: func(ctx context.Context, s ExportStore, id string) (any, error) {
+		return s.ExportIdempotencyKeys(ctx, id)
+	}},
+	{name: "personal_access_tokens", omit: []string{"token_hash"}, fetch: func(ctx context.Context, s ExportStore, id string) (any, error) {
+		return s.ExportPersonalAccessTokens(ctx, id)
+	}},
+	{name: "household_memberships", fetch: func(ctx context.Context, s ExportStore, id string) (any, error) {
+		return s.ExportHouseholdMemberships(ctx, id)
+	}},
+	{name: "household_copies", fetch: func(ctx context.Context, s ExportStore, id string) (any, error) {
+		return s.ExportHouseholdCopies(ctx, id)
+	}},
+	{name: "household_invites", omit: []string{"token_hash"}, fetch: func(ctx context.Context, s ExportStore, id string) (any, error) {
+		return s.ExportHouseholdInvites(ctx, id)
+	}},
+	{name: "loans", fetch: func(ctx context.Context, s ExportStore, id string) (any, error) {
+		return s.ExportLoans(ctx, id)
+	}},
+	{name: "owned_formats", fetch: func(ctx context.Context, s ExportStore, id string) (any, error) {
+		return s.ExportOwnedFormats(ctx, id)
+	}},
+}
+
+// Export writes a ZIP archive of everything stored for userID: for each table a
+// <table>.json file holding an array of rows and a <table>.csv file with a header.
+func Export(ctx context.Context, store ExportStore, userID string, w io.Writer) error {
+	zw := zip.NewWriter(w)
+	for _, ds := range datasets {
+		rows, err := ds.fetch(ctx, store, userID)
+		if err != nil {
+			return fmt.Errorf("export %s: %w", ds.name, err)
+		}
+		columns, records := flatten(rows, ds.omit)
+
+		f, err := zw.Create(ds.name + ".json")
+		if err != nil {
+			return err
+		}
+		if err := writeJSON(f, columns, records); err != nil {
+			return err
+		}
+
+		f, err = zw.Create(ds.name + ".csv")
+		if err != nil {
+			return err
+		}
+		if err := writeCSV(f, columns, records); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// flatten turns a slice of generated row structs into snake_case column names and
+// plain values, unwrapping sql.Null* types to their value or nil.
+func flatten(rows any, omit []string) ([]string, [][]any) {
+	v := reflect.ValueOf(rows)
+	t := v.Type().Elem()
+
+	var columns []string
+	var index []int
+	for i := range t.NumField() {
+		name := snakeCase(t.Field(i).Name)
+		if slices.Contains(omit, name) {
+			continue
+		}
+		columns = append(columns, name)
+		index = append(index, i)
+	}
+
+	records := make([][]any, v.Len())
+	for r := range v.Len() {
+		row := v.Index(r)
+		record := make([]any, len(index))
+		for c, i := range index {
+			field := row.Field(i).Interface()
+			if valuer, ok := field.(driver.Valuer); ok {
+				field, _ = valuer.Value()
+			}
+			record[c] = field
+		}
+		records[r] = record
+	}
+	return columns, records
+}
+
+func writeJSON(w io.Writer, columns []string, records [][]any) error {
+	out := make([]map[string]any, len(records))
+	for r, record := range records {
+		out[r] = make(map[string]any, len(columns))
+		for c, name := range columns {
+			out[r][name] = record[c]
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func writeCSV(w io.Writer, columns []string, records [][]any) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	line := make([]string, len(columns))
+	for _, record := range records {
+		for c, v := range record {
+			line[c] = csvValue(v)
+		}
+		if err := cw.Write(line); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case json.RawMessage:
+		return string(v)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	case []string:
+		return strings.Join(v, ";")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// snakeCase converts a generated Go field name (UserID, CoverArtUrl) to its column
+// name (user_id, cover_art_url).
+func snakeCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
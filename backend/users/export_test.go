@@ -0,0 +1,61 @@
+package users
+
+import (
+	"database/sql"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dcrespo1/book-list-app/pkg/database"
+)
+
+func TestSnakeCase(t *testing.T) {
+	for in, want := range map[string]string{
+		"UserID":      "user_id",
+		"CoverArtUrl": "cover_art_url",
+		"ID":          "id",
+		"OlKey":       "ol_key",
+		"Authors":     "authors",
+	} {
+		if got := snakeCase(in); got != want {
+			t.Errorf("snakeCase(%q): got %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFlatten_OmitsAndUnwraps(t *testing.T) {
+	now := time.Now()
+	rows := []database.PersonalAccessToken{{
+		ID:        1,
+		TokenHash: "secret",
+		ExpiresAt: sql.NullTime{Time: now, Valid: true},
+	}}
+
+	columns, records := flatten(rows, []string{"token_hash"})
+
+	for i, c := range columns {
+		if c == "token_hash" {
+			t.Fatal("omitted column exported")
+		}
+		if c == "expires_at" && records[0][i] != now {
+			t.Errorf("expires_at: got %v, want %v", records[0][i], now)
+		}
+		if c == "last_used_at" && records[0][i] != nil {
+			t.Errorf("last_used_at: got %v, want nil", records[0][i])
+		}
+	}
+}
+
+func TestDatasets_OmitStoredResponses(t *testing.T) {
+	i := slices.IndexFunc(datasets, func(ds dataset) bool { return ds.name == "idempotency_keys" })
+	if i < 0 {
+		t.Fatal("idempotency_keys dataset missing")
+	}
+	rows := []database.IdempotencyKey{{Key: "k1", ResponseBody: []byte(`{"token":"blpat_secret"}`)}}
+
+	columns, _ := flatten(rows, datasets[i].omit)
+
+	if slices.Contains(columns, "response_body") || slices.Contains(columns, "response_headers") {
+		t.Errorf("stored responses exported: columns %v", columns)
+	}
+}
@@ -0,0 +1,78 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dcrespo1/book-list-app/pkg/database"
+)
+
+// purges remove a user's rows from every user-scoped table. A new table holding
+// user data must be added here and to datasets. rate_limit_buckets is purged but
+// not exported: it holds a token count, not anything the user gave us.
+var purges = []struct {
+	name  string
+	purge func(q *database.Queries, ctx context.Context, userID string) error
+}{
+	{"loans", (*database.Queries).PurgeUserLoans},
+	{"borrowed_loans", unlinkLoanBorrower},
+	{"owned_formats", (*database.Queries).PurgeUserOwnedFormats},
+	{"books", (*database.Queries).PurgeUserBooks},
+	{"comments", (*database.Queries).AnonymizeUserComments},
+	{"webhook_subscriptions", (*database.Queries).PurgeUserWebhookSubscriptions},
+	{"readlist_events", (*database.Queries).PurgeUserReadlistEvents},
+	{"idempotency_keys", (*database.Queries).PurgeUserIdempotencyKeys},
+	{"rate_limit_buckets", (*database.Queries).PurgeUserRateLimitBuckets},
+	{"personal_access_tokens", (*database.Queries).PurgeUserPersonalAccessTokens},
+	{"household_invites", (*database.Queries).PurgeUserHouseholdInvites},
+	{"household_copies", (*database.Queries).AnonymizeUserHouseholdCopies},
+	{"household_members", leaveHouseholds},
+	{"users", (*database.Queries).PurgeUser},
+}
+
+func unlinkLoanBorrower(q *database.Queries, ctx context.Context, userID string) error {
+	return q.UnlinkLoanBorrower(ctx, sql.NullString{String: userID, Valid: true})
+}
+
+// leaveHouseholds removes userID from every household. A household left without an
+// owner passes to its longest-standing member; one left empty is deleted.
+func leaveHouseholds(q *database.Queries, ctx context.Context, userID string) error {
+	households, err := q.PurgeUserHouseholdMemberships(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, id := range households {
+		if err := q.PromoteHouseholdOwner(ctx, id); err != nil {
+			return err
+		}
+		if err := q.DeleteEmptyHousehold(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DBStore adds account purging to the generated queries. It satisfies Store,
+// ExportStore and jobs.AccountPurger.
+type DBStore struct {
+	*database.Queries
+	DB *sql.DB
+}
+
+// PurgeAccount deletes everything stored for userID in one transaction.
+func (s *DBStore) PurgeAccount(ctx context.Context, userID string) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := s.Queries.WithTx(tx)
+	for _, p := range purges {
+		if err := p.purge(q, ctx, userID); err != nil {
+			return fmt.Errorf("purge %s: %w", p.name, err)
+		}
+	}
+	return tx.Commit()
+}
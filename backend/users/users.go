@@ -0,0 +1,115 @@
+// Package users keeps a row per authenticated user, created from the token claims on
+// their first request, and makes it (with their preferences) available to handlers.
+package users
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"connectrpc.com/connect"
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+	"github.com/dcrespo1/book-list-app/problem"
+)
+
+type contextKey struct{}
+
+// defaults mirrors the column defaults of the users table, for requests that have no
+// user row in their context.
+var defaults = database.User{
+	DefaultSort:   "added_desc",
+	Privacy:       "private",
+	DefaultStatus: "want_to_read",
+	Timezone:      "UTC",
+}
+
+// Store is the persistence interface for users. *database.Queries satisfies it.
+type Store interface {
+	GetUser(ctx context.Context, userID string) (database.User, error)
+	UpsertUser(ctx context.Context, arg database.UpsertUserParams) (database.User, error)
+}
+
+// Middleware loads the caller's user row into the request context, creating it on
+// their first request and refreshing the profile when the token claims change. It
+// must run after auth.AuthMiddleware; unauthenticated requests pass through.
+func Middleware(store Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := appauth.PrincipalFromContext(r.Context())
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			user, err := load(r.Context(), store, p)
+			if err != nil {
+				problem.Write(w, r, problem.Internal, "failed to load user")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ToContext(r.Context(), user)))
+		})
+	}
+}
+
+// Interceptor is Middleware for Connect services, covering unary RPCs. It must come
+// after auth.AuthInterceptor.
+func Interceptor(store Store) connect.Interceptor {
+	return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			p, ok := appauth.PrincipalFromContext(ctx)
+			if !ok {
+				return next(ctx, req)
+			}
+			user, err := load(ctx, store, p)
+			if err != nil {
+				return nil, problem.ConnectError(ctx, problem.Internal, "failed to load user")
+			}
+			return next(ToContext(ctx, user), req)
+		}
+	})
+}
+
+// load returns p's user row, creating it or refreshing its profile as needed.
+func load(ctx context.Context, store Store, p *appauth.Principal) (database.User, error) {
+	user, err := store.GetUser(ctx, p.Subject)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && stale(user, p)) {
+		user, err = store.UpsertUser(ctx, database.UpsertUserParams{
+			UserID:   p.Subject,
+			Username: nullString(p.Username),
+			Email:    nullString(p.Email),
+			Name:     nullString(p.Name),
+		})
+	}
+	return user, err
+}
+
+// stale reports whether the token carries profile claims that differ from the stored
+// ones. Personal access tokens carry none, so they never trigger a refresh.
+func stale(u database.User, p *appauth.Principal) bool {
+	differs := func(stored sql.NullString, claim string) bool {
+		return claim != "" && stored.String != claim
+	}
+	return differs(u.Username, p.Username) || differs(u.Email, p.Email) || differs(u.Name, p.Name)
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// FromContext returns the caller's user row as loaded by Middleware. Without one, ok
+// is false and the returned user carries the default preferences.
+func FromContext(ctx context.Context) (database.User, bool) {
+	u, ok := ctx.Value(contextKey{}).(database.User)
+	if !ok {
+		return defaults, false
+	}
+	return u, true
+}
+
+// ToContext stores a user row in the context. Used by Middleware and in tests.
+func ToContext(ctx context.Context, u database.User) context.Context {
+	return context.WithValue(ctx, contextKey{}, u)
+}
@@ -0,0 +1,99 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	appauth "github.com/dcrespo1/book-list-app/auth"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+)
+
+// fakeStore is an in-memory Store that counts upserts.
+type fakeStore struct {
+	users   map[string]database.User
+	upserts int
+}
+
+func (f *fakeStore) GetUser(_ context.Context, userID string) (database.User, error) {
+	u, ok := f.users[userID]
+	if !ok {
+		return database.User{}, sql.ErrNoRows
+	}
+	return u, nil
+}
+
+func (f *fakeStore) UpsertUser(_ context.Context, arg database.UpsertUserParams) (database.User, error) {
+	f.upserts++
+	u, ok := f.users[arg.UserID]
+	if !ok {
+		u = defaults
+		u.UserID = arg.UserID
+	}
+	for _, c := range []struct {
+		dst *sql.NullString
+		src sql.NullString
+	}{
+		{&u.Username, arg.Username}, {&u.Email, arg.Email}, {&u.Name, arg.Name},
+	} {
+		if c.src.Valid {
+			*c.dst = c.src
+		}
+	}
+	f.users[arg.UserID] = u
+	return u, nil
+}
+
+func serve(store Store, p *appauth.Principal) (database.User, bool) {
+	var got database.User
+	var ok bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok = FromContext(r.Context())
+	})
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if p != nil {
+		r = r.WithContext(appauth.PrincipalToContext(r.Context(), p))
+	}
+	Middleware(store)(next).ServeHTTP(httptest.NewRecorder(), r)
+	return got, ok
+}
+
+func TestMiddleware_CreatesUserOnFirstRequest(t *testing.T) {
+	store := &fakeStore{users: map[string]database.User{}}
+	p := &appauth.Principal{Subject: "u1", Username: "alice", Email: "alice@example.com"}
+
+	u, ok := serve(store, p)
+	if !ok || u.UserID != "u1" || u.Username.String != "alice" || u.DefaultStatus != "want_to_read" {
+		t.Fatalf("unexpected user: %+v", u)
+	}
+	serve(store, p)
+	if store.upserts != 1 {
+		t.Errorf("upserts: got %d, want 1", store.upserts)
+	}
+}
+
+func TestMiddleware_RefreshesChangedClaims(t *testing.T) {
+	store := &fakeStore{users: map[string]database.User{
+		"u1": {UserID: "u1", Email: sql.NullString{String: "old@example.com", Valid: true}, Privacy: "public"},
+	}}
+
+	u, _ := serve(store, &appauth.Principal{Subject: "u1", Email: "new@example.com"})
+	if u.Email.String != "new@example.com" || u.Privacy != "public" {
+		t.Errorf("unexpected user: %+v", u)
+	}
+
+	// A personal access token carries no claims and must not blank them out.
+	u, _ = serve(store, &appauth.Principal{Subject: "u1", PersonalToken: true})
+	if u.Email.String != "new@example.com" || store.upserts != 1 {
+		t.Errorf("unexpected user after PAT request: %+v (upserts %d)", u, store.upserts)
+	}
+}
+
+func TestFromContext_Defaults(t *testing.T) {
+	u, ok := serve(&fakeStore{}, nil)
+	if ok || u.DefaultSort != "added_desc" || u.Timezone != "UTC" {
+		t.Errorf("expected default preferences, got %+v (ok %v)", u, ok)
+	}
+}
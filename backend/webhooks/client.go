@@ -0,0 +1,80 @@
+package webhooks
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// reserved lists prefixes that pass net/netip's classification but must not be
+// reachable from deliveries: shared and special-purpose IPv4 space, and IPv6
+// prefixes that embed an IPv4 address (NAT64, 6to4).
+var reserved = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("2002::/16"),
+}
+
+// PublicAddr reports whether ip is a publicly routable unicast address, and so a
+// valid webhook destination. Loopback, private, link-local (including cloud
+// metadata endpoints), multicast and reserved addresses are not.
+func PublicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, p := range reserved {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewClient returns the HTTP client deliveries use unless Dispatcher.Client is set.
+// Subscribers choose the URL, so it only connects to public addresses, checked on
+// the resolved address at dial time so a name cannot be pointed at an internal host
+// after the subscription was created. Redirects are not followed: a 3xx is the
+// receiver's answer, and a failed delivery.
+func NewClient() *http.Client {
+	return newClient(PublicAddr)
+}
+
+func newClient(allow func(netip.Addr) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			ap, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !allow(ap.Addr()) {
+				return fmt.Errorf("webhook destination %s is not a public address", ap.Addr())
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			// No proxy: the address check has to see the receiver, not a proxy.
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   true,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConnsPerHost: 2,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,238 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dcrespo1/book-list-app/pkg/database"
+)
+
+// Headers set on every delivery. Receivers verify SignatureHeader by computing
+// Sign(secret, TimestampHeader value, raw body) and comparing in constant time.
+const (
+	SignatureHeader = "X-Booklist-Signature"
+	TimestampHeader = "X-Booklist-Timestamp"
+	EventHeader     = "X-Booklist-Event"
+	DeliveryHeader  = "X-Booklist-Delivery"
+)
+
+// Delivery states stored in webhook_deliveries.status.
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Store is the persistence interface used by Dispatcher. *database.Queries satisfies it.
+type Store interface {
+	ClaimWebhookDeliveries(ctx context.Context, arg database.ClaimWebhookDeliveriesParams) ([]database.ClaimWebhookDeliveriesRow, error)
+	MarkWebhookDeliverySucceeded(ctx context.Context, arg database.MarkWebhookDeliverySucceededParams) (int64, error)
+	MarkWebhookDeliveryFailed(ctx context.Context, arg database.MarkWebhookDeliveryFailedParams) (int64, error)
+}
+
+// Dispatcher polls the delivery queue and POSTs signed payloads. Failed attempts
+// are retried with exponential backoff until MaxAttempts is reached. Zero-valued
+// fields fall back to the defaults below.
+type Dispatcher struct {
+	Store Store
+	// Client sends deliveries; nil means a client from NewClient.
+	Client      *http.Client
+	BatchSize   int32
+	MaxAttempts int32
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Lease is how long a claimed delivery is hidden from other workers. A batch is
+	// sent concurrently, so it must outlast one delivery, bounded by the client's
+	// timeout; a result recorded after the lease has run out is discarded.
+	Lease time.Duration
+
+	now func() time.Time
+}
+
+const (
+	defaultBatchSize   = 20
+	defaultMaxAttempts = 8
+	defaultBaseBackoff = 30 * time.Second
+	defaultMaxBackoff  = 6 * time.Hour
+	defaultLease       = 2 * time.Minute
+	// maxDrainBytes bounds how much of a response is read, and discarded, so the
+	// connection can be reused.
+	maxDrainBytes = 64 << 10
+)
+
+// defaultClient is shared so deliveries reuse connections.
+var defaultClient = NewClient()
+
+// Sign returns the value of SignatureHeader for a payload: "sha256=" followed by the
+// hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the subscription secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Deliver attempts one batch of due deliveries. main runs it with jobs.Every.
+func (d *Dispatcher) Deliver(ctx context.Context) error {
+	_, err := d.DeliverDue(ctx)
+	return err
+}
+
+// DeliverDue claims one batch of due deliveries and attempts each once,
+// concurrently, returning how many were attempted.
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	batch, err := d.Store.ClaimWebhookDeliveries(ctx, database.ClaimWebhookDeliveriesParams{
+		BatchSize:    d.batchSize(),
+		LeaseSeconds: int32(d.lease().Seconds()),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	errs := make([]error, len(batch))
+	var wg sync.WaitGroup
+	for i, del := range batch {
+		wg.Go(func() {
+			errs[i] = d.deliver(ctx, del)
+		})
+	}
+	wg.Wait()
+	return len(batch), errors.Join(errs...)
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, del database.ClaimWebhookDeliveriesRow) error {
+	code, sendErr := d.send(ctx, del)
+	if sendErr == nil {
+		n, err := d.Store.MarkWebhookDeliverySucceeded(ctx, database.MarkWebhookDeliverySucceededParams{
+			LastStatusCode: sql.NullInt32{Int32: int32(code), Valid: true},
+			ID:             del.ID,
+			LeasedUntil:    del.LeasedUntil,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record webhook delivery %d: %w", del.ID, err)
+		}
+		if n == 0 {
+			slog.Warn("webhook delivery lease expired before its result was recorded", "delivery_id", del.ID)
+		}
+		return nil
+	}
+
+	attempts := del.Attempts + 1
+	status := StatusPending
+	if attempts >= d.maxAttempts() {
+		status = StatusFailed
+	}
+	params := database.MarkWebhookDeliveryFailedParams{
+		Status:        status,
+		NextAttemptAt: d.clock().Add(d.backoff(attempts)),
+		LastError:     sql.NullString{String: sendErr.Error(), Valid: true},
+		ID:            del.ID,
+		LeasedUntil:   del.LeasedUntil,
+	}
+	if code != 0 {
+		params.LastStatusCode = sql.NullInt32{Int32: int32(code), Valid: true}
+	}
+	n, err := d.Store.MarkWebhookDeliveryFailed(ctx, params)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery %d: %w", del.ID, err)
+	}
+	if n == 0 {
+		slog.Warn("webhook delivery lease expired before its result was recorded", "delivery_id", del.ID)
+		return nil
+	}
+	slog.Warn("webhook delivery failed", "delivery_id", del.ID, "attempt", attempts, "status", status, "error", sendErr)
+	return nil
+}
+
+// send POSTs the payload and returns the receiver's status code (0 if none was
+// received). Any non-2xx response is an error. The response body is never
+// recorded: the subscriber reads last_error, and the body is the receiver's.
+func (d *Dispatcher) send(ctx context.Context, del database.ClaimWebhookDeliveriesRow) (int, error) {
+	ts := strconv.FormatInt(d.clock().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, del.Url, bytes.NewReader(del.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("invalid webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "booklist-webhooks/1")
+	req.Header.Set(EventHeader, del.EventType)
+	req.Header.Set(DeliveryHeader, strconv.Itoa(int(del.ID)))
+	req.Header.Set(TimestampHeader, ts)
+	req.Header.Set(SignatureHeader, Sign(del.Secret, ts, del.Payload))
+
+	resp, err := d.client().Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response code: %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff doubles BaseBackoff for every attempt after the first, capped at MaxBackoff.
+func (d *Dispatcher) backoff(attempts int32) time.Duration {
+	base, ceiling := d.BaseBackoff, d.MaxBackoff
+	if base <= 0 {
+		base = defaultBaseBackoff
+	}
+	if ceiling <= 0 {
+		ceiling = defaultMaxBackoff
+	}
+	wait := base
+	for i := int32(1); i < attempts && wait < ceiling; i++ {
+		wait *= 2
+	}
+	return min(wait, ceiling)
+}
+
+func (d *Dispatcher) client() *http.Client {
+	if d.Client != nil {
+		return d.Client
+	}
+	return defaultClient
+}
+
+func (d *Dispatcher) clock() time.Time {
+	if d.now != nil {
+		return d.now()
+	}
+	return time.Now()
+}
+
+func (d *Dispatcher) batchSize() int32 {
+	if d.BatchSize > 0 {
+		return d.BatchSize
+	}
+	return defaultBatchSize
+}
+
+func (d *Dispatcher) maxAttempts() int32 {
+	if d.MaxAttempts > 0 {
+		return d.MaxAttempts
+	}
+	return defaultMaxAttempts
+}
+
+func (d *Dispatcher) lease() time.Duration {
+	if d.Lease > 0 {
+		return d.Lease
+	}
+	return defaultLease
+}
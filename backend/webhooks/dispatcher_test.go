@@ -0,0 +1,364 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dcrespo1/book-list-app/events"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+)
+
+// fakeStore is a test double for Store that hands out its queue once. Deliveries
+// whose ids are in expired have lost their lease, so recording them affects no row.
+type fakeStore struct {
+	mu        sync.Mutex
+	queue     []database.ClaimWebhookDeliveriesRow
+	claimArg  database.ClaimWebhookDeliveriesParams
+	claimErr  error
+	expired   map[int32]bool
+	succeeded []database.MarkWebhookDeliverySucceededParams
+	failed    []database.MarkWebhookDeliveryFailedParams
+}
+
+func (f *fakeStore) ClaimWebhookDeliveries(_ context.Context, arg database.ClaimWebhookDeliveriesParams) ([]database.ClaimWebhookDeliveriesRow, error) {
+	f.claimArg = arg
+	q := f.queue
+	f.queue = nil
+	return q, f.claimErr
+}
+
+func (f *fakeStore) MarkWebhookDeliverySucceeded(_ context.Context, arg database.MarkWebhookDeliverySucceededParams) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.expired[arg.ID] {
+		return 0, nil
+	}
+	f.succeeded = append(f.succeeded, arg)
+	return 1, nil
+}
+
+func (f *fakeStore) MarkWebhookDeliveryFailed(_ context.Context, arg database.MarkWebhookDeliveryFailedParams) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.expired[arg.ID] {
+		return 0, nil
+	}
+	f.failed = append(f.failed, arg)
+	return 1, nil
+}
+
+var fixedNow = time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// newDispatcher returns a Dispatcher whose client, like NewClient's, does not follow
+// redirects, but which may reach the loopback receivers these tests start.
+func newDispatcher(store Store) *Dispatcher {
+	return &Dispatcher{
+		Store:  store,
+		Client: newClient(func(netip.Addr) bool { return true }),
+		now:    func() time.Time { return fixedNow },
+	}
+}
+
+func TestDeliverDue_SignsAndPostsPayload(t *testing.T) {
+	payload := []byte(`{"type":"book.added","data":{"id":1}}`)
+	var got *http.Request
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	store := &fakeStore{queue: []database.ClaimWebhookDeliveriesRow{
+		{ID: 7, EventType: events.BookAdded, Payload: payload, LeasedUntil: fixedNow.Add(defaultLease), Url: srv.URL, Secret: "s3cret"},
+	}}
+	n, err := newDispatcher(store).DeliverDue(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("attempted: got %d, want 1", n)
+	}
+
+	if string(gotBody) != string(payload) {
+		t.Errorf("body: got %s, want %s", gotBody, payload)
+	}
+	ts := got.Header.Get(TimestampHeader)
+	if ts != "1780315200" {
+		t.Errorf("timestamp header: got %q", ts)
+	}
+	if want := Sign("s3cret", ts, payload); got.Header.Get(SignatureHeader) != want {
+		t.Errorf("signature: got %q, want %q", got.Header.Get(SignatureHeader), want)
+	}
+	if got.Header.Get(EventHeader) != events.BookAdded || got.Header.Get(DeliveryHeader) != "7" {
+		t.Errorf("unexpected event headers: %v", got.Header)
+	}
+
+	if len(store.succeeded) != 1 || store.succeeded[0].ID != 7 || store.succeeded[0].LastStatusCode.Int32 != http.StatusNoContent {
+		t.Errorf("succeeded: got %+v", store.succeeded)
+	}
+	if !store.succeeded[0].LeasedUntil.Equal(fixedNow.Add(defaultLease)) {
+		t.Errorf("leased until: got %v, want the claimed lease", store.succeeded[0].LeasedUntil)
+	}
+	if store.claimArg.LeaseSeconds != int32(defaultLease.Seconds()) {
+		t.Errorf("lease: got %ds, want %v", store.claimArg.LeaseSeconds, defaultLease)
+	}
+}
+
+func TestDeliverDue_SendsBatchConcurrently(t *testing.T) {
+	// Each receiver holds its response until both deliveries have arrived, which
+	// only happens if they are in flight at the same time.
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived.Done()
+		arrived.Wait()
+	}))
+	t.Cleanup(srv.Close)
+
+	store := &fakeStore{queue: []database.ClaimWebhookDeliveriesRow{
+		{ID: 1, Payload: []byte(`{}`), Url: srv.URL},
+		{ID: 2, Payload: []byte(`{}`), Url: srv.URL},
+	}}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := newDispatcher(store).DeliverDue(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.succeeded) != 2 {
+		t.Errorf("expected both deliveries to succeed, got %+v (failed %+v)", store.succeeded, store.failed)
+	}
+}
+
+func TestDeliverDue_ExpiredLeaseIsNotAnError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+
+	store := &fakeStore{
+		queue:   []database.ClaimWebhookDeliveriesRow{{ID: 1, Payload: []byte(`{}`), Url: srv.URL}},
+		expired: map[int32]bool{1: true},
+	}
+	if _, err := newDispatcher(store).DeliverDue(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.failed) != 0 {
+		t.Errorf("expected the stale result to be discarded, got %+v", store.failed)
+	}
+}
+
+func TestDeliverDue_ReceiverErrorSchedulesRetry(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusBadGateway)
+	}))
+	t.Cleanup(srv.Close)
+
+	store := &fakeStore{queue: []database.ClaimWebhookDeliveriesRow{
+		{ID: 1, Attempts: 2, Payload: []byte(`{}`), Url: srv.URL, Secret: "s"},
+	}}
+	d := newDispatcher(store)
+	if _, err := d.DeliverDue(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(store.failed) != 1 {
+		t.Fatalf("expected 1 failure recorded, got %d", len(store.failed))
+	}
+	f := store.failed[0]
+	if f.Status != StatusPending {
+		t.Errorf("status: got %q, want %q", f.Status, StatusPending)
+	}
+	if f.LastStatusCode.Int32 != http.StatusBadGateway {
+		t.Errorf("last status code: got %d", f.LastStatusCode.Int32)
+	}
+	// Third attempt: 30s * 2^2.
+	if want := fixedNow.Add(2 * time.Minute); !f.NextAttemptAt.Equal(want) {
+		t.Errorf("next attempt: got %v, want %v", f.NextAttemptAt, want)
+	}
+}
+
+func TestDeliverDue_GivesUpAfterMaxAttempts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+
+	store := &fakeStore{queue: []database.ClaimWebhookDeliveriesRow{
+		{ID: 1, Attempts: 2, Payload: []byte(`{}`), Url: srv.URL},
+	}}
+	d := newDispatcher(store)
+	d.MaxAttempts = 3
+	d.DeliverDue(context.Background())
+
+	if len(store.failed) != 1 || store.failed[0].Status != StatusFailed {
+		t.Errorf("expected delivery to be marked failed, got %+v", store.failed)
+	}
+}
+
+func TestDeliverDue_UnreachableReceiver(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	store := &fakeStore{queue: []database.ClaimWebhookDeliveriesRow{{ID: 1, Payload: []byte(`{}`), Url: url}}}
+	newDispatcher(store).DeliverDue(context.Background())
+
+	if len(store.failed) != 1 {
+		t.Fatalf("expected 1 failure recorded, got %d", len(store.failed))
+	}
+	if store.failed[0].LastStatusCode.Valid {
+		t.Error("expected no status code for a connection error")
+	}
+}
+
+func TestDeliverDue_ErrorOmitsResponseBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "internal-only detail", http.StatusForbidden)
+	}))
+	t.Cleanup(srv.Close)
+
+	store := &fakeStore{queue: []database.ClaimWebhookDeliveriesRow{{ID: 1, Payload: []byte(`{}`), Url: srv.URL}}}
+	newDispatcher(store).DeliverDue(context.Background())
+
+	if len(store.failed) != 1 {
+		t.Fatalf("expected 1 failure recorded, got %d", len(store.failed))
+	}
+	if got := store.failed[0].LastError.String; strings.Contains(got, "internal-only") || !strings.Contains(got, "403") {
+		t.Errorf("last error: got %q, want the status code only", got)
+	}
+}
+
+func TestDeliverDue_DoesNotFollowRedirects(t *testing.T) {
+	followed := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/internal" {
+			followed = true
+			return
+		}
+		http.Redirect(w, r, "/internal", http.StatusTemporaryRedirect)
+	}))
+	t.Cleanup(srv.Close)
+
+	store := &fakeStore{queue: []database.ClaimWebhookDeliveriesRow{{ID: 1, Payload: []byte(`{}`), Url: srv.URL}}}
+	newDispatcher(store).DeliverDue(context.Background())
+
+	if followed {
+		t.Error("redirect was followed")
+	}
+	if len(store.failed) != 1 || store.failed[0].LastStatusCode.Int32 != http.StatusTemporaryRedirect {
+		t.Errorf("expected a failure with status 307, got %+v", store.failed)
+	}
+}
+
+func TestDeliverDue_RefusesLocalReceivers(t *testing.T) {
+	reached := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+	t.Cleanup(srv.Close)
+
+	store := &fakeStore{queue: []database.ClaimWebhookDeliveriesRow{{ID: 1, Payload: []byte(`{}`), Url: srv.URL}}}
+	d := newDispatcher(store)
+	d.Client = nil // the default client
+	d.DeliverDue(context.Background())
+
+	if reached {
+		t.Error("delivery reached a loopback receiver")
+	}
+	if len(store.failed) != 1 || store.failed[0].LastStatusCode.Valid {
+		t.Errorf("expected a connection failure, got %+v", store.failed)
+	}
+}
+
+func TestPublicAddr(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"93.184.216.34":      true,
+		"2606:2800:220:1::1": true,
+		"127.0.0.1":          false,
+		"::1":                false,
+		"10.1.2.3":           false,
+		"172.16.0.1":         false,
+		"192.168.1.1":        false,
+		"169.254.169.254":    false,
+		"fe80::1":            false,
+		"fd00:ec2::254":      false,
+		"100.64.0.1":         false,
+		"0.0.0.0":            false,
+		"::":                 false,
+		"224.0.0.1":          false,
+		"::ffff:127.0.0.1":   false,
+		"64:ff9b::a00:1":     false,
+		"2002:a00:1::1":      false,
+		"255.255.255.255":    false,
+	} {
+		if got := PublicAddr(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("PublicAddr(%s): got %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestDeliverDue_ClaimError(t *testing.T) {
+	store := &fakeStore{claimErr: errors.New("db down")}
+	if _, err := newDispatcher(store).DeliverDue(context.Background()); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestBackoff_IsCapped(t *testing.T) {
+	d := &Dispatcher{BaseBackoff: time.Second, MaxBackoff: 10 * time.Second}
+	cases := map[int32]time.Duration{1: time.Second, 2: 2 * time.Second, 4: 8 * time.Second, 5: 10 * time.Second, 30: 10 * time.Second}
+	for attempts, want := range cases {
+		if got := d.backoff(attempts); got != want {
+			t.Errorf("backoff(%d): got %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+// fakeEnqueuer records what Publisher queues.
+type fakeEnqueuer struct {
+	arg database.EnqueueWebhookDeliveriesParams
+}
+
+func (f *fakeEnqueuer) EnqueueWebhookDeliveries(_ context.Context, arg database.EnqueueWebhookDeliveriesParams) (int64, error) {
+	f.arg = arg
+	return 1, nil
+}
+
+func TestPublisher_EnqueuesEvent(t *testing.T) {
+	q := &fakeEnqueuer{}
+	p := &Publisher{Queries: q}
+
+	err := p.Publish(context.Background(), events.Event{
+		Type:       events.BookDeleted,
+		UserID:     "user-1",
+		OccurredAt: fixedNow,
+		Data:       map[string]int{"id": 3},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if q.arg.UserID != "user-1" || q.arg.EventType != events.BookDeleted {
+		t.Errorf("unexpected params: %+v", q.arg)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(q.arg.Payload, &payload); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if payload["type"] != events.BookDeleted {
+		t.Errorf("payload type: got %v", payload["type"])
+	}
+	if _, leaked := payload["UserID"]; leaked {
+		t.Error("user id should not be part of the payload")
+	}
+}
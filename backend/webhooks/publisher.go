@@ -0,0 +1,38 @@
+// Package webhooks delivers readlist events to user-registered HTTP endpoints.
+// Publisher writes deliveries to a Postgres-backed queue; Dispatcher drains it.
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dcrespo1/book-list-app/events"
+	"github.com/dcrespo1/book-list-app/pkg/database"
+)
+
+// Enqueuer is the persistence interface used by Publisher. *database.Queries satisfies it.
+type Enqueuer interface {
+	EnqueueWebhookDeliveries(ctx context.Context, arg database.EnqueueWebhookDeliveriesParams) (int64, error)
+}
+
+// Publisher implements events.Publisher by queuing one delivery per matching subscription.
+type Publisher struct {
+	Queries Enqueuer
+}
+
+func (p *Publisher) Publish(ctx context.Context, e events.Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	_, err = p.Queries.EnqueueWebhookDeliveries(ctx, database.EnqueueWebhookDeliveriesParams{
+		EventType: e.Type,
+		Payload:   payload,
+		UserID:    e.UserID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+	return nil
+}
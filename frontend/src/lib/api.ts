@@ -1,9 +1,12 @@
 import { keycloak } from './auth';
 import { PUBLIC_API_URL } from '$env/static/public';
 
+// Paths passed to api are relative to this API version.
+const API_VERSION = '/v1';
+
 async function request(path: string, options: RequestInit = {}): Promise<Response> {
 	await keycloak.updateToken(30);
-	return fetch(`${PUBLIC_API_URL}${path}`, {
+	return fetch(`${PUBLIC_API_URL}${API_VERSION}${path}`, {
 		...options,
 		headers: {
 			'Content-Type': 'application/json',